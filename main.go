@@ -14,7 +14,10 @@ import (
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/wujie1993/waves/pkg/admission"
+	"github.com/wujie1993/waves/pkg/auth"
 	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/encryption"
 	"github.com/wujie1993/waves/pkg/loader"
 	"github.com/wujie1993/waves/pkg/operators"
 	"github.com/wujie1993/waves/pkg/orm"
@@ -36,12 +39,25 @@ func init() {
 	})
 	log.SetReportCaller(true)
 
+	// 开启敏感字段加密
+	if err := encryption.Setup(); err != nil {
+		log.Fatal(err)
+	}
+
 	// 初始化数据库连接
 	db.InitKV()
 
 	// 初始化底层数据
 	orm.InitStorage()
 
+	// 开启基于HTTP钩子的准入控制
+	admission.Setup()
+
+	// 初始化令牌密钥与初始管理员
+	if err := auth.Setup(); err != nil {
+		log.Fatal(err)
+	}
+
 	// 加载应用
 	loadApps()
 }
@@ -83,6 +99,12 @@ func loadPlugins(ctx context.Context) {
 
 	appInstanceOperator := operators.NewAppInstanceOperator()
 	go appInstanceOperator.Run(ctx)
+
+	garbageCollector := operators.NewGarbageCollector()
+	go garbageCollector.Run(ctx)
+
+	// 启动各控制器共享的资源对象缓存
+	orm.GetHelper().Informers.Start(ctx)
 }
 
 // @title Golang Gin API
@@ -133,9 +155,30 @@ func loadPlugins(ctx context.Context) {
 // @tag.name Revision
 // @tag.description 修订历史
 
+// @tag.name AdmissionConfig
+// @tag.description 准入控制配置
+
+// @tag.name User
+// @tag.description 用户
+
+// @tag.name Role
+// @tag.description 角色
+
+// @tag.name RoleBinding
+// @tag.description 角色绑定
+
+// @tag.name ServiceAccount
+// @tag.description 服务账号
+
+// @tag.name Auth
+// @tag.description 认证
+
 // @tag.name Topology
 // @tag.description 拓扑
 
+// @tag.name Admin
+// @tag.description 运维管理
+
 func main() {
 	flag.Parse()
 
@@ -0,0 +1,203 @@
+package admission
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/patch"
+)
+
+// Request 发送至准入控制钩子的审查请求
+type Request struct {
+	// 请求的唯一标识，响应中需原样返回
+	Uid        string
+	Operation  string
+	Kind       string
+	ApiVersion string
+	Namespace  string
+	Name       string
+	// 待写入的资源对象，删除时为空
+	Object json.RawMessage `json:",omitempty"`
+	// 写入前的资源对象，创建时为空
+	OldObject json.RawMessage `json:",omitempty"`
+}
+
+// Response 准入控制钩子返回的审查结果
+type Response struct {
+	Uid     string
+	Allowed bool
+	// 拒绝请求的原因
+	Reason string
+	// 应用于待写入对象的JSON补丁(RFC 6902)，仅在允许请求时生效
+	Patch json.RawMessage
+}
+
+// Review 准入控制钩子的请求与响应体
+type Review struct {
+	Request  *Request  `json:",omitempty"`
+	Response *Response `json:",omitempty"`
+}
+
+// WebhookAdmitter 根据准入控制配置调用HTTP钩子，实现了registry.Admitter接口。
+// 匹配的钩子按配置顺序依次调用，后调用的钩子接收到的是已被之前的钩子修改后的对象
+type WebhookAdmitter struct {
+	admissionConfigRegistry *v1.AdmissionConfigRegistry
+	client                  *http.Client
+}
+
+// Admit 调用所有匹配的准入控制钩子，任一钩子拒绝时返回拒绝错误
+func (a WebhookAdmitter) Admit(ctx context.Context, attrs registry.AdmissionAttributes) ([]byte, error) {
+	// 准入控制配置本身不经过准入控制，避免错误的钩子导致配置无法修复
+	if attrs.Kind == core.KindAdmissionConfig {
+		return nil, nil
+	}
+
+	objs, err := a.admissionConfigRegistry.List(ctx, "")
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	patched := false
+	for _, obj := range objs {
+		admissionConfig := obj.(*v1.AdmissionConfig)
+		for _, webhook := range admissionConfig.Spec.Webhooks {
+			if !matches(webhook, attrs) {
+				continue
+			}
+			object, err := a.call(ctx, webhook, attrs)
+			if err != nil {
+				if _, ok := err.(e.AdmissionDeniedError); ok || webhook.FailurePolicy != core.AdmissionFailurePolicyIgnore {
+					return nil, err
+				}
+				log.Warnf("ignore failure of admission webhook %s: %s", webhook.Name, err)
+				continue
+			}
+			if object != nil {
+				attrs.Object = object
+				patched = true
+			}
+		}
+	}
+	if !patched {
+		return nil, nil
+	}
+	return attrs.Object, nil
+}
+
+// call 调用单个准入控制钩子，返回修改后的对象，未修改时返回空
+func (a WebhookAdmitter) call(ctx context.Context, webhook v1.AdmissionWebhook, attrs registry.AdmissionAttributes) ([]byte, error) {
+	uid := uuid.New().String()
+	body, err := json.Marshal(Review{
+		Request: &Request{
+			Uid:        uid,
+			Operation:  attrs.Operation,
+			Kind:       attrs.Kind,
+			ApiVersion: attrs.ApiVersion,
+			Namespace:  attrs.Namespace,
+			Name:       attrs.Name,
+			Object:     attrs.Object,
+			OldObject:  attrs.OldObject,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	timeoutSeconds := webhook.TimeoutSeconds
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = core.AdmissionDefaultTimeoutSeconds
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(timeoutCtx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: err.Error()}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: fmt.Sprintf("unexpected status code %d", resp.StatusCode)}
+	}
+	review := Review{}
+	if err := json.Unmarshal(data, &review); err != nil {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: err.Error()}
+	}
+	if review.Response == nil {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: "response is empty"}
+	}
+	if review.Response.Uid != "" && review.Response.Uid != uid {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: "uid of response does not match with request"}
+	}
+	if !review.Response.Allowed {
+		return nil, e.AdmissionDeniedError{Webhook: webhook.Name, Reason: review.Response.Reason}
+	}
+
+	if len(review.Response.Patch) == 0 || string(review.Response.Patch) == "null" {
+		return nil, nil
+	}
+	if attrs.Object == nil {
+		// 删除操作没有可修改的对象
+		return nil, nil
+	}
+	object, err := patch.JSONPatch(attrs.Object, review.Response.Patch)
+	if err != nil {
+		return nil, e.AdmissionWebhookError{Webhook: webhook.Name, Reason: "invalid patch: " + err.Error()}
+	}
+	return object, nil
+}
+
+// matches 判断钩子是否匹配请求的资源类型、操作与命名空间
+func matches(webhook v1.AdmissionWebhook, attrs registry.AdmissionAttributes) bool {
+	return matchesAny(webhook.Kinds, attrs.Kind) &&
+		matchesAny(webhook.Operations, attrs.Operation) &&
+		matchesAny(webhook.Namespaces, attrs.Namespace)
+}
+
+// matchesAny 判断值是否在规则列表中，规则列表为空或包含*时匹配所有值
+func matchesAny(rules []string, value string) bool {
+	if len(rules) == 0 {
+		return true
+	}
+	for _, rule := range rules {
+		if rule == "*" || rule == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebhookAdmitter 实例化HTTP钩子准入控制器
+func NewWebhookAdmitter() *WebhookAdmitter {
+	return &WebhookAdmitter{
+		admissionConfigRegistry: v1.NewAdmissionConfigRegistry(),
+		client:                  &http.Client{},
+	}
+}
+
+// Setup 开启基于HTTP钩子的准入控制
+func Setup() {
+	registry.SetAdmitter(NewWebhookAdmitter())
+}
@@ -0,0 +1,78 @@
+package admission_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/admission"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	// 注册资源对象的实例化与转换方法
+	_ "github.com/wujie1993/waves/pkg/orm"
+)
+
+func TestRegistryAdmission(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	// 拒绝prod命名空间中的请求，其余请求添加owner标签
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		review := admission.Review{}
+		if err := json.NewDecoder(req.Body).Decode(&review); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		response := &admission.Response{Uid: review.Request.Uid, Allowed: true}
+		if review.Request.Namespace == "prod" {
+			response.Allowed = false
+			response.Reason = "prod is frozen"
+		} else {
+			response.Patch = json.RawMessage(`[{"op":"add","path":"/Metadata/Labels","value":{"owner":"platform"}}]`)
+		}
+		json.NewEncoder(w).Encode(admission.Review{Response: response})
+	}))
+	defer server.Close()
+
+	registry.SetAdmitter(admission.NewWebhookAdmitter())
+	defer registry.SetAdmitter(nil)
+
+	admissionConfig := v1.NewAdmissionConfig()
+	admissionConfig.Metadata.Name = "policy"
+	admissionConfig.Spec.Webhooks = []v1.AdmissionWebhook{{
+		Name:       "policy",
+		URL:        server.URL,
+		Kinds:      []string{core.KindConfigMap},
+		Operations: []string{core.AdmissionOperationCreate},
+	}}
+	if _, err := v1.NewAdmissionConfigRegistry().Create(context.TODO(), admissionConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	configMap := v1.NewConfigMap()
+	configMap.Metadata.Namespace = "prod"
+	configMap.Metadata.Name = "test"
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err == nil {
+		t.Fatal("create in prod should be denied")
+	} else if _, ok := err.(e.AdmissionDeniedError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	configMap = v1.NewConfigMap()
+	configMap.Metadata.Namespace = "default"
+	configMap.Metadata.Name = "test"
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := configMapRegistry.Get(context.TODO(), "default", "test")
+	if err != nil {
+		t.Fatal(err)
+	} else if obj.GetMetadata().Labels["owner"] != "platform" {
+		t.Fatalf("unexpected labels: %v", obj.GetMetadata().Labels)
+	}
+}
@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/setting"
+	"github.com/wujie1993/waves/pkg/util"
+)
+
+const (
+	// 认证通过后在请求上下文中保存用户名的键
+	ContextKeyUsername = "username"
+	// 认证通过后在请求上下文中保存令牌授权范围的键，仅服务账号令牌设置了授权范围时存在
+	ContextKeyScopes = "scopes"
+
+	// 初始管理员用户名
+	AdminUsername = "admin"
+
+	defaultAccessTokenExpire  = 2 * time.Hour
+	defaultRefreshTokenExpire = 7 * 24 * time.Hour
+)
+
+// TokenPair 登录或刷新令牌时签发的访问令牌与刷新令牌
+type TokenPair struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// Identity 令牌所属的身份
+type Identity struct {
+	// 用户名，服务账号为system:serviceaccount:<服务账号名称>
+	Username string
+	// 令牌的授权范围，为空时不限制
+	Scopes []v1.PolicyRule
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `binding:"required"`
+	Password string `binding:"required"`
+}
+
+// RefreshRequest 刷新令牌请求
+type RefreshRequest struct {
+	RefreshToken string `binding:"required"`
+}
+
+// Setup 初始化令牌密钥，并在不存在任何用户时创建初始管理员及其角色
+func Setup() error {
+	if setting.AuthSetting.Enabled && setting.AppSetting.JwtSecret == "" {
+		return e.Errorf("JwtSecret is required when auth is enabled")
+	}
+	util.Setup()
+	return initAdmin()
+}
+
+// initAdmin 创建初始管理员，未配置密码时随机生成
+func initAdmin() error {
+	userRegistry := v1.NewUserRegistry()
+	users, err := userRegistry.List(context.TODO(), "")
+	if err != nil {
+		log.Error(err)
+		return err
+	} else if len(users) > 0 {
+		return initAdminRole()
+	}
+
+	password := setting.AuthSetting.AdminPassword
+	if password == "" {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			log.Error(err)
+			return err
+		}
+		password = hex.EncodeToString(buf)
+		log.Warnf("created initial user %s with random password %s, please change it after login", AdminUsername, password)
+	}
+
+	user := v1.NewUser()
+	user.Metadata.Name = AdminUsername
+	user.Spec.Password = password
+	if _, err := userRegistry.Create(context.TODO(), user); err != nil {
+		log.Error(err)
+		return err
+	}
+	return initAdminRole()
+}
+
+// initAdminRole 确保存在拥有所有权限的admin角色及其与admin用户的角色绑定，被删除后会在服务启动时重新创建
+func initAdminRole() error {
+	roleRegistry := v1.NewRoleRegistry()
+	roleObj, err := roleRegistry.Get(context.TODO(), "", AdminUsername)
+	if err != nil {
+		log.Error(err)
+		return err
+	} else if roleObj == nil {
+		role := v1.NewRole()
+		role.Metadata.Name = AdminUsername
+		role.Spec.Rules = []v1.PolicyRule{{Verbs: []string{"*"}, Kinds: []string{"*"}}}
+		if _, err := roleRegistry.Create(context.TODO(), role); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+
+	roleBindingRegistry := v1.NewRoleBindingRegistry()
+	roleBindingObj, err := roleBindingRegistry.Get(context.TODO(), "", AdminUsername)
+	if err != nil {
+		log.Error(err)
+		return err
+	} else if roleBindingObj == nil {
+		roleBinding := v1.NewRoleBinding()
+		roleBinding.Metadata.Name = AdminUsername
+		roleBinding.Spec.RoleRef = AdminUsername
+		roleBinding.Spec.Subjects = []v1.Subject{{Kind: core.SubjectKindUser, Name: AdminUsername}}
+		if _, err := roleBindingRegistry.Create(context.TODO(), roleBinding); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Login 校验用户名与密码，通过后签发令牌
+func Login(username string, password string) (*TokenPair, error) {
+	user, err := getUser(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.CheckPassword(password) {
+		return nil, e.UnauthorizedError{Reason: "用户名或密码错误"}
+	}
+	if user.Spec.Disabled {
+		return nil, e.UnauthorizedError{Reason: "用户已被禁用"}
+	}
+	return issueTokens(user)
+}
+
+// Refresh 使用刷新令牌换取新的令牌
+func Refresh(refreshToken string) (*TokenPair, error) {
+	user, err := verifyToken(refreshToken, util.TokenTypeRefresh)
+	if err != nil {
+		return nil, err
+	}
+	return issueTokens(user)
+}
+
+// Authenticate 校验用户的访问令牌或服务账号令牌，返回令牌所属的身份
+func Authenticate(token string) (*Identity, error) {
+	if strings.HasPrefix(token, ServiceAccountTokenPrefix) {
+		return authenticateServiceAccount(token)
+	}
+	user, err := verifyToken(token, util.TokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{Username: user.Metadata.Name}, nil
+}
+
+// Logout 使用户已签发的所有令牌失效
+func Logout(username string) error {
+	user, err := getUser(username)
+	if err != nil {
+		return err
+	} else if user == nil {
+		return e.ResourceNotFoundError{Key: username}
+	}
+	user.Info.TokenGeneration++
+	if _, err := v1.NewUserRegistry().Update(context.TODO(), user, core.WithAllFields()); err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+// verifyToken 校验令牌的签名、有效期与类型，以及所属用户是否存在、启用且未登出
+func verifyToken(token string, tokenType string) (*v1.User, error) {
+	claims, err := util.ParseToken(token)
+	if err != nil {
+		return nil, e.UnauthorizedError{Reason: err.Error()}
+	}
+	if claims.Type != tokenType {
+		return nil, e.UnauthorizedError{Reason: "令牌类型错误"}
+	}
+
+	user, err := getUser(claims.Username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, e.UnauthorizedError{Reason: "用户不存在"}
+	}
+	if user.Spec.Disabled {
+		return nil, e.UnauthorizedError{Reason: "用户已被禁用"}
+	}
+	if claims.Generation != user.Info.TokenGeneration {
+		return nil, e.UnauthorizedError{Reason: "令牌已失效"}
+	}
+	return user, nil
+}
+
+func issueTokens(user *v1.User) (*TokenPair, error) {
+	accessExpire := setting.AuthSetting.AccessTokenExpire
+	if accessExpire == 0 {
+		accessExpire = defaultAccessTokenExpire
+	}
+	refreshExpire := setting.AuthSetting.RefreshTokenExpire
+	if refreshExpire == 0 {
+		refreshExpire = defaultRefreshTokenExpire
+	}
+
+	var tokens TokenPair
+	var err error
+	tokens.AccessToken, tokens.AccessTokenExpiresAt, err = util.GenerateToken(user.Metadata.Name, util.TokenTypeAccess, user.Info.TokenGeneration, accessExpire)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	tokens.RefreshToken, tokens.RefreshTokenExpiresAt, err = util.GenerateToken(user.Metadata.Name, util.TokenTypeRefresh, user.Info.TokenGeneration, refreshExpire)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return &tokens, nil
+}
+
+// getUser 获取包含密码哈希值的用户，名称不合法的用户视为不存在
+func getUser(username string) (*v1.User, error) {
+	obj, err := v1.NewUserRegistry().Get(context.TODO(), "", username, core.WithoutDecorate())
+	if _, ok := err.(e.InvalidNameError); ok {
+		return nil, nil
+	} else if err != nil {
+		log.Error(err)
+		return nil, err
+	} else if obj == nil {
+		return nil, nil
+	}
+	return obj.(*v1.User), nil
+}
@@ -0,0 +1,186 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/setting"
+	"golang.org/x/crypto/bcrypt"
+	// 注册资源对象的实例化与转换方法
+	_ "github.com/wujie1993/waves/pkg/orm"
+)
+
+func TestUserAuth(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	setting.AppSetting.JwtSecret = "test"
+	setting.AuthSetting.AdminPassword = "admin-secret"
+	defer func() {
+		setting.AppSetting.JwtSecret = ""
+		setting.AuthSetting.AdminPassword = ""
+	}()
+	if err := auth.Setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 密码以bcrypt哈希值存储，获取时隐藏哈希值，更新时未设置密码则保留原有密码
+	userRegistry := v1.NewUserRegistry()
+	obj, err := userRegistry.Get(context.TODO(), "", auth.AdminUsername, core.WithoutDecorate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := obj.(*v1.User)
+	if admin.Spec.Password == "admin-secret" || !admin.CheckPassword("admin-secret") {
+		t.Fatalf("password of initial user is not hashed: %s", admin.Spec.Password)
+	}
+	obj, err = userRegistry.Get(context.TODO(), "", auth.AdminUsername)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.(*v1.User).Spec.Password != "" {
+		t.Fatal("password hash should be hidden when getting user")
+	}
+	objs, err := userRegistry.List(context.TODO(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, obj := range objs {
+		if obj.(*v1.User).Spec.Password != "" {
+			t.Fatal("password hash should be hidden when listing users")
+		}
+	}
+	if _, err := userRegistry.Update(context.TODO(), obj); err != nil {
+		t.Fatal(err)
+	}
+	obj, err = userRegistry.Get(context.TODO(), "", auth.AdminUsername, core.WithoutDecorate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.(*v1.User).Spec.Password != admin.Spec.Password {
+		t.Fatal("password should be kept when updating without password")
+	}
+	user := v1.NewUser()
+	user.Metadata.Name = "nopass"
+	if _, err := userRegistry.Create(context.TODO(), user); err == nil {
+		t.Fatal("expected error when creating user without password")
+	}
+
+	if _, err := auth.Login(auth.AdminUsername, "wrong"); err == nil {
+		t.Fatal("expected login failure with wrong password")
+	} else if _, ok := err.(e.UnauthorizedError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tokens, err := auth.Login(auth.AdminUsername, "admin-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(tokens.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	// 刷新令牌不能用于访问接口
+	if _, err := auth.Authenticate(tokens.RefreshToken); err == nil {
+		t.Fatal("refresh token should not be accepted as access token")
+	}
+	refreshed, err := auth.Refresh(tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 登出后已签发的令牌全部失效
+	if err := auth.Logout(auth.AdminUsername); err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range []string{tokens.AccessToken, refreshed.AccessToken} {
+		if _, err := auth.Authenticate(token); err == nil {
+			t.Fatal("token should be revoked after logout")
+		}
+	}
+	if _, err := auth.Refresh(refreshed.RefreshToken); err == nil {
+		t.Fatal("refresh token should be revoked after logout")
+	}
+}
+
+func TestUserPassword(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	setting.AppSetting.JwtSecret = "test"
+	setting.AuthSetting.AdminPassword = "admin-secret"
+	defer func() {
+		setting.AppSetting.JwtSecret = ""
+		setting.AuthSetting.AdminPassword = ""
+	}()
+	if err := auth.Setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	userRegistry := v1.NewUserRegistry()
+	obj, err := userRegistry.Get(context.TODO(), "", auth.AdminUsername, core.WithoutDecorate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := obj.(*v1.User).Spec.Password
+
+	// 不能写入客户端提供的bcrypt哈希值，避免绕过密码设置
+	forged, err := bcrypt.GenerateFromPassword([]byte("forged"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update := obj.DeepCopyApiObject().(*v1.User)
+	update.Spec.Password = string(forged)
+	if _, err := userRegistry.Update(context.TODO(), update); err == nil {
+		t.Fatal("expected error when updating password with bcrypt hash")
+	} else if _, ok := err.(e.InvalidFieldError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	user := v1.NewUser()
+	user.Metadata.Name = "forged"
+	user.Spec.Password = string(forged)
+	if _, err := userRegistry.Create(context.TODO(), user); err == nil {
+		t.Fatal("expected error when creating user with bcrypt hash")
+	}
+	// 原样写回存储中的哈希值不视为修改密码
+	update.Spec.Password = hash
+	if _, err := userRegistry.Update(context.TODO(), update); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := auth.Login(auth.AdminUsername, "admin-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(tokens.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+
+	// 修改密码后已签发的令牌全部失效，并且只能使用新密码登录
+	obj, err = userRegistry.Get(context.TODO(), "", auth.AdminUsername)
+	if err != nil {
+		t.Fatal(err)
+	}
+	update = obj.(*v1.User)
+	update.Spec.Password = "new-secret"
+	if _, err := userRegistry.Update(context.TODO(), update); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(tokens.AccessToken); err == nil {
+		t.Fatal("token should be revoked after password change")
+	}
+	if _, err := auth.Refresh(tokens.RefreshToken); err == nil {
+		t.Fatal("refresh token should be revoked after password change")
+	}
+	if _, err := auth.Login(auth.AdminUsername, "admin-secret"); err == nil {
+		t.Fatal("expected login failure with old password")
+	}
+	tokens, err = auth.Login(auth.AdminUsername, "new-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(tokens.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+const (
+	// 服务账号令牌的前缀，用于与用户的JWT访问令牌区分，令牌格式为wsa_<服务账号名称>:<随机串>
+	ServiceAccountTokenPrefix = "wsa_"
+
+	// 服务账号在请求上下文中的用户名前缀，名称中不允许出现冒号，因此不会与用户名冲突
+	ServiceAccountUsernamePrefix = "system:serviceaccount:"
+
+	// 令牌最近使用时间的最小记录间隔，避免每次请求都写入存储
+	tokenLastUsedInterval = time.Minute
+)
+
+// ServiceAccountTokenRequest 创建服务账号令牌请求
+type ServiceAccountTokenRequest struct {
+	// 令牌名称，在服务账号内唯一
+	Name string `binding:"required"`
+	// 有效时长，如720h，为空时永不过期
+	ExpiresIn string
+	// 令牌的授权范围，为空时拥有服务账号的全部权限
+	Scopes []v1.PolicyRule
+}
+
+// ServiceAccountTokenResponse 创建的服务账号令牌，令牌明文只在创建时返回一次
+type ServiceAccountTokenResponse struct {
+	Name       string
+	Token      string
+	ExpireTime time.Time
+}
+
+// ServiceAccountUsername 获取服务账号在请求上下文中的用户名
+func ServiceAccountUsername(name string) string {
+	return ServiceAccountUsernamePrefix + name
+}
+
+// ParseServiceAccountUsername 从请求上下文中的用户名解析服务账号名称，不是服务账号时返回false
+func ParseServiceAccountUsername(username string) (string, bool) {
+	if !strings.HasPrefix(username, ServiceAccountUsernamePrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(username, ServiceAccountUsernamePrefix), true
+}
+
+// CreateServiceAccountToken 为服务账号创建令牌，存储令牌的哈希值并返回令牌明文
+func CreateServiceAccountToken(name string, req ServiceAccountTokenRequest) (*ServiceAccountTokenResponse, error) {
+	serviceAccount, err := getServiceAccount(name)
+	if err != nil {
+		return nil, err
+	} else if serviceAccount == nil {
+		return nil, e.ResourceNotFoundError{Key: name}
+	}
+	if serviceAccount.GetToken(req.Name) != nil {
+		return nil, e.InvalidFieldError{Field: "Name", Reason: fmt.Sprintf("令牌名称 %s 已存在", req.Name)}
+	}
+
+	var expireTime time.Time
+	if req.ExpiresIn != "" {
+		expiresIn, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || expiresIn <= 0 {
+			return nil, e.InvalidFieldError{Field: "ExpiresIn", Reason: "有效时长格式错误，如720h"}
+		}
+		expireTime = time.Now().Add(expiresIn)
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	token := ServiceAccountTokenPrefix + name + ":" + hex.EncodeToString(buf)
+
+	serviceAccount.Spec.Tokens = append(serviceAccount.Spec.Tokens, v1.ServiceAccountToken{
+		Name:       req.Name,
+		Hash:       v1.HashServiceAccountToken(token),
+		ExpireTime: expireTime,
+		Scopes:     req.Scopes,
+	})
+	if _, err := v1.NewServiceAccountRegistry().Update(context.TODO(), serviceAccount); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return &ServiceAccountTokenResponse{
+		Name:       req.Name,
+		Token:      token,
+		ExpireTime: expireTime,
+	}, nil
+}
+
+// RevokeServiceAccountToken 吊销服务账号的令牌
+func RevokeServiceAccountToken(name string, tokenName string) error {
+	serviceAccount, err := getServiceAccount(name)
+	if err != nil {
+		return err
+	} else if serviceAccount == nil {
+		return e.ResourceNotFoundError{Key: name}
+	}
+
+	tokens := []v1.ServiceAccountToken{}
+	for _, token := range serviceAccount.Spec.Tokens {
+		if token.Name != tokenName {
+			tokens = append(tokens, token)
+		}
+	}
+	if len(tokens) == len(serviceAccount.Spec.Tokens) {
+		return e.ResourceNotFoundError{Key: name + "/" + tokenName}
+	}
+	serviceAccount.Spec.Tokens = tokens
+	delete(serviceAccount.Info.TokensLastUsed, tokenName)
+	if _, err := v1.NewServiceAccountRegistry().Update(context.TODO(), serviceAccount, core.WithAllFields()); err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+// authenticateServiceAccount 校验服务账号令牌，通过后记录令牌的最近使用时间
+func authenticateServiceAccount(token string) (*Identity, error) {
+	parts := strings.SplitN(strings.TrimPrefix(token, ServiceAccountTokenPrefix), ":", 2)
+	if len(parts) != 2 {
+		return nil, e.UnauthorizedError{Reason: "令牌格式错误"}
+	}
+	serviceAccount, err := getServiceAccount(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if serviceAccount == nil {
+		return nil, e.UnauthorizedError{Reason: "服务账号不存在"}
+	}
+	if serviceAccount.Spec.Disabled {
+		return nil, e.UnauthorizedError{Reason: "服务账号已被禁用"}
+	}
+
+	hash := v1.HashServiceAccountToken(token)
+	for _, t := range serviceAccount.Spec.Tokens {
+		if subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hash)) != 1 {
+			continue
+		}
+		if !t.ExpireTime.IsZero() && time.Now().After(t.ExpireTime) {
+			return nil, e.UnauthorizedError{Reason: "令牌已过期"}
+		}
+		recordTokenLastUsed(serviceAccount, t.Name)
+		return &Identity{
+			Username: ServiceAccountUsername(serviceAccount.Metadata.Name),
+			Scopes:   t.Scopes,
+		}, nil
+	}
+	return nil, e.UnauthorizedError{Reason: "令牌已失效"}
+}
+
+// recordTokenLastUsed 记录令牌的最近使用时间，记录失败不影响认证结果
+func recordTokenLastUsed(serviceAccount *v1.ServiceAccount, tokenName string) {
+	now := time.Now()
+	if lastUsed, ok := serviceAccount.Info.TokensLastUsed[tokenName]; ok && now.Sub(lastUsed) < tokenLastUsedInterval {
+		return
+	}
+	if serviceAccount.Info.TokensLastUsed == nil {
+		serviceAccount.Info.TokensLastUsed = make(map[string]time.Time)
+	}
+	serviceAccount.Info.TokensLastUsed[tokenName] = now
+	if _, err := v1.NewServiceAccountRegistry().Update(context.TODO(), serviceAccount, core.WithAllFields()); err != nil {
+		log.Warn(err)
+	}
+}
+
+// getServiceAccount 获取服务账号，名称不合法的服务账号视为不存在
+func getServiceAccount(name string) (*v1.ServiceAccount, error) {
+	obj, err := v1.NewServiceAccountRegistry().Get(context.TODO(), "", name)
+	if _, ok := err.(e.InvalidNameError); ok {
+		return nil, nil
+	} else if err != nil {
+		log.Error(err)
+		return nil, err
+	} else if obj == nil {
+		return nil, nil
+	}
+	return obj.(*v1.ServiceAccount), nil
+}
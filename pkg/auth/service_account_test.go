@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/rbac"
+)
+
+func TestServiceAccountToken(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	serviceAccountRegistry := v1.NewServiceAccountRegistry()
+	serviceAccount := v1.NewServiceAccount()
+	serviceAccount.Metadata.Name = "ci"
+	if _, err := serviceAccountRegistry.Create(context.TODO(), serviceAccount); err != nil {
+		t.Fatal(err)
+	}
+
+	// 令牌只存储哈希值，使用后记录最近使用时间
+	scopes := []v1.PolicyRule{{Verbs: []string{core.VerbUpdate, core.VerbAction}, Kinds: []string{core.KindAppInstance}}}
+	created, err := auth.CreateServiceAccountToken("ci", auth.ServiceAccountTokenRequest{Name: "pipeline", Scopes: scopes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.CreateServiceAccountToken("ci", auth.ServiceAccountTokenRequest{Name: "pipeline"}); err == nil {
+		t.Fatal("expected error when creating token with duplicated name")
+	}
+	identity, err := auth.Authenticate(created.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Username != auth.ServiceAccountUsername("ci") || len(identity.Scopes) != 1 {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+	obj, err := serviceAccountRegistry.Get(context.TODO(), "", "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceAccount = obj.(*v1.ServiceAccount)
+	token := serviceAccount.GetToken("pipeline")
+	if token == nil || token.Hash == created.Token || token.Hash != v1.HashServiceAccountToken(created.Token) {
+		t.Fatalf("token should be stored as hash: %+v", token)
+	}
+	if _, ok := serviceAccount.Info.TokensLastUsed["pipeline"]; !ok {
+		t.Fatal("last used time of token is not recorded")
+	}
+
+	// 服务账号通过角色绑定获得授权，令牌的授权范围进一步限制可执行的操作
+	role := v1.NewRole()
+	role.Metadata.Name = "deployer"
+	role.Spec.Rules = []v1.PolicyRule{{Verbs: []string{"*"}, Kinds: []string{core.KindAppInstance}}}
+	if _, err := v1.NewRoleRegistry().Create(context.TODO(), role); err != nil {
+		t.Fatal(err)
+	}
+	roleBinding := v1.NewRoleBinding()
+	roleBinding.Metadata.Name = "ci-deployer"
+	roleBinding.Spec.RoleRef = "deployer"
+	roleBinding.Spec.Subjects = []v1.Subject{{Kind: core.SubjectKindServiceAccount, Name: "ci"}}
+	if _, err := v1.NewRoleBindingRegistry().Create(context.TODO(), roleBinding); err != nil {
+		t.Fatal(err)
+	}
+	attrs := rbac.Attributes{Verb: core.VerbDelete, Kind: core.KindAppInstance, Namespace: "default", Name: "a"}
+	if allowed, err := rbac.Authorize(identity.Username, attrs); err != nil {
+		t.Fatal(err)
+	} else if !allowed {
+		t.Fatal("service account should be authorized by role binding")
+	}
+	if rbac.MatchRules(identity.Scopes, attrs) {
+		t.Fatal("delete should be out of the token scopes")
+	}
+
+	// 过期或被吊销的令牌无法使用
+	expiring, err := auth.CreateServiceAccountToken("ci", auth.ServiceAccountTokenRequest{Name: "expiring", ExpiresIn: "1h"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err = serviceAccountRegistry.Get(context.TODO(), "", "ci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceAccount = obj.(*v1.ServiceAccount)
+	serviceAccount.GetToken("expiring").ExpireTime = time.Now().Add(-time.Minute)
+	if _, err := serviceAccountRegistry.Update(context.TODO(), serviceAccount); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(expiring.Token); err == nil {
+		t.Fatal("expired token should not be accepted")
+	}
+	if err := auth.RevokeServiceAccountToken("ci", "pipeline"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(created.Token); err == nil {
+		t.Fatal("revoked token should not be accepted")
+	} else if _, ok := err.(e.UnauthorizedError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
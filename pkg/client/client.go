@@ -25,3 +25,11 @@ func NewClientSet(endpoint string) ClientSet {
 		v2: v2.NewClient(rest.NewRESTClient(endpoint)),
 	}
 }
+
+// NewClientSetWithToken 实例化携带令牌的客户端集合，令牌可以是用户登录签发的访问令牌或服务账号令牌
+func NewClientSetWithToken(endpoint string, token string) ClientSet {
+	return ClientSet{
+		v1: v1.NewClient(rest.NewRESTClientWithToken(endpoint, token)),
+		v2: v2.NewClient(rest.NewRESTClientWithToken(endpoint, token)),
+	}
+}
@@ -1,25 +1,34 @@
 package rest
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
 )
 
+// maxWatchLineSize 侦听时单个变更的最大长度
+const maxWatchLineSize = 16 * 1024 * 1024
+
 type RESTClient struct {
 	endpoint string
+	// 访问令牌，不为空时在请求头中携带 Authorization: Bearer <访问令牌>
+	token string
 }
 
 func (c RESTClient) Get() *Request {
 	return &Request{
 		endpoint: c.endpoint,
+		token:    c.token,
 		method:   http.MethodGet,
 	}
 }
@@ -27,6 +36,7 @@ func (c RESTClient) Get() *Request {
 func (c RESTClient) Put() *Request {
 	return &Request{
 		endpoint: c.endpoint,
+		token:    c.token,
 		method:   http.MethodPut,
 	}
 }
@@ -34,13 +44,25 @@ func (c RESTClient) Put() *Request {
 func (c RESTClient) Post() *Request {
 	return &Request{
 		endpoint: c.endpoint,
+		token:    c.token,
 		method:   http.MethodPost,
 	}
 }
 
+// Patch 创建使用补丁修改资源的请求，patchType为请求体的Content-Type
+func (c RESTClient) Patch(patchType string) *Request {
+	return &Request{
+		endpoint:    c.endpoint,
+		token:       c.token,
+		method:      http.MethodPatch,
+		contentType: patchType,
+	}
+}
+
 func (c RESTClient) Delete() *Request {
 	return &Request{
 		endpoint: c.endpoint,
+		token:    c.token,
 		method:   http.MethodDelete,
 	}
 }
@@ -51,15 +73,26 @@ func NewRESTClient(endpoint string) RESTClient {
 	}
 }
 
+// NewRESTClientWithToken 实例化携带令牌的客户端，令牌可以是用户登录签发的访问令牌或服务账号令牌
+func NewRESTClientWithToken(endpoint string, token string) RESTClient {
+	return RESTClient{
+		endpoint: endpoint,
+		token:    token,
+	}
+}
+
 type Request struct {
 	endpoint     string
+	token        string
 	resource     string
 	resourceName string
+	subResource  string
 	namespace    string
 	method       string
 	params       map[string]string
 	apiVersion   string
 	body         []byte
+	contentType  string
 }
 
 // Version 设置请求资源的结构版本
@@ -86,6 +119,12 @@ func (r *Request) Name(name string) *Request {
 	return r
 }
 
+// SubResource 设置请求资源的子资源路径，如revisions/1
+func (r *Request) SubResource(subResource string) *Request {
+	r.subResource = subResource
+	return r
+}
+
 // Data 设置请求资源的类型
 func (r *Request) Data(data interface{}) *Request {
 	body, _ := json.Marshal(data)
@@ -93,20 +132,40 @@ func (r *Request) Data(data interface{}) *Request {
 	return r
 }
 
+// Body 设置未经序列化的原始请求体
+func (r *Request) Body(body []byte) *Request {
+	r.body = body
+	return r
+}
+
 // Params 设置请求查询参数
 func (r *Request) Params(params map[string]string) *Request {
 	r.params = params
 	return r
 }
 
-// Do 执行请求
-func (r *Request) Do(ctx context.Context) *Result {
+// Options 将写入选项转换为请求查询参数，支持core.WithDryRun与core.WithPropagationPolicy
+func (r *Request) Options(opts ...core.OpOpt) *Request {
+	var option core.Option
+	option.SetupOption(opts...)
+	if r.params == nil {
+		r.params = make(map[string]string)
+	}
+	if option.DryRun {
+		r.params["dryRun"] = "true"
+	}
+	if option.PropagationPolicy != "" {
+		r.params["propagationPolicy"] = option.PropagationPolicy
+	}
+	return r
+}
+
+// newHTTPRequest 根据请求的资源信息构造http请求
+func (r *Request) newHTTPRequest(ctx context.Context) (*http.Request, error) {
 	urlStr := r.endpoint + "/api"
 
 	if r.apiVersion == "" {
-		return &Result{
-			err: e.Errorf("please specific api version"),
-		}
+		return nil, e.Errorf("please specific api version")
 	}
 	urlStr += "/" + r.apiVersion
 
@@ -115,9 +174,7 @@ func (r *Request) Do(ctx context.Context) *Result {
 	}
 
 	if r.resource == "" {
-		return &Result{
-			err: e.Errorf("please specific resource"),
-		}
+		return nil, e.Errorf("please specific resource")
 	}
 	urlStr += "/" + r.resource
 
@@ -125,6 +182,13 @@ func (r *Request) Do(ctx context.Context) *Result {
 		urlStr += "/" + r.resourceName
 	}
 
+	if r.subResource != "" {
+		if r.resourceName == "" {
+			return nil, e.Errorf("please specific resource name")
+		}
+		urlStr += "/" + r.subResource
+	}
+
 	requestUrl, _ := url.Parse(urlStr)
 
 	query := requestUrl.Query()
@@ -136,6 +200,21 @@ func (r *Request) Do(ctx context.Context) *Result {
 	log.Debugf("[%s] %s %s", r.method, requestUrl.String(), string(r.body))
 
 	req, err := http.NewRequestWithContext(ctx, r.method, requestUrl.String(), bytes.NewReader(r.body))
+	if err != nil {
+		return nil, err
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	return req, nil
+}
+
+// Do 执行请求
+func (r *Request) Do(ctx context.Context) *Result {
+	req, err := r.newHTTPRequest(ctx)
 	if err != nil {
 		return &Result{
 			err: err,
@@ -149,6 +228,7 @@ func (r *Request) Do(ctx context.Context) *Result {
 			err: err,
 		}
 	}
+	defer resp.Body.Close()
 
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -170,6 +250,79 @@ func (r *Request) Do(ctx context.Context) *Result {
 	}
 }
 
+// Watch 执行侦听请求，逐行解析服务端推送的变更，并将变更的对象解析为newObj创建的资源对象。
+// 连接断开或上下文结束时关闭通道，调用方可从最后收到的修订版本恢复侦听。修订版本已过期时返回e.ExpiredRevisionError
+func (r *Request) Watch(ctx context.Context, newObj func() core.ApiObject) (<-chan core.ApiObjectAction, error) {
+	req, err := r.newHTTPRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cli := http.Client{}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusGone {
+			revision, _ := strconv.ParseInt(r.params["revision"], 10, 64)
+			return nil, e.ExpiredRevisionError{Revision: revision}
+		}
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		_, err = (&Result{data: data, err: e.Errorf(resp.Status)}).Raw()
+		return nil, err
+	}
+
+	actions := make(chan core.ApiObjectAction)
+	go func() {
+		defer close(actions)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), maxWatchLineSize)
+		for scanner.Scan() {
+			// 空行为心跳
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+
+			var event struct {
+				Type     string
+				Obj      json.RawMessage
+				Revision int64
+			}
+			if err := json.Unmarshal(line, &event); err != nil {
+				log.Error(err)
+				continue
+			}
+			obj := newObj()
+			if err := json.Unmarshal(event.Obj, obj); err != nil {
+				log.Error(err)
+				continue
+			}
+
+			select {
+			case actions <- core.ApiObjectAction{
+				Type:     event.Type,
+				Obj:      obj,
+				Revision: event.Revision,
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			log.Warn(err)
+		}
+	}()
+	return actions, nil
+}
+
 type Result struct {
 	err  error
 	data []byte
@@ -177,9 +330,11 @@ type Result struct {
 }
 
 type ResultBody struct {
-	OpCode int
-	OpDesc string
-	Data   interface{}
+	OpCode   int
+	OpDesc   string
+	Data     interface{}
+	Continue string
+	Revision int64
 }
 
 // Into 将http请求返回结果写入receiver中，receiver必须是指针
@@ -202,3 +357,24 @@ func (r *Result) Into(receiver interface{}) error {
 	}
 	return nil
 }
+
+// Continue 返回分页获取时用于获取下一页的令牌，需要在Into之后调用
+func (r *Result) Continue() string {
+	return r.body.Continue
+}
+
+// Revision 返回列举时存储的修订版本号，可用于从该版本之后开始侦听变更，需要在Into之后调用
+func (r *Result) Revision() int64 {
+	return r.body.Revision
+}
+
+// Raw 返回http请求的原始返回内容，请求失败时返回错误
+func (r *Result) Raw() ([]byte, error) {
+	if r.err != nil {
+		if err := json.Unmarshal(r.data, &r.body); err == nil && r.body.OpDesc != "" {
+			return nil, e.Errorf(r.body.OpDesc)
+		}
+		return nil, r.err
+	}
+	return r.data, nil
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"context"
+)
+
+// Backup 导出全量数据备份，返回gzip压缩的备份归档
+func (c Client) Backup(ctx context.Context) ([]byte, error) {
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("admin").
+		Name("backup").
+		Do(ctx).
+		Raw()
+}
+
+// Restore 使用备份归档还原数据
+func (c Client) Restore(ctx context.Context, archive []byte) (string, error) {
+	var result string
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("admin").
+		Name("restore").
+		Body(archive).
+		Do(ctx).
+		Into(&result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// RotateEncryptionKey 轮换加密密钥并重新加密所有资源对象，返回新的主密钥ID
+func (c Client) RotateEncryptionKey(ctx context.Context) (string, error) {
+	var result string
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("admin").
+		Name("encryption/rotate").
+		Do(ctx).
+		Into(&result); err != nil {
+		return "", err
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/wujie1993/waves/pkg/auth"
+)
+
+// Login 使用用户名与密码登录，返回签发的令牌
+func (c Client) Login(ctx context.Context, username string, password string) (*auth.TokenPair, error) {
+	result := new(auth.TokenPair)
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("login").
+		Data(auth.LoginRequest{Username: username, Password: password}).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Refresh 使用刷新令牌换取新的令牌
+func (c Client) Refresh(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+	result := new(auth.TokenPair)
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("refresh").
+		Data(auth.RefreshRequest{RefreshToken: refreshToken}).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Logout 使当前用户已签发的所有令牌失效
+func (c Client) Logout(ctx context.Context) error {
+	return c.RESTClient.Post().
+		Version("v1").
+		Resource("logout").
+		Do(ctx).
+		Into(nil)
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"context"
+
+	"github.com/wujie1993/waves/pkg/auth"
+)
+
+// CreateServiceAccountToken 为服务账号创建令牌，令牌明文只在创建时返回一次
+func (c Client) CreateServiceAccountToken(ctx context.Context, name string, req auth.ServiceAccountTokenRequest) (*auth.ServiceAccountTokenResponse, error) {
+	result := new(auth.ServiceAccountTokenResponse)
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("serviceaccounts").
+		Name(name).
+		SubResource("tokens").
+		Data(req).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RevokeServiceAccountToken 吊销服务账号的令牌
+func (c Client) RevokeServiceAccountToken(ctx context.Context, name string, tokenName string) error {
+	return c.RESTClient.Delete().
+		Version("v1").
+		Resource("serviceaccounts").
+		Name(name).
+		SubResource("tokens/" + tokenName).
+		Do(ctx).
+		Into(nil)
+}
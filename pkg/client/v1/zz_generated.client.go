@@ -4,15 +4,25 @@ package v1
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/wujie1993/waves/pkg/client/rest"
+	"github.com/wujie1993/waves/pkg/orm/core"
 	objv1 "github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/patch"
 )
 
 type Client struct {
 	rest.RESTClient
 }
 
+func (c Client) AdmissionConfigs() admissionconfigs {
+	return admissionconfigs{
+		RESTClient: c.RESTClient,
+	}
+}
+
 func (c Client) Apps(namespace string) apps {
 	return apps{
 		namespace:  namespace,
@@ -95,23 +105,46 @@ func (c Client) Revisions() revisions {
 	}
 }
 
+func (c Client) Roles() roles {
+	return roles{
+		RESTClient: c.RESTClient,
+	}
+}
+
+func (c Client) RoleBindings() rolebindings {
+	return rolebindings{
+		RESTClient: c.RESTClient,
+	}
+}
+
+func (c Client) ServiceAccounts() serviceaccounts {
+	return serviceaccounts{
+		RESTClient: c.RESTClient,
+	}
+}
+
+func (c Client) Users() users {
+	return users{
+		RESTClient: c.RESTClient,
+	}
+}
+
 func NewClient(cli rest.RESTClient) Client {
 	return Client{
 		RESTClient: cli,
 	}
 }
 
-type apps struct {
+type admissionconfigs struct {
 	rest.RESTClient
 	namespace string
 }
 
-func (c apps) Get(ctx context.Context, name string) (*objv1.App, error) {
-	result := &objv1.App{}
+func (c admissionconfigs) Get(ctx context.Context, name string) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("apps").
+		Resource("admissionconfigs").
 		Name(name).
 		Do(ctx).
 		Into(result); err != nil {
@@ -120,13 +153,13 @@ func (c apps) Get(ctx context.Context, name string) (*objv1.App, error) {
 	return result, nil
 }
 
-func (c apps) Create(ctx context.Context, obj *objv1.App) (*objv1.App, error) {
-	result := &objv1.App{}
+func (c admissionconfigs) Create(ctx context.Context, obj *objv1.AdmissionConfig, opts ...core.OpOpt) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
 	if err := c.RESTClient.Post().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("apps").
+		Resource("admissionconfigs").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -134,12 +167,11 @@ func (c apps) Create(ctx context.Context, obj *objv1.App) (*objv1.App, error) {
 	return result, nil
 }
 
-func (c apps) List(ctx context.Context) ([]objv1.App, error) {
-	result := []objv1.App{}
+func (c admissionconfigs) List(ctx context.Context) ([]objv1.AdmissionConfig, error) {
+	result := []objv1.AdmissionConfig{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("apps").
+		Resource("admissionconfigs").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -147,61 +179,75 @@ func (c apps) List(ctx context.Context) ([]objv1.App, error) {
 	return result, nil
 }
 
-func (c apps) Update(ctx context.Context, obj *objv1.App) (*objv1.App, error) {
-	result := &objv1.App{}
-	if err := c.RESTClient.Put().
+func (c admissionconfigs) ListWithRevision(ctx context.Context) ([]objv1.AdmissionConfig, int64, error) {
+	result := []objv1.AdmissionConfig{}
+	resp := c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("apps").
-		Name(obj.Metadata.Name).
-		Data(obj).
-		Do(ctx).
-		Into(&result); err != nil {
-		return nil, err
+		Resource("admissionconfigs").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
 	}
-	return result, nil
+	return result, resp.Revision(), nil
 }
 
-func (c apps) Delete(ctx context.Context, name string) (*objv1.App, error) {
-	result := &objv1.App{}
-	if err := c.RESTClient.Delete().
+func (c admissionconfigs) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.AdmissionConfig, string, error) {
+	result := []objv1.AdmissionConfig{}
+	resp := c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("apps").
-		Name(name).
-		Do(ctx).
-		Into(result); err != nil {
-		return nil, err
+		Resource("admissionconfigs").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
 	}
-	return result, nil
-}
-
-type appinstances struct {
-	rest.RESTClient
-	namespace string
+	return result, resp.Continue(), nil
 }
 
-func (c appinstances) Get(ctx context.Context, name string) (*objv1.AppInstance, error) {
-	result := &objv1.AppInstance{}
+func (c admissionconfigs) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.AdmissionConfig, error) {
+	result := []objv1.AdmissionConfig{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("appinstances").
-		Name(name).
+		Resource("admissionconfigs").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
 		Do(ctx).
-		Into(result); err != nil {
+		Into(&result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c appinstances) Create(ctx context.Context, obj *objv1.AppInstance) (*objv1.AppInstance, error) {
-	result := &objv1.AppInstance{}
-	if err := c.RESTClient.Post().
+func (c admissionconfigs) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("appinstances").
+		Resource("admissionconfigs").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewAdmissionConfig()
+		})
+}
+
+func (c admissionconfigs) Update(ctx context.Context, obj *objv1.AdmissionConfig, opts ...core.OpOpt) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("admissionconfigs").
+		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -209,41 +255,43 @@ func (c appinstances) Create(ctx context.Context, obj *objv1.AppInstance) (*objv
 	return result, nil
 }
 
-func (c appinstances) List(ctx context.Context) ([]objv1.AppInstance, error) {
-	result := []objv1.AppInstance{}
-	if err := c.RESTClient.Get().
+func (c admissionconfigs) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
+	if err := c.RESTClient.Patch(patchType).
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("appinstances").
+		Resource("admissionconfigs").
+		Name(name).
+		Body(data).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c appinstances) Update(ctx context.Context, obj *objv1.AppInstance) (*objv1.AppInstance, error) {
-	result := &objv1.AppInstance{}
-	if err := c.RESTClient.Put().
+func (c admissionconfigs) Apply(ctx context.Context, obj *objv1.AdmissionConfig, opts ...core.OpOpt) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("appinstances").
+		Resource("admissionconfigs").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c appinstances) Delete(ctx context.Context, name string) (*objv1.AppInstance, error) {
-	result := &objv1.AppInstance{}
+func (c admissionconfigs) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("appinstances").
+		Resource("admissionconfigs").
 		Name(name).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -251,17 +299,15 @@ func (c appinstances) Delete(ctx context.Context, name string) (*objv1.AppInstan
 	return result, nil
 }
 
-type audits struct {
-	rest.RESTClient
-	namespace string
-}
-
-func (c audits) Get(ctx context.Context, name string) (*objv1.Audit, error) {
-	result := &objv1.Audit{}
-	if err := c.RESTClient.Get().
+func (c admissionconfigs) UpdateStatus(ctx context.Context, obj *objv1.AdmissionConfig, opts ...core.OpOpt) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
+	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("audits").
-		Name(name).
+		Resource("admissionconfigs").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -269,12 +315,13 @@ func (c audits) Get(ctx context.Context, name string) (*objv1.Audit, error) {
 	return result, nil
 }
 
-func (c audits) Create(ctx context.Context, obj *objv1.Audit) (*objv1.Audit, error) {
-	result := &objv1.Audit{}
-	if err := c.RESTClient.Post().
+func (c admissionconfigs) ListRevisions(ctx context.Context, name string) ([]objv1.AdmissionConfig, error) {
+	result := []objv1.AdmissionConfig{}
+	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("audits").
-		Data(obj).
+		Resource("admissionconfigs").
+		Name(name).
+		SubResource("revisions").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -282,38 +329,41 @@ func (c audits) Create(ctx context.Context, obj *objv1.Audit) (*objv1.Audit, err
 	return result, nil
 }
 
-func (c audits) List(ctx context.Context) ([]objv1.Audit, error) {
-	result := []objv1.Audit{}
+func (c admissionconfigs) GetRevision(ctx context.Context, name string, revision int) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("audits").
+		Resource("admissionconfigs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c audits) Update(ctx context.Context, obj *objv1.Audit) (*objv1.Audit, error) {
-	result := &objv1.Audit{}
+func (c admissionconfigs) RevertRevision(ctx context.Context, name string, revision int) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
 	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("audits").
-		Name(obj.Metadata.Name).
-		Data(obj).
+		Resource("admissionconfigs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c audits) Delete(ctx context.Context, name string) (*objv1.Audit, error) {
-	result := &objv1.Audit{}
+func (c admissionconfigs) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.AdmissionConfig, error) {
+	result := &objv1.AdmissionConfig{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Resource("audits").
+		Resource("admissionconfigs").
 		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -321,17 +371,31 @@ func (c audits) Delete(ctx context.Context, name string) (*objv1.Audit, error) {
 	return result, nil
 }
 
-type configmaps struct {
+func (c admissionconfigs) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("admissionconfigs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type apps struct {
 	rest.RESTClient
 	namespace string
 }
 
-func (c configmaps) Get(ctx context.Context, name string) (*objv1.ConfigMap, error) {
-	result := &objv1.ConfigMap{}
+func (c apps) Get(ctx context.Context, name string) (*objv1.App, error) {
+	result := &objv1.App{}
 	if err := c.RESTClient.Get().
 		Version("v1").
 		Namespace(c.namespace).
-		Resource("configmaps").
+		Resource("apps").
 		Name(name).
 		Do(ctx).
 		Into(result); err != nil {
@@ -340,13 +404,14 @@ func (c configmaps) Get(ctx context.Context, name string) (*objv1.ConfigMap, err
 	return result, nil
 }
 
-func (c configmaps) Create(ctx context.Context, obj *objv1.ConfigMap) (*objv1.ConfigMap, error) {
-	result := &objv1.ConfigMap{}
+func (c apps) Create(ctx context.Context, obj *objv1.App, opts ...core.OpOpt) (*objv1.App, error) {
+	result := &objv1.App{}
 	if err := c.RESTClient.Post().
 		Version("v1").
 		Namespace(c.namespace).
-		Resource("configmaps").
+		Resource("apps").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -354,12 +419,12 @@ func (c configmaps) Create(ctx context.Context, obj *objv1.ConfigMap) (*objv1.Co
 	return result, nil
 }
 
-func (c configmaps) List(ctx context.Context) ([]objv1.ConfigMap, error) {
-	result := []objv1.ConfigMap{}
+func (c apps) List(ctx context.Context) ([]objv1.App, error) {
+	result := []objv1.App{}
 	if err := c.RESTClient.Get().
 		Version("v1").
 		Namespace(c.namespace).
-		Resource("configmaps").
+		Resource("apps").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -367,59 +432,80 @@ func (c configmaps) List(ctx context.Context) ([]objv1.ConfigMap, error) {
 	return result, nil
 }
 
-func (c configmaps) Update(ctx context.Context, obj *objv1.ConfigMap) (*objv1.ConfigMap, error) {
-	result := &objv1.ConfigMap{}
-	if err := c.RESTClient.Put().
+func (c apps) ListWithRevision(ctx context.Context) ([]objv1.App, int64, error) {
+	result := []objv1.App{}
+	resp := c.RESTClient.Get().
 		Version("v1").
 		Namespace(c.namespace).
-		Resource("configmaps").
-		Name(obj.Metadata.Name).
-		Data(obj).
-		Do(ctx).
-		Into(&result); err != nil {
-		return nil, err
+		Resource("apps").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
 	}
-	return result, nil
+	return result, resp.Revision(), nil
 }
 
-func (c configmaps) Delete(ctx context.Context, name string) (*objv1.ConfigMap, error) {
-	result := &objv1.ConfigMap{}
-	if err := c.RESTClient.Delete().
+func (c apps) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.App, string, error) {
+	result := []objv1.App{}
+	resp := c.RESTClient.Get().
 		Version("v1").
 		Namespace(c.namespace).
-		Resource("configmaps").
-		Name(name).
-		Do(ctx).
-		Into(result); err != nil {
-		return nil, err
+		Resource("apps").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
 	}
-	return result, nil
-}
-
-type events struct {
-	rest.RESTClient
-	namespace string
+	return result, resp.Continue(), nil
 }
 
-func (c events) Get(ctx context.Context, name string) (*objv1.Event, error) {
-	result := &objv1.Event{}
+func (c apps) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.App, error) {
+	result := []objv1.App{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("events").
-		Name(name).
+		Namespace(c.namespace).
+		Resource("apps").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
 		Do(ctx).
-		Into(result); err != nil {
+		Into(&result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c events) Create(ctx context.Context, obj *objv1.Event) (*objv1.Event, error) {
-	result := &objv1.Event{}
-	if err := c.RESTClient.Post().
+func (c apps) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
 		Version("v1").
-		Resource("events").
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewApp()
+		})
+}
+
+func (c apps) Update(ctx context.Context, obj *objv1.App, opts ...core.OpOpt) (*objv1.App, error) {
+	result := &objv1.App{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -427,38 +513,46 @@ func (c events) Create(ctx context.Context, obj *objv1.Event) (*objv1.Event, err
 	return result, nil
 }
 
-func (c events) List(ctx context.Context) ([]objv1.Event, error) {
-	result := []objv1.Event{}
-	if err := c.RESTClient.Get().
+func (c apps) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.App, error) {
+	result := &objv1.App{}
+	if err := c.RESTClient.Patch(patchType).
 		Version("v1").
-		Resource("events").
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(name).
+		Body(data).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c events) Update(ctx context.Context, obj *objv1.Event) (*objv1.Event, error) {
-	result := &objv1.Event{}
-	if err := c.RESTClient.Put().
+func (c apps) Apply(ctx context.Context, obj *objv1.App, opts ...core.OpOpt) (*objv1.App, error) {
+	result := &objv1.App{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
 		Version("v1").
-		Resource("events").
+		Namespace(c.namespace).
+		Resource("apps").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c events) Delete(ctx context.Context, name string) (*objv1.Event, error) {
-	result := &objv1.Event{}
+func (c apps) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.App, error) {
+	result := &objv1.App{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Resource("events").
+		Namespace(c.namespace).
+		Resource("apps").
 		Name(name).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -466,17 +560,16 @@ func (c events) Delete(ctx context.Context, name string) (*objv1.Event, error) {
 	return result, nil
 }
 
-type gpus struct {
-	rest.RESTClient
-	namespace string
-}
-
-func (c gpus) Get(ctx context.Context, name string) (*objv1.GPU, error) {
-	result := &objv1.GPU{}
-	if err := c.RESTClient.Get().
+func (c apps) UpdateStatus(ctx context.Context, obj *objv1.App, opts ...core.OpOpt) (*objv1.App, error) {
+	result := &objv1.App{}
+	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("gpus").
-		Name(name).
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -484,12 +577,14 @@ func (c gpus) Get(ctx context.Context, name string) (*objv1.GPU, error) {
 	return result, nil
 }
 
-func (c gpus) Create(ctx context.Context, obj *objv1.GPU) (*objv1.GPU, error) {
-	result := &objv1.GPU{}
-	if err := c.RESTClient.Post().
+func (c apps) ListRevisions(ctx context.Context, name string) ([]objv1.App, error) {
+	result := []objv1.App{}
+	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("gpus").
-		Data(obj).
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(name).
+		SubResource("revisions").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -497,11 +592,3142 @@ func (c gpus) Create(ctx context.Context, obj *objv1.GPU) (*objv1.GPU, error) {
 	return result, nil
 }
 
-func (c gpus) List(ctx context.Context) ([]objv1.GPU, error) {
-	result := []objv1.GPU{}
+func (c apps) GetRevision(ctx context.Context, name string, revision int) (*objv1.App, error) {
+	result := &objv1.App{}
 	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c apps) RevertRevision(ctx context.Context, name string, revision int) (*objv1.App, error) {
+	result := &objv1.App{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c apps) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.App, error) {
+	result := &objv1.App{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c apps) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("apps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type appinstances struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c appinstances) Get(ctx context.Context, name string) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Create(ctx context.Context, obj *objv1.AppInstance, opts ...core.OpOpt) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) List(ctx context.Context) ([]objv1.AppInstance, error) {
+	result := []objv1.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) ListWithRevision(ctx context.Context) ([]objv1.AppInstance, int64, error) {
+	result := []objv1.AppInstance{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c appinstances) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.AppInstance, string, error) {
+	result := []objv1.AppInstance{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c appinstances) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.AppInstance, error) {
+	result := []objv1.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewAppInstance()
+		})
+}
+
+func (c appinstances) Update(ctx context.Context, obj *objv1.AppInstance, opts ...core.OpOpt) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Apply(ctx context.Context, obj *objv1.AppInstance, opts ...core.OpOpt) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) UpdateStatus(ctx context.Context, obj *objv1.AppInstance, opts ...core.OpOpt) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) ListRevisions(ctx context.Context, name string) ([]objv1.AppInstance, error) {
+	result := []objv1.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) GetRevision(ctx context.Context, name string, revision int) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) RevertRevision(ctx context.Context, name string, revision int) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.AppInstance, error) {
+	result := &objv1.AppInstance{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type audits struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c audits) Get(ctx context.Context, name string) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) Create(ctx context.Context, obj *objv1.Audit, opts ...core.OpOpt) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("audits").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) List(ctx context.Context) ([]objv1.Audit, error) {
+	result := []objv1.Audit{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) ListWithRevision(ctx context.Context) ([]objv1.Audit, int64, error) {
+	result := []objv1.Audit{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c audits) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Audit, string, error) {
+	result := []objv1.Audit{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c audits) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Audit, error) {
+	result := []objv1.Audit{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewAudit()
+		})
+}
+
+func (c audits) Update(ctx context.Context, obj *objv1.Audit, opts ...core.OpOpt) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("audits").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) Apply(ctx context.Context, obj *objv1.Audit, opts ...core.OpOpt) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("audits").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) UpdateStatus(ctx context.Context, obj *objv1.Audit, opts ...core.OpOpt) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("audits").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) ListRevisions(ctx context.Context, name string) ([]objv1.Audit, error) {
+	result := []objv1.Audit{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) GetRevision(ctx context.Context, name string, revision int) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Audit, error) {
+	result := &objv1.Audit{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c audits) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("audits").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type configmaps struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c configmaps) Get(ctx context.Context, name string) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) Create(ctx context.Context, obj *objv1.ConfigMap, opts ...core.OpOpt) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) List(ctx context.Context) ([]objv1.ConfigMap, error) {
+	result := []objv1.ConfigMap{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) ListWithRevision(ctx context.Context) ([]objv1.ConfigMap, int64, error) {
+	result := []objv1.ConfigMap{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c configmaps) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.ConfigMap, string, error) {
+	result := []objv1.ConfigMap{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c configmaps) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.ConfigMap, error) {
+	result := []objv1.ConfigMap{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewConfigMap()
+		})
+}
+
+func (c configmaps) Update(ctx context.Context, obj *objv1.ConfigMap, opts ...core.OpOpt) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) Apply(ctx context.Context, obj *objv1.ConfigMap, opts ...core.OpOpt) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) UpdateStatus(ctx context.Context, obj *objv1.ConfigMap, opts ...core.OpOpt) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) ListRevisions(ctx context.Context, name string) ([]objv1.ConfigMap, error) {
+	result := []objv1.ConfigMap{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) GetRevision(ctx context.Context, name string, revision int) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) RevertRevision(ctx context.Context, name string, revision int) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.ConfigMap, error) {
+	result := &objv1.ConfigMap{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c configmaps) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("configmaps").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type events struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c events) Get(ctx context.Context, name string) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) Create(ctx context.Context, obj *objv1.Event, opts ...core.OpOpt) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("events").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) List(ctx context.Context) ([]objv1.Event, error) {
+	result := []objv1.Event{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) ListWithRevision(ctx context.Context) ([]objv1.Event, int64, error) {
+	result := []objv1.Event{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c events) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Event, string, error) {
+	result := []objv1.Event{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c events) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Event, error) {
+	result := []objv1.Event{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewEvent()
+		})
+}
+
+func (c events) Update(ctx context.Context, obj *objv1.Event, opts ...core.OpOpt) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("events").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("events").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) Apply(ctx context.Context, obj *objv1.Event, opts ...core.OpOpt) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("events").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) UpdateStatus(ctx context.Context, obj *objv1.Event, opts ...core.OpOpt) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("events").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) ListRevisions(ctx context.Context, name string) ([]objv1.Event, error) {
+	result := []objv1.Event{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) GetRevision(ctx context.Context, name string, revision int) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Event, error) {
+	result := &objv1.Event{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c events) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("events").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type gpus struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c gpus) Get(ctx context.Context, name string) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) Create(ctx context.Context, obj *objv1.GPU, opts ...core.OpOpt) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("gpus").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) List(ctx context.Context) ([]objv1.GPU, error) {
+	result := []objv1.GPU{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) ListWithRevision(ctx context.Context) ([]objv1.GPU, int64, error) {
+	result := []objv1.GPU{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c gpus) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.GPU, string, error) {
+	result := []objv1.GPU{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c gpus) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.GPU, error) {
+	result := []objv1.GPU{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewGPU()
+		})
+}
+
+func (c gpus) Update(ctx context.Context, obj *objv1.GPU, opts ...core.OpOpt) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("gpus").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) Apply(ctx context.Context, obj *objv1.GPU, opts ...core.OpOpt) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("gpus").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) UpdateStatus(ctx context.Context, obj *objv1.GPU, opts ...core.OpOpt) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("gpus").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) ListRevisions(ctx context.Context, name string) ([]objv1.GPU, error) {
+	result := []objv1.GPU{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) GetRevision(ctx context.Context, name string, revision int) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) RevertRevision(ctx context.Context, name string, revision int) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Put().
 		Version("v1").
 		Resource("gpus").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.GPU, error) {
+	result := &objv1.GPU{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c gpus) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("gpus").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type hosts struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c hosts) Get(ctx context.Context, name string) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Create(ctx context.Context, obj *objv1.Host, opts ...core.OpOpt) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("hosts").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) List(ctx context.Context) ([]objv1.Host, error) {
+	result := []objv1.Host{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) ListWithRevision(ctx context.Context) ([]objv1.Host, int64, error) {
+	result := []objv1.Host{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c hosts) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Host, string, error) {
+	result := []objv1.Host{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c hosts) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Host, error) {
+	result := []objv1.Host{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewHost()
+		})
+}
+
+func (c hosts) Update(ctx context.Context, obj *objv1.Host, opts ...core.OpOpt) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("hosts").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Apply(ctx context.Context, obj *objv1.Host, opts ...core.OpOpt) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("hosts").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) UpdateStatus(ctx context.Context, obj *objv1.Host, opts ...core.OpOpt) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("hosts").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) ListRevisions(ctx context.Context, name string) ([]objv1.Host, error) {
+	result := []objv1.Host{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) GetRevision(ctx context.Context, name string, revision int) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Host, error) {
+	result := &objv1.Host{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type jobs struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c jobs) Get(ctx context.Context, name string) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Create(ctx context.Context, obj *objv1.Job, opts ...core.OpOpt) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("jobs").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) List(ctx context.Context) ([]objv1.Job, error) {
+	result := []objv1.Job{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) ListWithRevision(ctx context.Context) ([]objv1.Job, int64, error) {
+	result := []objv1.Job{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c jobs) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Job, string, error) {
+	result := []objv1.Job{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c jobs) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Job, error) {
+	result := []objv1.Job{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewJob()
+		})
+}
+
+func (c jobs) Update(ctx context.Context, obj *objv1.Job, opts ...core.OpOpt) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("jobs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Apply(ctx context.Context, obj *objv1.Job, opts ...core.OpOpt) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("jobs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) UpdateStatus(ctx context.Context, obj *objv1.Job, opts ...core.OpOpt) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("jobs").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) ListRevisions(ctx context.Context, name string) ([]objv1.Job, error) {
+	result := []objv1.Job{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) GetRevision(ctx context.Context, name string, revision int) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Job, error) {
+	result := &objv1.Job{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("jobs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type k8sconfigs struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c k8sconfigs) Get(ctx context.Context, name string) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) Create(ctx context.Context, obj *objv1.K8sConfig, opts ...core.OpOpt) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) List(ctx context.Context) ([]objv1.K8sConfig, error) {
+	result := []objv1.K8sConfig{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) ListWithRevision(ctx context.Context) ([]objv1.K8sConfig, int64, error) {
+	result := []objv1.K8sConfig{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c k8sconfigs) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.K8sConfig, string, error) {
+	result := []objv1.K8sConfig{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c k8sconfigs) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.K8sConfig, error) {
+	result := []objv1.K8sConfig{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewK8sConfig()
+		})
+}
+
+func (c k8sconfigs) Update(ctx context.Context, obj *objv1.K8sConfig, opts ...core.OpOpt) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) Apply(ctx context.Context, obj *objv1.K8sConfig, opts ...core.OpOpt) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) UpdateStatus(ctx context.Context, obj *objv1.K8sConfig, opts ...core.OpOpt) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) ListRevisions(ctx context.Context, name string) ([]objv1.K8sConfig, error) {
+	result := []objv1.K8sConfig{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) GetRevision(ctx context.Context, name string, revision int) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) RevertRevision(ctx context.Context, name string, revision int) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.K8sConfig, error) {
+	result := &objv1.K8sConfig{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c k8sconfigs) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Namespace(c.namespace).
+		Resource("k8sconfigs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type namespaces struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c namespaces) Get(ctx context.Context, name string) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) Create(ctx context.Context, obj *objv1.Namespace, opts ...core.OpOpt) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("namespaces").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) List(ctx context.Context) ([]objv1.Namespace, error) {
+	result := []objv1.Namespace{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) ListWithRevision(ctx context.Context) ([]objv1.Namespace, int64, error) {
+	result := []objv1.Namespace{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c namespaces) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Namespace, string, error) {
+	result := []objv1.Namespace{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c namespaces) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Namespace, error) {
+	result := []objv1.Namespace{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewNamespace()
+		})
+}
+
+func (c namespaces) Update(ctx context.Context, obj *objv1.Namespace, opts ...core.OpOpt) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("namespaces").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) Apply(ctx context.Context, obj *objv1.Namespace, opts ...core.OpOpt) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("namespaces").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) UpdateStatus(ctx context.Context, obj *objv1.Namespace, opts ...core.OpOpt) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("namespaces").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) ListRevisions(ctx context.Context, name string) ([]objv1.Namespace, error) {
+	result := []objv1.Namespace{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) GetRevision(ctx context.Context, name string, revision int) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Namespace, error) {
+	result := &objv1.Namespace{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c namespaces) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("namespaces").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type pkgs struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c pkgs) Get(ctx context.Context, name string) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) Create(ctx context.Context, obj *objv1.Pkg, opts ...core.OpOpt) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("pkgs").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) List(ctx context.Context) ([]objv1.Pkg, error) {
+	result := []objv1.Pkg{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) ListWithRevision(ctx context.Context) ([]objv1.Pkg, int64, error) {
+	result := []objv1.Pkg{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c pkgs) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Pkg, string, error) {
+	result := []objv1.Pkg{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c pkgs) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Pkg, error) {
+	result := []objv1.Pkg{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewPkg()
+		})
+}
+
+func (c pkgs) Update(ctx context.Context, obj *objv1.Pkg, opts ...core.OpOpt) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("pkgs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) Apply(ctx context.Context, obj *objv1.Pkg, opts ...core.OpOpt) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("pkgs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) UpdateStatus(ctx context.Context, obj *objv1.Pkg, opts ...core.OpOpt) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("pkgs").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) ListRevisions(ctx context.Context, name string) ([]objv1.Pkg, error) {
+	result := []objv1.Pkg{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) GetRevision(ctx context.Context, name string, revision int) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Pkg, error) {
+	result := &objv1.Pkg{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c pkgs) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("pkgs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type projects struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c projects) Get(ctx context.Context, name string) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) Create(ctx context.Context, obj *objv1.Project, opts ...core.OpOpt) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("projects").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) List(ctx context.Context) ([]objv1.Project, error) {
+	result := []objv1.Project{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) ListWithRevision(ctx context.Context) ([]objv1.Project, int64, error) {
+	result := []objv1.Project{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c projects) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Project, string, error) {
+	result := []objv1.Project{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c projects) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Project, error) {
+	result := []objv1.Project{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewProject()
+		})
+}
+
+func (c projects) Update(ctx context.Context, obj *objv1.Project, opts ...core.OpOpt) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("projects").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) Apply(ctx context.Context, obj *objv1.Project, opts ...core.OpOpt) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("projects").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) UpdateStatus(ctx context.Context, obj *objv1.Project, opts ...core.OpOpt) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("projects").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) ListRevisions(ctx context.Context, name string) ([]objv1.Project, error) {
+	result := []objv1.Project{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) GetRevision(ctx context.Context, name string, revision int) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Project, error) {
+	result := &objv1.Project{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c projects) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("projects").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type revisions struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c revisions) Get(ctx context.Context, name string) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) Create(ctx context.Context, obj *objv1.Revision, opts ...core.OpOpt) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("revisions").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) List(ctx context.Context) ([]objv1.Revision, error) {
+	result := []objv1.Revision{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) ListWithRevision(ctx context.Context) ([]objv1.Revision, int64, error) {
+	result := []objv1.Revision{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c revisions) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Revision, string, error) {
+	result := []objv1.Revision{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c revisions) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Revision, error) {
+	result := []objv1.Revision{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewRevision()
+		})
+}
+
+func (c revisions) Update(ctx context.Context, obj *objv1.Revision, opts ...core.OpOpt) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("revisions").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) Apply(ctx context.Context, obj *objv1.Revision, opts ...core.OpOpt) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("revisions").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) UpdateStatus(ctx context.Context, obj *objv1.Revision, opts ...core.OpOpt) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("revisions").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) ListRevisions(ctx context.Context, name string) ([]objv1.Revision, error) {
+	result := []objv1.Revision{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) GetRevision(ctx context.Context, name string, revision int) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Revision, error) {
+	result := &objv1.Revision{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c revisions) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("revisions").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type roles struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c roles) Get(ctx context.Context, name string) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) Create(ctx context.Context, obj *objv1.Role, opts ...core.OpOpt) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("roles").
+		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -509,13 +3735,87 @@ func (c gpus) List(ctx context.Context) ([]objv1.GPU, error) {
 	return result, nil
 }
 
-func (c gpus) Update(ctx context.Context, obj *objv1.GPU) (*objv1.GPU, error) {
-	result := &objv1.GPU{}
+func (c roles) List(ctx context.Context) ([]objv1.Role, error) {
+	result := []objv1.Role{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) ListWithRevision(ctx context.Context) ([]objv1.Role, int64, error) {
+	result := []objv1.Role{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c roles) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.Role, string, error) {
+	result := []objv1.Role{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c roles) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.Role, error) {
+	result := []objv1.Role{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewRole()
+		})
+}
+
+func (c roles) Update(ctx context.Context, obj *objv1.Role, opts ...core.OpOpt) (*objv1.Role, error) {
+	result := &objv1.Role{}
 	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("gpus").
+		Resource("roles").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -523,12 +3823,309 @@ func (c gpus) Update(ctx context.Context, obj *objv1.GPU) (*objv1.GPU, error) {
 	return result, nil
 }
 
-func (c gpus) Delete(ctx context.Context, name string) (*objv1.GPU, error) {
-	result := &objv1.GPU{}
+func (c roles) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) Apply(ctx context.Context, obj *objv1.Role, opts ...core.OpOpt) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("roles").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.Role, error) {
+	result := &objv1.Role{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Resource("gpus").
+		Resource("roles").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) UpdateStatus(ctx context.Context, obj *objv1.Role, opts ...core.OpOpt) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("roles").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) ListRevisions(ctx context.Context, name string) ([]objv1.Role, error) {
+	result := []objv1.Role{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) GetRevision(ctx context.Context, name string, revision int) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) RevertRevision(ctx context.Context, name string, revision int) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.Role, error) {
+	result := &objv1.Role{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c roles) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("roles").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type rolebindings struct {
+	rest.RESTClient
+	namespace string
+}
+
+func (c rolebindings) Get(ctx context.Context, name string) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("rolebindings").
+		Name(name).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) Create(ctx context.Context, obj *objv1.RoleBinding, opts ...core.OpOpt) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Post().
+		Version("v1").
+		Resource("rolebindings").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) List(ctx context.Context) ([]objv1.RoleBinding, error) {
+	result := []objv1.RoleBinding{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("rolebindings").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) ListWithRevision(ctx context.Context) ([]objv1.RoleBinding, int64, error) {
+	result := []objv1.RoleBinding{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("rolebindings").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c rolebindings) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.RoleBinding, string, error) {
+	result := []objv1.RoleBinding{}
+	resp := c.RESTClient.Get().
+		Version("v1").
+		Resource("rolebindings").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c rolebindings) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.RoleBinding, error) {
+	result := []objv1.RoleBinding{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("rolebindings").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v1").
+		Resource("rolebindings").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewRoleBinding()
+		})
+}
+
+func (c rolebindings) Update(ctx context.Context, obj *objv1.RoleBinding, opts ...core.OpOpt) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("rolebindings").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v1").
+		Resource("rolebindings").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) Apply(ctx context.Context, obj *objv1.RoleBinding, opts ...core.OpOpt) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v1").
+		Resource("rolebindings").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Delete().
+		Version("v1").
+		Resource("rolebindings").
 		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c rolebindings) UpdateStatus(ctx context.Context, obj *objv1.RoleBinding, opts ...core.OpOpt) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("rolebindings").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -536,69 +4133,69 @@ func (c gpus) Delete(ctx context.Context, name string) (*objv1.GPU, error) {
 	return result, nil
 }
 
-type hosts struct {
-	rest.RESTClient
-	namespace string
-}
-
-func (c hosts) Get(ctx context.Context, name string) (*objv1.Host, error) {
-	result := &objv1.Host{}
+func (c rolebindings) ListRevisions(ctx context.Context, name string) ([]objv1.RoleBinding, error) {
+	result := []objv1.RoleBinding{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("hosts").
+		Resource("rolebindings").
 		Name(name).
+		SubResource("revisions").
 		Do(ctx).
-		Into(result); err != nil {
+		Into(&result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c hosts) Create(ctx context.Context, obj *objv1.Host) (*objv1.Host, error) {
-	result := &objv1.Host{}
-	if err := c.RESTClient.Post().
+func (c rolebindings) GetRevision(ctx context.Context, name string, revision int) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("hosts").
-		Data(obj).
+		Resource("rolebindings").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c hosts) List(ctx context.Context) ([]objv1.Host, error) {
-	result := []objv1.Host{}
-	if err := c.RESTClient.Get().
+func (c rolebindings) RevertRevision(ctx context.Context, name string, revision int) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("hosts").
+		Resource("rolebindings").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c hosts) Update(ctx context.Context, obj *objv1.Host) (*objv1.Host, error) {
-	result := &objv1.Host{}
-	if err := c.RESTClient.Put().
+func (c rolebindings) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.RoleBinding, error) {
+	result := &objv1.RoleBinding{}
+	if err := c.RESTClient.Delete().
 		Version("v1").
-		Resource("hosts").
-		Name(obj.Metadata.Name).
-		Data(obj).
+		Resource("rolebindings").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c hosts) Delete(ctx context.Context, name string) (*objv1.Host, error) {
-	result := &objv1.Host{}
-	if err := c.RESTClient.Delete().
+func (c rolebindings) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("hosts").
+		Resource("rolebindings").
 		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -606,16 +4203,16 @@ func (c hosts) Delete(ctx context.Context, name string) (*objv1.Host, error) {
 	return result, nil
 }
 
-type jobs struct {
+type serviceaccounts struct {
 	rest.RESTClient
 	namespace string
 }
 
-func (c jobs) Get(ctx context.Context, name string) (*objv1.Job, error) {
-	result := &objv1.Job{}
+func (c serviceaccounts) Get(ctx context.Context, name string) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("jobs").
+		Resource("serviceaccounts").
 		Name(name).
 		Do(ctx).
 		Into(result); err != nil {
@@ -624,12 +4221,13 @@ func (c jobs) Get(ctx context.Context, name string) (*objv1.Job, error) {
 	return result, nil
 }
 
-func (c jobs) Create(ctx context.Context, obj *objv1.Job) (*objv1.Job, error) {
-	result := &objv1.Job{}
+func (c serviceaccounts) Create(ctx context.Context, obj *objv1.ServiceAccount, opts ...core.OpOpt) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
 	if err := c.RESTClient.Post().
 		Version("v1").
-		Resource("jobs").
+		Resource("serviceaccounts").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -637,11 +4235,11 @@ func (c jobs) Create(ctx context.Context, obj *objv1.Job) (*objv1.Job, error) {
 	return result, nil
 }
 
-func (c jobs) List(ctx context.Context) ([]objv1.Job, error) {
-	result := []objv1.Job{}
+func (c serviceaccounts) List(ctx context.Context) ([]objv1.ServiceAccount, error) {
+	result := []objv1.ServiceAccount{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("jobs").
+		Resource("serviceaccounts").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -649,59 +4247,75 @@ func (c jobs) List(ctx context.Context) ([]objv1.Job, error) {
 	return result, nil
 }
 
-func (c jobs) Update(ctx context.Context, obj *objv1.Job) (*objv1.Job, error) {
-	result := &objv1.Job{}
-	if err := c.RESTClient.Put().
+func (c serviceaccounts) ListWithRevision(ctx context.Context) ([]objv1.ServiceAccount, int64, error) {
+	result := []objv1.ServiceAccount{}
+	resp := c.RESTClient.Get().
 		Version("v1").
-		Resource("jobs").
-		Name(obj.Metadata.Name).
-		Data(obj).
-		Do(ctx).
-		Into(&result); err != nil {
-		return nil, err
+		Resource("serviceaccounts").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
 	}
-	return result, nil
+	return result, resp.Revision(), nil
 }
 
-func (c jobs) Delete(ctx context.Context, name string) (*objv1.Job, error) {
-	result := &objv1.Job{}
-	if err := c.RESTClient.Delete().
+func (c serviceaccounts) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.ServiceAccount, string, error) {
+	result := []objv1.ServiceAccount{}
+	resp := c.RESTClient.Get().
 		Version("v1").
-		Resource("jobs").
-		Name(name).
-		Do(ctx).
-		Into(result); err != nil {
-		return nil, err
+		Resource("serviceaccounts").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
 	}
-	return result, nil
-}
-
-type k8sconfigs struct {
-	rest.RESTClient
-	namespace string
+	return result, resp.Continue(), nil
 }
 
-func (c k8sconfigs) Get(ctx context.Context, name string) (*objv1.K8sConfig, error) {
-	result := &objv1.K8sConfig{}
+func (c serviceaccounts) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.ServiceAccount, error) {
+	result := []objv1.ServiceAccount{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("k8sconfigs").
-		Name(name).
+		Resource("serviceaccounts").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
 		Do(ctx).
-		Into(result); err != nil {
+		Into(&result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c k8sconfigs) Create(ctx context.Context, obj *objv1.K8sConfig) (*objv1.K8sConfig, error) {
-	result := &objv1.K8sConfig{}
-	if err := c.RESTClient.Post().
+func (c serviceaccounts) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("k8sconfigs").
+		Resource("serviceaccounts").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewServiceAccount()
+		})
+}
+
+func (c serviceaccounts) Update(ctx context.Context, obj *objv1.ServiceAccount, opts ...core.OpOpt) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("serviceaccounts").
+		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -709,41 +4323,43 @@ func (c k8sconfigs) Create(ctx context.Context, obj *objv1.K8sConfig) (*objv1.K8
 	return result, nil
 }
 
-func (c k8sconfigs) List(ctx context.Context) ([]objv1.K8sConfig, error) {
-	result := []objv1.K8sConfig{}
-	if err := c.RESTClient.Get().
+func (c serviceaccounts) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
+	if err := c.RESTClient.Patch(patchType).
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("k8sconfigs").
+		Resource("serviceaccounts").
+		Name(name).
+		Body(data).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c k8sconfigs) Update(ctx context.Context, obj *objv1.K8sConfig) (*objv1.K8sConfig, error) {
-	result := &objv1.K8sConfig{}
-	if err := c.RESTClient.Put().
+func (c serviceaccounts) Apply(ctx context.Context, obj *objv1.ServiceAccount, opts ...core.OpOpt) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("k8sconfigs").
+		Resource("serviceaccounts").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c k8sconfigs) Delete(ctx context.Context, name string) (*objv1.K8sConfig, error) {
-	result := &objv1.K8sConfig{}
+func (c serviceaccounts) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Namespace(c.namespace).
-		Resource("k8sconfigs").
+		Resource("serviceaccounts").
 		Name(name).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -751,17 +4367,15 @@ func (c k8sconfigs) Delete(ctx context.Context, name string) (*objv1.K8sConfig,
 	return result, nil
 }
 
-type namespaces struct {
-	rest.RESTClient
-	namespace string
-}
-
-func (c namespaces) Get(ctx context.Context, name string) (*objv1.Namespace, error) {
-	result := &objv1.Namespace{}
-	if err := c.RESTClient.Get().
+func (c serviceaccounts) UpdateStatus(ctx context.Context, obj *objv1.ServiceAccount, opts ...core.OpOpt) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
+	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("namespaces").
-		Name(name).
+		Resource("serviceaccounts").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -769,12 +4383,13 @@ func (c namespaces) Get(ctx context.Context, name string) (*objv1.Namespace, err
 	return result, nil
 }
 
-func (c namespaces) Create(ctx context.Context, obj *objv1.Namespace) (*objv1.Namespace, error) {
-	result := &objv1.Namespace{}
-	if err := c.RESTClient.Post().
+func (c serviceaccounts) ListRevisions(ctx context.Context, name string) ([]objv1.ServiceAccount, error) {
+	result := []objv1.ServiceAccount{}
+	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("namespaces").
-		Data(obj).
+		Resource("serviceaccounts").
+		Name(name).
+		SubResource("revisions").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -782,38 +4397,41 @@ func (c namespaces) Create(ctx context.Context, obj *objv1.Namespace) (*objv1.Na
 	return result, nil
 }
 
-func (c namespaces) List(ctx context.Context) ([]objv1.Namespace, error) {
-	result := []objv1.Namespace{}
+func (c serviceaccounts) GetRevision(ctx context.Context, name string, revision int) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("namespaces").
+		Resource("serviceaccounts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c namespaces) Update(ctx context.Context, obj *objv1.Namespace) (*objv1.Namespace, error) {
-	result := &objv1.Namespace{}
+func (c serviceaccounts) RevertRevision(ctx context.Context, name string, revision int) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
 	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("namespaces").
-		Name(obj.Metadata.Name).
-		Data(obj).
+		Resource("serviceaccounts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c namespaces) Delete(ctx context.Context, name string) (*objv1.Namespace, error) {
-	result := &objv1.Namespace{}
+func (c serviceaccounts) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.ServiceAccount, error) {
+	result := &objv1.ServiceAccount{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Resource("namespaces").
+		Resource("serviceaccounts").
 		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -821,16 +4439,30 @@ func (c namespaces) Delete(ctx context.Context, name string) (*objv1.Namespace,
 	return result, nil
 }
 
-type pkgs struct {
+func (c serviceaccounts) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("serviceaccounts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type users struct {
 	rest.RESTClient
 	namespace string
 }
 
-func (c pkgs) Get(ctx context.Context, name string) (*objv1.Pkg, error) {
-	result := &objv1.Pkg{}
+func (c users) Get(ctx context.Context, name string) (*objv1.User, error) {
+	result := &objv1.User{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("pkgs").
+		Resource("users").
 		Name(name).
 		Do(ctx).
 		Into(result); err != nil {
@@ -839,12 +4471,13 @@ func (c pkgs) Get(ctx context.Context, name string) (*objv1.Pkg, error) {
 	return result, nil
 }
 
-func (c pkgs) Create(ctx context.Context, obj *objv1.Pkg) (*objv1.Pkg, error) {
-	result := &objv1.Pkg{}
+func (c users) Create(ctx context.Context, obj *objv1.User, opts ...core.OpOpt) (*objv1.User, error) {
+	result := &objv1.User{}
 	if err := c.RESTClient.Post().
 		Version("v1").
-		Resource("pkgs").
+		Resource("users").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -852,11 +4485,11 @@ func (c pkgs) Create(ctx context.Context, obj *objv1.Pkg) (*objv1.Pkg, error) {
 	return result, nil
 }
 
-func (c pkgs) List(ctx context.Context) ([]objv1.Pkg, error) {
-	result := []objv1.Pkg{}
+func (c users) List(ctx context.Context) ([]objv1.User, error) {
+	result := []objv1.User{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("pkgs").
+		Resource("users").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -864,57 +4497,75 @@ func (c pkgs) List(ctx context.Context) ([]objv1.Pkg, error) {
 	return result, nil
 }
 
-func (c pkgs) Update(ctx context.Context, obj *objv1.Pkg) (*objv1.Pkg, error) {
-	result := &objv1.Pkg{}
-	if err := c.RESTClient.Put().
+func (c users) ListWithRevision(ctx context.Context) ([]objv1.User, int64, error) {
+	result := []objv1.User{}
+	resp := c.RESTClient.Get().
 		Version("v1").
-		Resource("pkgs").
-		Name(obj.Metadata.Name).
-		Data(obj).
-		Do(ctx).
-		Into(&result); err != nil {
-		return nil, err
+		Resource("users").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
 	}
-	return result, nil
+	return result, resp.Revision(), nil
 }
 
-func (c pkgs) Delete(ctx context.Context, name string) (*objv1.Pkg, error) {
-	result := &objv1.Pkg{}
-	if err := c.RESTClient.Delete().
+func (c users) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv1.User, string, error) {
+	result := []objv1.User{}
+	resp := c.RESTClient.Get().
 		Version("v1").
-		Resource("pkgs").
-		Name(name).
-		Do(ctx).
-		Into(result); err != nil {
-		return nil, err
+		Resource("users").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
 	}
-	return result, nil
+	return result, resp.Continue(), nil
 }
 
-type projects struct {
-	rest.RESTClient
-	namespace string
-}
-
-func (c projects) Get(ctx context.Context, name string) (*objv1.Project, error) {
-	result := &objv1.Project{}
+func (c users) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv1.User, error) {
+	result := []objv1.User{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("projects").
-		Name(name).
+		Resource("users").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
 		Do(ctx).
-		Into(result); err != nil {
+		Into(&result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c projects) Create(ctx context.Context, obj *objv1.Project) (*objv1.Project, error) {
-	result := &objv1.Project{}
-	if err := c.RESTClient.Post().
+func (c users) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
 		Version("v1").
-		Resource("projects").
+		Resource("users").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv1.NewUser()
+		})
+}
+
+func (c users) Update(ctx context.Context, obj *objv1.User, opts ...core.OpOpt) (*objv1.User, error) {
+	result := &objv1.User{}
+	if err := c.RESTClient.Put().
+		Version("v1").
+		Resource("users").
+		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -922,38 +4573,43 @@ func (c projects) Create(ctx context.Context, obj *objv1.Project) (*objv1.Projec
 	return result, nil
 }
 
-func (c projects) List(ctx context.Context) ([]objv1.Project, error) {
-	result := []objv1.Project{}
-	if err := c.RESTClient.Get().
+func (c users) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv1.User, error) {
+	result := &objv1.User{}
+	if err := c.RESTClient.Patch(patchType).
 		Version("v1").
-		Resource("projects").
+		Resource("users").
+		Name(name).
+		Body(data).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c projects) Update(ctx context.Context, obj *objv1.Project) (*objv1.Project, error) {
-	result := &objv1.Project{}
-	if err := c.RESTClient.Put().
+func (c users) Apply(ctx context.Context, obj *objv1.User, opts ...core.OpOpt) (*objv1.User, error) {
+	result := &objv1.User{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
 		Version("v1").
-		Resource("projects").
+		Resource("users").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c projects) Delete(ctx context.Context, name string) (*objv1.Project, error) {
-	result := &objv1.Project{}
+func (c users) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv1.User, error) {
+	result := &objv1.User{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Resource("projects").
+		Resource("users").
 		Name(name).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -961,17 +4617,15 @@ func (c projects) Delete(ctx context.Context, name string) (*objv1.Project, erro
 	return result, nil
 }
 
-type revisions struct {
-	rest.RESTClient
-	namespace string
-}
-
-func (c revisions) Get(ctx context.Context, name string) (*objv1.Revision, error) {
-	result := &objv1.Revision{}
-	if err := c.RESTClient.Get().
+func (c users) UpdateStatus(ctx context.Context, obj *objv1.User, opts ...core.OpOpt) (*objv1.User, error) {
+	result := &objv1.User{}
+	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("revisions").
-		Name(name).
+		Resource("users").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -979,12 +4633,13 @@ func (c revisions) Get(ctx context.Context, name string) (*objv1.Revision, error
 	return result, nil
 }
 
-func (c revisions) Create(ctx context.Context, obj *objv1.Revision) (*objv1.Revision, error) {
-	result := &objv1.Revision{}
-	if err := c.RESTClient.Post().
+func (c users) ListRevisions(ctx context.Context, name string) ([]objv1.User, error) {
+	result := []objv1.User{}
+	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("revisions").
-		Data(obj).
+		Resource("users").
+		Name(name).
+		SubResource("revisions").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -992,38 +4647,55 @@ func (c revisions) Create(ctx context.Context, obj *objv1.Revision) (*objv1.Revi
 	return result, nil
 }
 
-func (c revisions) List(ctx context.Context) ([]objv1.Revision, error) {
-	result := []objv1.Revision{}
+func (c users) GetRevision(ctx context.Context, name string, revision int) (*objv1.User, error) {
+	result := &objv1.User{}
 	if err := c.RESTClient.Get().
 		Version("v1").
-		Resource("revisions").
+		Resource("users").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c revisions) Update(ctx context.Context, obj *objv1.Revision) (*objv1.Revision, error) {
-	result := &objv1.Revision{}
+func (c users) RevertRevision(ctx context.Context, name string, revision int) (*objv1.User, error) {
+	result := &objv1.User{}
 	if err := c.RESTClient.Put().
 		Version("v1").
-		Resource("revisions").
-		Name(obj.Metadata.Name).
-		Data(obj).
+		Resource("users").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
 		Do(ctx).
-		Into(&result); err != nil {
+		Into(result); err != nil {
 		return nil, err
 	}
 	return result, nil
 }
 
-func (c revisions) Delete(ctx context.Context, name string) (*objv1.Revision, error) {
-	result := &objv1.Revision{}
+func (c users) DeleteRevision(ctx context.Context, name string, revision int) (*objv1.User, error) {
+	result := &objv1.User{}
 	if err := c.RESTClient.Delete().
 		Version("v1").
-		Resource("revisions").
+		Resource("users").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c users) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v1").
+		Resource("users").
 		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -4,9 +4,13 @@ package v2
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/wujie1993/waves/pkg/client/rest"
+	"github.com/wujie1993/waves/pkg/orm/core"
 	objv2 "github.com/wujie1993/waves/pkg/orm/v2"
+	"github.com/wujie1993/waves/pkg/patch"
 )
 
 type Client struct {
@@ -57,13 +61,14 @@ func (c appinstances) Get(ctx context.Context, name string) (*objv2.AppInstance,
 	return result, nil
 }
 
-func (c appinstances) Create(ctx context.Context, obj *objv2.AppInstance) (*objv2.AppInstance, error) {
+func (c appinstances) Create(ctx context.Context, obj *objv2.AppInstance, opts ...core.OpOpt) (*objv2.AppInstance, error) {
 	result := &objv2.AppInstance{}
 	if err := c.RESTClient.Post().
 		Version("v2").
 		Namespace(c.namespace).
 		Resource("appinstances").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -84,7 +89,72 @@ func (c appinstances) List(ctx context.Context) ([]objv2.AppInstance, error) {
 	return result, nil
 }
 
-func (c appinstances) Update(ctx context.Context, obj *objv2.AppInstance) (*objv2.AppInstance, error) {
+func (c appinstances) ListWithRevision(ctx context.Context) ([]objv2.AppInstance, int64, error) {
+	result := []objv2.AppInstance{}
+	resp := c.RESTClient.Get().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c appinstances) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv2.AppInstance, string, error) {
+	result := []objv2.AppInstance{}
+	resp := c.RESTClient.Get().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c appinstances) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv2.AppInstance, error) {
+	result := []objv2.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv2.NewAppInstance()
+		})
+}
+
+func (c appinstances) Update(ctx context.Context, obj *objv2.AppInstance, opts ...core.OpOpt) (*objv2.AppInstance, error) {
 	result := &objv2.AppInstance{}
 	if err := c.RESTClient.Put().
 		Version("v2").
@@ -92,6 +162,86 @@ func (c appinstances) Update(ctx context.Context, obj *objv2.AppInstance) (*objv
 		Resource("appinstances").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv2.AppInstance, error) {
+	result := &objv2.AppInstance{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Apply(ctx context.Context, obj *objv2.AppInstance, opts ...core.OpOpt) (*objv2.AppInstance, error) {
+	result := &objv2.AppInstance{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv2.AppInstance, error) {
+	result := &objv2.AppInstance{}
+	if err := c.RESTClient.Delete().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) UpdateStatus(ctx context.Context, obj *objv2.AppInstance, opts ...core.OpOpt) (*objv2.AppInstance, error) {
+	result := &objv2.AppInstance{}
+	if err := c.RESTClient.Put().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) ListRevisions(ctx context.Context, name string) ([]objv2.AppInstance, error) {
+	result := []objv2.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource("revisions").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -99,13 +249,59 @@ func (c appinstances) Update(ctx context.Context, obj *objv2.AppInstance) (*objv
 	return result, nil
 }
 
-func (c appinstances) Delete(ctx context.Context, name string) (*objv2.AppInstance, error) {
+func (c appinstances) GetRevision(ctx context.Context, name string, revision int) (*objv2.AppInstance, error) {
+	result := &objv2.AppInstance{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) RevertRevision(ctx context.Context, name string, revision int) (*objv2.AppInstance, error) {
+	result := &objv2.AppInstance{}
+	if err := c.RESTClient.Put().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) DeleteRevision(ctx context.Context, name string, revision int) (*objv2.AppInstance, error) {
 	result := &objv2.AppInstance{}
 	if err := c.RESTClient.Delete().
 		Version("v2").
 		Namespace(c.namespace).
 		Resource("appinstances").
 		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c appinstances) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Namespace(c.namespace).
+		Resource("appinstances").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -131,12 +327,13 @@ func (c hosts) Get(ctx context.Context, name string) (*objv2.Host, error) {
 	return result, nil
 }
 
-func (c hosts) Create(ctx context.Context, obj *objv2.Host) (*objv2.Host, error) {
+func (c hosts) Create(ctx context.Context, obj *objv2.Host, opts ...core.OpOpt) (*objv2.Host, error) {
 	result := &objv2.Host{}
 	if err := c.RESTClient.Post().
 		Version("v2").
 		Resource("hosts").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -156,13 +353,75 @@ func (c hosts) List(ctx context.Context) ([]objv2.Host, error) {
 	return result, nil
 }
 
-func (c hosts) Update(ctx context.Context, obj *objv2.Host) (*objv2.Host, error) {
+func (c hosts) ListWithRevision(ctx context.Context) ([]objv2.Host, int64, error) {
+	result := []objv2.Host{}
+	resp := c.RESTClient.Get().
+		Version("v2").
+		Resource("hosts").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c hosts) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv2.Host, string, error) {
+	result := []objv2.Host{}
+	resp := c.RESTClient.Get().
+		Version("v2").
+		Resource("hosts").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c hosts) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv2.Host, error) {
+	result := []objv2.Host{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("hosts").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v2").
+		Resource("hosts").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv2.NewHost()
+		})
+}
+
+func (c hosts) Update(ctx context.Context, obj *objv2.Host, opts ...core.OpOpt) (*objv2.Host, error) {
 	result := &objv2.Host{}
 	if err := c.RESTClient.Put().
 		Version("v2").
 		Resource("hosts").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -170,12 +429,129 @@ func (c hosts) Update(ctx context.Context, obj *objv2.Host) (*objv2.Host, error)
 	return result, nil
 }
 
-func (c hosts) Delete(ctx context.Context, name string) (*objv2.Host, error) {
+func (c hosts) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv2.Host, error) {
+	result := &objv2.Host{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v2").
+		Resource("hosts").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Apply(ctx context.Context, obj *objv2.Host, opts ...core.OpOpt) (*objv2.Host, error) {
+	result := &objv2.Host{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v2").
+		Resource("hosts").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv2.Host, error) {
 	result := &objv2.Host{}
 	if err := c.RESTClient.Delete().
 		Version("v2").
 		Resource("hosts").
 		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) UpdateStatus(ctx context.Context, obj *objv2.Host, opts ...core.OpOpt) (*objv2.Host, error) {
+	result := &objv2.Host{}
+	if err := c.RESTClient.Put().
+		Version("v2").
+		Resource("hosts").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) ListRevisions(ctx context.Context, name string) ([]objv2.Host, error) {
+	result := []objv2.Host{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("hosts").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) GetRevision(ctx context.Context, name string, revision int) (*objv2.Host, error) {
+	result := &objv2.Host{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) RevertRevision(ctx context.Context, name string, revision int) (*objv2.Host, error) {
+	result := &objv2.Host{}
+	if err := c.RESTClient.Put().
+		Version("v2").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) DeleteRevision(ctx context.Context, name string, revision int) (*objv2.Host, error) {
+	result := &objv2.Host{}
+	if err := c.RESTClient.Delete().
+		Version("v2").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c hosts) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("hosts").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -201,12 +577,13 @@ func (c jobs) Get(ctx context.Context, name string) (*objv2.Job, error) {
 	return result, nil
 }
 
-func (c jobs) Create(ctx context.Context, obj *objv2.Job) (*objv2.Job, error) {
+func (c jobs) Create(ctx context.Context, obj *objv2.Job, opts ...core.OpOpt) (*objv2.Job, error) {
 	result := &objv2.Job{}
 	if err := c.RESTClient.Post().
 		Version("v2").
 		Resource("jobs").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -226,13 +603,149 @@ func (c jobs) List(ctx context.Context) ([]objv2.Job, error) {
 	return result, nil
 }
 
-func (c jobs) Update(ctx context.Context, obj *objv2.Job) (*objv2.Job, error) {
+func (c jobs) ListWithRevision(ctx context.Context) ([]objv2.Job, int64, error) {
+	result := []objv2.Job{}
+	resp := c.RESTClient.Get().
+		Version("v2").
+		Resource("jobs").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c jobs) ListPage(ctx context.Context, limit int64, continueToken string) ([]objv2.Job, string, error) {
+	result := []objv2.Job{}
+	resp := c.RESTClient.Get().
+		Version("v2").
+		Resource("jobs").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c jobs) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]objv2.Job, error) {
+	result := []objv2.Job{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("jobs").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("v2").
+		Resource("jobs").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return objv2.NewJob()
+		})
+}
+
+func (c jobs) Update(ctx context.Context, obj *objv2.Job, opts ...core.OpOpt) (*objv2.Job, error) {
+	result := &objv2.Job{}
+	if err := c.RESTClient.Put().
+		Version("v2").
+		Resource("jobs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*objv2.Job, error) {
+	result := &objv2.Job{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("v2").
+		Resource("jobs").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Apply(ctx context.Context, obj *objv2.Job, opts ...core.OpOpt) (*objv2.Job, error) {
+	result := &objv2.Job{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("v2").
+		Resource("jobs").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*objv2.Job, error) {
+	result := &objv2.Job{}
+	if err := c.RESTClient.Delete().
+		Version("v2").
+		Resource("jobs").
+		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) UpdateStatus(ctx context.Context, obj *objv2.Job, opts ...core.OpOpt) (*objv2.Job, error) {
 	result := &objv2.Job{}
 	if err := c.RESTClient.Put().
 		Version("v2").
 		Resource("jobs").
 		Name(obj.Metadata.Name).
+		SubResource("status").
 		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) ListRevisions(ctx context.Context, name string) ([]objv2.Job, error) {
+	result := []objv2.Job{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("jobs").
+		Name(name).
+		SubResource("revisions").
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -240,12 +753,55 @@ func (c jobs) Update(ctx context.Context, obj *objv2.Job) (*objv2.Job, error) {
 	return result, nil
 }
 
-func (c jobs) Delete(ctx context.Context, name string) (*objv2.Job, error) {
+func (c jobs) GetRevision(ctx context.Context, name string, revision int) (*objv2.Job, error) {
+	result := &objv2.Job{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("jobs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) RevertRevision(ctx context.Context, name string, revision int) (*objv2.Job, error) {
+	result := &objv2.Job{}
+	if err := c.RESTClient.Put().
+		Version("v2").
+		Resource("jobs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) DeleteRevision(ctx context.Context, name string, revision int) (*objv2.Job, error) {
 	result := &objv2.Job{}
 	if err := c.RESTClient.Delete().
 		Version("v2").
 		Resource("jobs").
 		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c jobs) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("v2").
+		Resource("jobs").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -7,9 +7,13 @@ package {{ .Package }}
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/wujie1993/waves/pkg/client/rest"
+	"github.com/wujie1993/waves/pkg/orm/core"
 	obj{{ .Package }} "github.com/wujie1993/waves/pkg/orm/{{ .Package }}"
+	"github.com/wujie1993/waves/pkg/patch"
 )
 
 type Client struct {
@@ -52,7 +56,7 @@ func (c {{ ToLower .Name }}s) Get(ctx context.Context, name string) (*obj{{ $pac
 	return result, nil
 }
 
-func (c {{ ToLower .Name }}s) Create(ctx context.Context, obj *obj{{ $package }}.{{ .Name }}) (*obj{{ $package }}.{{ .Name }}, error) {
+func (c {{ ToLower .Name }}s) Create(ctx context.Context, obj *obj{{ $package }}.{{ .Name }}, opts ...core.OpOpt) (*obj{{ $package }}.{{ .Name }}, error) {
 	result := &obj{{ $package }}.{{ .Name }}{}
 	if err := c.RESTClient.Post().
 		Version("{{ $package }}").
@@ -61,6 +65,7 @@ func (c {{ ToLower .Name }}s) Create(ctx context.Context, obj *obj{{ $package }}
 		{{- end }}
 		Resource("{{ ToLower .Name }}s").
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -83,7 +88,80 @@ func (c {{ ToLower .Name }}s) List(ctx context.Context) ([]obj{{ $package }}.{{
 	return result, nil
 }
 
-func (c {{ ToLower .Name }}s) Update(ctx context.Context, obj *obj{{ $package }}.{{ .Name }}) (*obj{{ $package }}.{{ .Name }}, error) {
+func (c {{ ToLower .Name }}s) ListWithRevision(ctx context.Context) ([]obj{{ $package }}.{{ .Name }}, int64, error) {
+	result := []obj{{ $package }}.{{ .Name }}{}
+	resp := c.RESTClient.Get().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, 0, err
+	}
+	return result, resp.Revision(), nil
+}
+
+func (c {{ ToLower .Name }}s) ListPage(ctx context.Context, limit int64, continueToken string) ([]obj{{ $package }}.{{ .Name }}, string, error) {
+	result := []obj{{ $package }}.{{ .Name }}{}
+	resp := c.RESTClient.Get().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Params(map[string]string{
+			"limit":    strconv.FormatInt(limit, 10),
+			"continue": continueToken,
+		}).
+		Do(ctx)
+	if err := resp.Into(&result); err != nil {
+		return nil, "", err
+	}
+	return result, resp.Continue(), nil
+}
+
+func (c {{ ToLower .Name }}s) ListBySelector(ctx context.Context, labelSelector string, fieldSelector string) ([]obj{{ $package }}.{{ .Name }}, error) {
+	result := []obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Get().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Params(map[string]string{
+			"labelSelector": labelSelector,
+			"fieldSelector": fieldSelector,
+		}).
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) Watch(ctx context.Context, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	params := map[string]string{
+		"watch": "true",
+	}
+	if revision > 0 {
+		params["revision"] = strconv.FormatInt(revision, 10)
+	}
+	return c.RESTClient.Get().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(name).
+		Params(params).
+		Watch(ctx, func() core.ApiObject {
+			return obj{{ $package }}.New{{ .Name }}()
+		})
+}
+
+func (c {{ ToLower .Name }}s) Update(ctx context.Context, obj *obj{{ $package }}.{{ .Name }}, opts ...core.OpOpt) (*obj{{ $package }}.{{ .Name }}, error) {
 	result := &obj{{ $package }}.{{ .Name }}{}
 	if err := c.RESTClient.Put().
 		Version("{{ $package }}").
@@ -93,6 +171,7 @@ func (c {{ ToLower .Name }}s) Update(ctx context.Context, obj *obj{{ $package }}
 		Resource("{{ ToLower .Name }}s").
 		Name(obj.Metadata.Name).
 		Data(obj).
+		Options(opts...).
 		Do(ctx).
 		Into(&result); err != nil {
 		return nil, err
@@ -100,7 +179,43 @@ func (c {{ ToLower .Name }}s) Update(ctx context.Context, obj *obj{{ $package }}
 	return result, nil
 }
 
-func (c {{ ToLower .Name }}s) Delete(ctx context.Context, name string) (*obj{{ $package }}.{{ .Name }}, error) {
+func (c {{ ToLower .Name }}s) Patch(ctx context.Context, name string, patchType string, data []byte, opts ...core.OpOpt) (*obj{{ $package }}.{{ .Name }}, error) {
+	result := &obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Patch(patchType).
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(name).
+		Body(data).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) Apply(ctx context.Context, obj *obj{{ $package }}.{{ .Name }}, opts ...core.OpOpt) (*obj{{ $package }}.{{ .Name }}, error) {
+	result := &obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Patch(patch.TypeApplyPatch).
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(obj.Metadata.Name).
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) Delete(ctx context.Context, name string, opts ...core.OpOpt) (*obj{{ $package }}.{{ .Name }}, error) {
 	result := &obj{{ $package }}.{{ .Name }}{}
 	if err := c.RESTClient.Delete().
 		Version("{{ $package }}").
@@ -109,6 +224,111 @@ func (c {{ ToLower .Name }}s) Delete(ctx context.Context, name string) (*obj{{ $
 		{{- end }}
 		Resource("{{ ToLower .Name }}s").
 		Name(name).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) UpdateStatus(ctx context.Context, obj *obj{{ $package }}.{{ .Name }}, opts ...core.OpOpt) (*obj{{ $package }}.{{ .Name }}, error) {
+	result := &obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Put().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(obj.Metadata.Name).
+		SubResource("status").
+		Data(obj).
+		Options(opts...).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) ListRevisions(ctx context.Context, name string) ([]obj{{ $package }}.{{ .Name }}, error) {
+	result := []obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Get().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(name).
+		SubResource("revisions").
+		Do(ctx).
+		Into(&result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) GetRevision(ctx context.Context, name string, revision int) (*obj{{ $package }}.{{ .Name }}, error) {
+	result := &obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Get().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) RevertRevision(ctx context.Context, name string, revision int) (*obj{{ $package }}.{{ .Name }}, error) {
+	result := &obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Put().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) DeleteRevision(ctx context.Context, name string, revision int) (*obj{{ $package }}.{{ .Name }}, error) {
+	result := &obj{{ $package }}.{{ .Name }}{}
+	if err := c.RESTClient.Delete().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d", revision)).
+		Do(ctx).
+		Into(result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c {{ ToLower .Name }}s) DiffRevisions(ctx context.Context, name string, revision int, target int) (*core.RevisionDiff, error) {
+	result := &core.RevisionDiff{}
+	if err := c.RESTClient.Get().
+		Version("{{ $package }}").
+		{{- if .Namespaced }}
+		Namespace(c.namespace).
+		{{- end }}
+		Resource("{{ ToLower .Name }}s").
+		Name(name).
+		SubResource(fmt.Sprintf("revisions/%d/diff/%d", revision, target)).
 		Do(ctx).
 		Into(result); err != nil {
 		return nil, err
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/orm/v2"
+	"github.com/wujie1993/waves/pkg/rbac"
+)
+
+// CheckAccess 判断当前用户是否被授权执行操作，未启用认证时总是允许
+func (c *BaseController) CheckAccess(ctx *gin.Context, verb string, kind string, namespace string, name string) (bool, error) {
+	username := ctx.GetString(auth.ContextKeyUsername)
+	if username == "" {
+		return true, nil
+	}
+	attrs := rbac.Attributes{
+		Verb:      verb,
+		Kind:      kind,
+		Namespace: namespace,
+		Name:      name,
+	}
+	// 令牌设置了授权范围时，只能执行同时被角色与授权范围授权的操作
+	if scopes, ok := ctx.Get(auth.ContextKeyScopes); ok && !rbac.MatchRules(scopes.([]v1.PolicyRule), attrs) {
+		return false, nil
+	}
+	return rbac.Authorize(username, attrs)
+}
+
+// Authorize 校验当前用户是否被授权执行操作，未被授权时返回403并返回false
+func (c *BaseController) Authorize(ctx *gin.Context, verb string, kind string, namespace string, name string) bool {
+	allowed, err := c.CheckAccess(ctx, verb, kind, namespace, name)
+	if err != nil {
+		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		return false
+	}
+	if !allowed {
+		keys := []string{}
+		for _, key := range []string{namespace, name} {
+			if key != "" {
+				keys = append(keys, key)
+			}
+		}
+		c.ResponseError(ctx, e.AccessDeniedError{
+			User: ctx.GetString(auth.ContextKeyUsername),
+			Verb: verb,
+			Kind: kind,
+			Key:  strings.Join(keys, "/"),
+		})
+		return false
+	}
+	return true
+}
+
+// authorize 校验当前用户是否被授权对控制器所管理的资源执行操作
+func (c *BaseController) authorize(ctx *gin.Context, verb string, namespace string, name string) bool {
+	if !c.registry.Namespaced() {
+		namespace = ""
+	}
+	return c.Authorize(ctx, verb, c.registry.GVK().Kind, namespace, name)
+}
+
+// authorizeAction 写入的应用实例修改了Action时，额外校验action操作的授权
+func (c *BaseController) authorizeAction(ctx *gin.Context, namespace string, name string, obj core.ApiObject) bool {
+	if ctx.GetString(auth.ContextKeyUsername) == "" || c.registry.GVK().Kind != core.KindAppInstance {
+		return true
+	}
+
+	current, err := c.registry.Get(context.TODO(), namespace, name)
+	if err != nil {
+		log.Error(err)
+		c.ResponseError(ctx, err)
+		return false
+	}
+	if current == nil && specAction(obj) == "" || current != nil && specAction(current) == specAction(obj) {
+		return true
+	}
+	return c.authorize(ctx, core.VerbAction, namespace, name)
+}
+
+// accessFilter 只保留当前用户被授权执行操作的资源对象
+func (c *BaseController) accessFilter(verb string) ListFilter {
+	return func(ctx *gin.Context, objs []core.ApiObject) []core.ApiObject {
+		result := []core.ApiObject{}
+		for _, obj := range objs {
+			metadata := obj.GetMetadata()
+			allowed, err := c.CheckAccess(ctx, verb, c.registry.GVK().Kind, metadata.Namespace, metadata.Name)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			if allowed {
+				result = append(result, obj)
+			}
+		}
+		return result
+	}
+}
+
+// specAction 获取应用实例中用于触发安装、卸载等动作的Action，其他资源返回空
+func specAction(obj core.ApiObject) string {
+	switch obj := obj.(type) {
+	case *v1.AppInstance:
+		return obj.Spec.Action
+	case *v2.AppInstance:
+		return obj.Spec.Action
+	}
+	return ""
+}
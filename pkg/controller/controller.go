@@ -3,6 +3,7 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin/binding"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/wujie1993/waves/pkg/auth"
 	"github.com/wujie1993/waves/pkg/e"
 	"github.com/wujie1993/waves/pkg/orm"
 	"github.com/wujie1993/waves/pkg/orm/core"
@@ -23,6 +25,10 @@ type Response struct {
 	OpCode int         `json:"OpCode"`
 	OpDesc string      `json:"OpDesc"`
 	Data   interface{} `json:"Data"`
+	// 分页获取时用于获取下一页的令牌，为空表示已到达最后一页
+	Continue string `json:"Continue,omitempty"`
+	// 列举时存储的全局修订版本号，可作为revision参数从该版本之后开始侦听变更，分页获取时应使用第一页返回的修订版本号
+	Revision int64 `json:"Revision,omitempty"`
 }
 
 type BaseController struct {
@@ -37,27 +43,167 @@ func (c *BaseController) Response(ctx *gin.Context, httpCode, opCode int, opMsg
 		OpDesc: opMsg,
 		Data:   data,
 	}
+	c.respond(ctx, httpCode, resp)
+}
 
+func (c *BaseController) respond(ctx *gin.Context, httpCode int, resp Response) {
 	c.recordAudit(ctx, httpCode, resp)
 
 	ctx.JSON(httpCode, resp)
 }
 
+// ResponseError 根据错误类型返回对应的状态码，请求参数或备份归档无效时返回400，资源冲突时返回409，被准入控制拒绝或未被授权时返回403，认证失败时返回401，侦听的修订版本已过期时返回410
+func (c *BaseController) ResponseError(ctx *gin.Context, err error) {
+	switch err.(type) {
+	case e.UnauthorizedError:
+		c.Response(ctx, http.StatusUnauthorized, e.ERROR_AUTH_CHECK_TOKEN_FAIL, err.Error(), nil)
+	case e.ResourceConflictError:
+		c.Response(ctx, http.StatusConflict, e.CONFLICT, err.Error(), nil)
+	case e.ResourceNotFoundError:
+		c.Response(ctx, http.StatusNotFound, e.ERROR, err.Error(), nil)
+	case e.InvalidContinueError, e.InvalidSelectorError, e.InvalidPatchError, e.InvalidPropagationPolicyError, e.InvalidFieldError, e.InvalidFieldsError, e.InvalidBackupError:
+		c.Response(ctx, http.StatusBadRequest, e.INVALID_PARAMS, err.Error(), nil)
+	case e.UnsupportedPatchTypeError:
+		c.Response(ctx, http.StatusUnsupportedMediaType, e.INVALID_PARAMS, err.Error(), nil)
+	case e.AdmissionDeniedError, e.AccessDeniedError:
+		c.Response(ctx, http.StatusForbidden, e.FORBIDDEN, err.Error(), nil)
+	case e.ExpiredRevisionError:
+		c.Response(ctx, http.StatusGone, e.INVALID_PARAMS, err.Error(), nil)
+	default:
+		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+	}
+}
+
+// List 列举资源对象，支持通过limit与continue查询参数分页获取，通过labelSelector与fieldSelector查询参数过滤，默认按名称排序。
+// 通过sortBy与order查询参数按任意字段排序，通过q查询参数按名称或ShortName注解模糊搜索，此时在过滤与排序后的全部结果上分页。
+// 控制器存在过滤器时同样在过滤后的全部结果上分页，避免分页后再过滤导致返回的页不足limit个对象，否则直接由存储分页。
+// 通过fields查询参数只返回指定的字段，如fields=metadata.createTime,spec.replicas。
+// 响应中的Revision为列举时存储的全局修订版本号，可作为侦听请求的revision查询参数，从列举结果之后开始侦听变更。
+// 项目与命名空间可以只授权其中的部分对象，未被授权列举所有对象时只返回被授权的对象
 func (c *BaseController) List(ctx *gin.Context, filts ...ListFilter) {
 	namespace := ctx.Param("namespace")
 
-	result, err := c.registry.List(context.TODO(), namespace)
+	kind := c.registry.GVK().Kind
+	if kind == core.KindProject || kind == core.KindNamespace {
+		allowed, err := c.CheckAccess(ctx, core.VerbList, kind, "", "")
+		if err != nil {
+			c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+			return
+		}
+		if !allowed {
+			filts = append([]ListFilter{c.accessFilter(core.VerbList)}, filts...)
+		}
+	} else if !c.authorize(ctx, core.VerbList, namespace, "") {
+		return
+	}
+
+	watch, err := isWatch(ctx)
 	if err != nil {
-		log.Error(err)
-		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	} else if watch {
+		c.Watch(ctx, namespace, "", filts...)
 		return
 	}
 
-	for _, filt := range filts {
-		result = filt(ctx, result)
+	desc, err := listOrder(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	fields, err := core.ParseFields(ctx.Query("fields"))
+	if err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	sortBy := ctx.Query("sortBy")
+	if sortBy == "" && desc {
+		sortBy = "metadata.name"
 	}
+	keyword := ctx.Query("q")
 
-	c.Response(ctx, 200, e.SUCCESS, "", result)
+	var limit int64
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err = strconv.ParseInt(limitStr, 10, 64)
+		if err != nil || limit < 0 {
+			c.Response(ctx, 400, e.INVALID_PARAMS, "invalid limit "+limitStr, nil)
+			return
+		}
+	}
+	opts := []core.OpOpt{}
+	if labelSelector := ctx.Query("labelSelector"); labelSelector != "" {
+		opts = append(opts, core.WithLabelSelector(labelSelector))
+	}
+	if fieldSelector := ctx.Query("fieldSelector"); fieldSelector != "" {
+		opts = append(opts, core.WithFieldSelector(fieldSelector))
+	}
+
+	var result core.ApiObjectList
+	var continueToken string
+	var revision int64
+	if sortBy != "" || keyword != "" || len(filts) > 0 {
+		// 排序、搜索与过滤需要在全部对象上进行，分页令牌记录的是结果中的位置
+		offset, err := decodeOffsetContinue(ctx.Query("continue"))
+		if err != nil {
+			c.ResponseError(ctx, err)
+			return
+		}
+		var list core.ApiObjectList
+		list, _, revision, err = c.registry.ListPage(context.TODO(), namespace, opts...)
+		if err != nil {
+			log.Error(err)
+			c.ResponseError(ctx, err)
+			return
+		}
+		// 先按名称排序，过滤器可以调整默认的顺序
+		core.SortByKey(list)
+		for _, filt := range filts {
+			list = filt(ctx, list)
+		}
+		matched := core.ApiObjectList{}
+		for _, obj := range list {
+			if core.MatchesKeyword(obj, keyword) {
+				matched = append(matched, obj)
+			}
+		}
+		if sortBy != "" {
+			core.SortByField(matched, sortBy, desc)
+		}
+		result, continueToken = pageList(matched, offset, limit)
+	} else {
+		if limit > 0 {
+			opts = append(opts, core.WithLimit(limit))
+		}
+		if token := ctx.Query("continue"); token != "" {
+			opts = append(opts, core.WithContinue(token))
+		}
+		result, continueToken, revision, err = c.registry.ListPage(context.TODO(), namespace, opts...)
+		if err != nil {
+			log.Error(err)
+			c.ResponseError(ctx, err)
+			return
+		}
+		// 未分页时存储返回的对象是无序的，按名称排序
+		core.SortByKey(result)
+	}
+
+	var data interface{} = result
+	if len(fields) > 0 {
+		projected, err := projectList(result, fields)
+		if err != nil {
+			log.Error(err)
+			c.ResponseError(ctx, err)
+			return
+		}
+		data = projected
+	}
+
+	c.respond(ctx, 200, Response{
+		OpCode:   e.SUCCESS,
+		Data:     data,
+		Continue: continueToken,
+		Revision: revision,
+	})
 }
 
 func (c *BaseController) Get(ctx *gin.Context) {
@@ -69,6 +215,19 @@ func (c *BaseController) Get(ctx *gin.Context) {
 		}
 	}
 
+	if !c.authorize(ctx, core.VerbGet, namespace, name) {
+		return
+	}
+
+	watch, err := isWatch(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	} else if watch {
+		c.Watch(ctx, namespace, name)
+		return
+	}
+
 	result, err := c.registry.Get(context.TODO(), namespace, name)
 	if err != nil {
 		log.Error(err)
@@ -105,10 +264,21 @@ func (c *BaseController) Create(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.registry.Create(context.TODO(), obj)
+	opts, err := c.writeOpts(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
+	metadata := obj.GetMetadata()
+	if !c.authorize(ctx, core.VerbCreate, metadata.Namespace, metadata.Name) || !c.authorizeAction(ctx, metadata.Namespace, metadata.Name, obj) {
+		return
+	}
+
+	result, err := c.registry.Create(context.TODO(), obj, opts...)
 	if err != nil {
 		log.Error(err)
-		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		c.ResponseError(ctx, err)
 		return
 	}
 
@@ -142,10 +312,20 @@ func (c *BaseController) Update(ctx *gin.Context) {
 	metadata.Name = name
 	obj.SetMetadata(metadata)
 
-	result, err := c.registry.Update(context.TODO(), obj)
+	opts, err := c.writeOpts(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
+	if !c.authorize(ctx, core.VerbUpdate, namespace, name) || !c.authorizeAction(ctx, namespace, name, obj) {
+		return
+	}
+
+	result, err := c.registry.Update(context.TODO(), obj, opts...)
 	if err != nil {
 		log.Error(err)
-		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		c.ResponseError(ctx, err)
 		return
 	}
 
@@ -159,6 +339,70 @@ func (c *BaseController) Update(ctx *gin.Context) {
 	c.Response(ctx, 200, e.SUCCESS, "", result)
 }
 
+// Patch 使用补丁更新资源对象，补丁类型由请求头Content-Type指定，
+// 支持application/merge-patch+json、application/json-patch+json与application/apply-patch+json
+func (c *BaseController) Patch(ctx *gin.Context) {
+	namespace := ctx.Param("namespace")
+	name := ctx.Param("name")
+	if name == "" {
+		if nameData, ok := ctx.Get("name"); ok {
+			name = nameData.(string)
+		}
+	}
+
+	data, err := ctx.GetRawData()
+	if err != nil {
+		log.Error(err)
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	// 缓存请求体用于记录审计
+	ctx.Set(gin.BodyBytesKey, data)
+
+	opts, err := c.writeOpts(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
+	if !c.authorize(ctx, core.VerbUpdate, namespace, name) {
+		return
+	}
+	// 通过试运行获取补丁应用后的对象，用于判断是否修改了应用实例的Action
+	if ctx.GetString(auth.ContextKeyUsername) != "" && c.registry.GVK().Kind == core.KindAppInstance {
+		preview, err := c.registry.Patch(context.TODO(), namespace, name, ctx.ContentType(), data, append(opts, core.WithDryRun())...)
+		if err != nil {
+			log.Error(err)
+			c.ResponseError(ctx, err)
+			return
+		}
+		if preview != nil && !c.authorizeAction(ctx, namespace, name, preview) {
+			return
+		}
+	}
+
+	result, err := c.registry.Patch(context.TODO(), namespace, name, ctx.ContentType(), data, opts...)
+	if err != nil {
+		log.Error(err)
+		c.ResponseError(ctx, err)
+		return
+	}
+
+	if result == nil {
+		meta := core.Metadata{
+			Namespace: namespace,
+			Name:      name,
+		}
+		key := meta.GetKey(c.registry.GVK().Kind, c.registry.Namespaced())
+		err := e.Errorf("%s not found", key)
+		c.Response(ctx, 404, e.ERROR, err.Error(), nil)
+		return
+	}
+
+	c.Response(ctx, 200, e.SUCCESS, "", result)
+}
+
+// Delete 删除资源对象，通过propagationPolicy查询参数指定依赖对象的级联删除策略
 func (c *BaseController) Delete(ctx *gin.Context) {
 	namespace := ctx.Param("namespace")
 	name := ctx.Param("name")
@@ -174,10 +418,23 @@ func (c *BaseController) Delete(ctx *gin.Context) {
 		return
 	}
 
-	result, err := c.registry.Delete(context.TODO(), namespace, name)
+	opts, err := c.writeOpts(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	if policy := ctx.Query("propagationPolicy"); policy != "" {
+		opts = append(opts, core.WithPropagationPolicy(policy))
+	}
+
+	if !c.authorize(ctx, core.VerbDelete, namespace, name) {
+		return
+	}
+
+	result, err := c.registry.Delete(context.TODO(), namespace, name, opts...)
 	if err != nil {
 		log.Error(err)
-		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		c.ResponseError(ctx, err)
 		return
 	}
 
@@ -195,6 +452,76 @@ func (c *BaseController) Delete(ctx *gin.Context) {
 	c.Response(ctx, 200, e.SUCCESS, "", result)
 }
 
+// GetStatus 获取资源对象，用于读取状态子资源
+func (c *BaseController) GetStatus(ctx *gin.Context) {
+	c.Get(ctx)
+}
+
+// PutStatus 只更新资源对象的状态，忽略请求中状态以外的内容。
+// 请求中指定了资源版本号时，要求与资源当前的版本号一致，避免根据过时的Spec上报状态
+func (c *BaseController) PutStatus(ctx *gin.Context) {
+	namespace := ctx.Param("namespace")
+	name := ctx.Param("name")
+
+	obj, err := orm.New(c.registry.GVK())
+	if err != nil {
+		log.Error(err)
+		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		return
+	}
+
+	if err := ctx.ShouldBindBodyWith(obj, binding.JSON); err != nil {
+		log.Error(err)
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
+	opts, err := c.writeOpts(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	opts = append(opts, core.WithResourceVersion(obj.GetMetadata().ResourceVersion))
+
+	if !c.authorize(ctx, core.VerbUpdate, namespace, name) {
+		return
+	}
+
+	result, err := c.registry.UpdateStatus(namespace, name, obj.GetStatus(), opts...)
+	if err != nil {
+		log.Error(err)
+		c.ResponseError(ctx, err)
+		return
+	}
+
+	c.Response(ctx, 200, e.SUCCESS, "", result)
+}
+
+// writeOpts 解析写入请求的通用查询参数，dryRun=true时只返回将要写入的对象而不实际写入
+func (c *BaseController) writeOpts(ctx *gin.Context) ([]core.OpOpt, error) {
+	opts := []core.OpOpt{}
+	dryRun, err := isDryRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		opts = append(opts, core.WithDryRun())
+	}
+	return opts, nil
+}
+
+func isDryRun(ctx *gin.Context) (bool, error) {
+	dryRunStr := ctx.Query("dryRun")
+	if dryRunStr == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(dryRunStr)
+	if err != nil {
+		return false, e.Errorf("invalid dryRun %s", dryRunStr)
+	}
+	return dryRun, nil
+}
+
 func (c *BaseController) ListRevisions(ctx *gin.Context) {
 	namespace := ctx.Param("namespace")
 	name := ctx.Param("name")
@@ -204,6 +531,10 @@ func (c *BaseController) ListRevisions(ctx *gin.Context) {
 		return
 	}
 
+	if !c.authorize(ctx, core.VerbGet, namespace, name) {
+		return
+	}
+
 	result, err := c.revisioner.ListRevisions(context.TODO(), namespace, name)
 	if err != nil {
 		log.Error(err)
@@ -229,6 +560,10 @@ func (c *BaseController) GetRevision(ctx *gin.Context) {
 		return
 	}
 
+	if !c.authorize(ctx, core.VerbGet, namespace, name) {
+		return
+	}
+
 	result, err := c.revisioner.GetRevision(context.TODO(), namespace, name, revision)
 	if err != nil {
 		log.Error(err)
@@ -259,10 +594,14 @@ func (c *BaseController) PutRevision(ctx *gin.Context) {
 		return
 	}
 
+	if !c.authorize(ctx, core.VerbUpdate, namespace, name) {
+		return
+	}
+
 	result, err := c.revisioner.RevertRevision(context.TODO(), namespace, name, revision)
 	if err != nil {
 		log.Error(err)
-		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		c.ResponseError(ctx, err)
 		return
 	}
 
@@ -289,6 +628,10 @@ func (c *BaseController) DeleteRevision(ctx *gin.Context) {
 		return
 	}
 
+	if !c.authorize(ctx, core.VerbDelete, namespace, name) {
+		return
+	}
+
 	result, err := c.revisioner.DeleteRevision(context.TODO(), namespace, name, revision)
 	if err != nil {
 		log.Error(err)
@@ -304,13 +647,82 @@ func (c *BaseController) DeleteRevision(ctx *gin.Context) {
 	c.Response(ctx, 200, e.SUCCESS, "", result)
 }
 
+// DiffRevision 比较资源的两个修订版本，修订版本号为资源当前的版本号时与资源的当前内容比较
+func (c *BaseController) DiffRevision(ctx *gin.Context) {
+	namespace := ctx.Param("namespace")
+	name := ctx.Param("name")
+	revisions := []int{}
+	for _, param := range []string{"revision", "target"} {
+		revision, err := strconv.Atoi(ctx.Param(param))
+		if err != nil {
+			log.Error(err)
+			c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+			return
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if c.revisioner == nil {
+		c.Response(ctx, 400, e.ERROR, "unsupport revision", nil)
+		return
+	}
+
+	if !c.authorize(ctx, core.VerbGet, namespace, name) {
+		return
+	}
+
+	objs := []core.ApiObject{}
+	for _, revision := range revisions {
+		obj, err := c.getRevisionOrCurrent(namespace, name, revision)
+		if err != nil {
+			log.Error(err)
+			c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+			return
+		}
+		if obj == nil {
+			c.Response(ctx, 404, e.ERROR, fmt.Sprintf("revision %d not found", revision), nil)
+			return
+		}
+		objs = append(objs, obj)
+	}
+
+	result, err := core.NewRevisionDiff(objs[0], objs[1])
+	if err != nil {
+		log.Error(err)
+		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		return
+	}
+
+	c.Response(ctx, 200, e.SUCCESS, "", result)
+}
+
+// getRevisionOrCurrent 获取资源的指定修订版本，修订版本号为资源当前的版本号时返回资源的当前内容
+func (c *BaseController) getRevisionOrCurrent(namespace string, name string, revision int) (core.ApiObject, error) {
+	obj, err := c.registry.Get(context.TODO(), namespace, name)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+	if obj.GetMetadata().ResourceVersion == revision {
+		return obj, nil
+	}
+	return c.revisioner.GetRevision(context.TODO(), namespace, name, revision)
+}
+
 func (c *BaseController) recordAudit(ctx *gin.Context, httpCode int, resp Response) {
+	// 未关联资源存储器的控制器与试运行的请求不记录审计
+	if c.registry == nil {
+		return
+	}
+	if dryRun, _ := isDryRun(ctx); dryRun {
+		return
+	}
+
 	audit := v1.NewAudit()
 
 	switch ctx.Request.Method {
 	case http.MethodPost:
 		audit.Spec.Action = core.AuditActionCreate
-	case http.MethodPut:
+	case http.MethodPut, http.MethodPatch:
 		audit.Spec.Action = core.AuditActionUpdate
 	case http.MethodDelete:
 		audit.Spec.Action = core.AuditActionDelete
@@ -318,6 +730,8 @@ func (c *BaseController) recordAudit(ctx *gin.Context, httpCode int, resp Respon
 		return
 	}
 
+	// 审计记录中隐藏请求与响应内容中的敏感字段
+	respObj, _ := resp.Data.(core.ApiObject)
 	if ctx.Request.Method == http.MethodPut || ctx.Request.Method == http.MethodPost {
 		reqObj, err := orm.New(c.registry.GVK())
 		if err != nil {
@@ -333,18 +747,41 @@ func (c *BaseController) recordAudit(ctx *gin.Context, httpCode int, resp Respon
 			log.Error(err)
 			return
 		}
-		audit.Spec.ReqBody = string(reqBodyData)
+		if audit.Spec.ReqBody, err = c.registry.Redact(reqObj, reqBodyData); err != nil {
+			log.Error(err)
+			return
+		}
+	} else if ctx.Request.Method == http.MethodPatch {
+		if body, ok := ctx.Get(gin.BodyBytesKey); ok {
+			// 补丁中敏感字段的路径可能依赖于资源对象的内容，更新失败时使用当前的资源对象
+			obj := respObj
+			if obj == nil {
+				obj, _ = c.registry.Get(context.TODO(), ctx.Param("namespace"), ctx.Param("name"))
+			}
+			reqBody, err := c.registry.Redact(obj, body.([]byte))
+			if err != nil {
+				log.Error(err)
+				return
+			}
+			audit.Spec.ReqBody = reqBody
+		}
 	}
 	if resp.Data != nil {
-		if data, err := json.Marshal(resp.Data); err != nil {
+		data, err := json.Marshal(resp.Data)
+		if err != nil {
 			log.Error(err)
 			return
+		}
+		if respObj != nil {
+			if audit.Spec.RespBody, err = c.registry.Redact(respObj, data); err != nil {
+				log.Error(err)
+				return
+			}
 		} else {
 			audit.Spec.RespBody = string(data)
 		}
 	}
-	respObj, ok := resp.Data.(core.ApiObject)
-	if ok {
+	if respObj != nil {
 		metadata := respObj.GetMetadata()
 		audit.Metadata.Annotations["ShortName"] = metadata.Annotations["ShortName"]
 		audit.Spec.ResourceRef = v1.ResourceRef{
@@ -360,6 +797,7 @@ func (c *BaseController) recordAudit(ctx *gin.Context, httpCode int, resp Respon
 		}
 	}
 	audit.Spec.SourceIP = ctx.ClientIP()
+	audit.Spec.User = ctx.GetString(auth.ContextKeyUsername)
 	audit.Spec.StatusCode = httpCode
 	audit.Spec.Msg = resp.OpDesc
 
@@ -373,8 +811,13 @@ func (c *BaseController) SetRevisioner(revisioner registry.Revisioner) {
 }
 
 func NewController(registry registry.ApiObjectRegistry) BaseController {
-	return BaseController{
+	c := BaseController{
 		helper:   orm.GetHelper(),
 		registry: registry,
 	}
+	// 存储器启用了修订历史时，控制器默认使用存储器的修订历史记录器
+	if registry != nil {
+		c.revisioner = registry.Revisioner()
+	}
+	return c
 }
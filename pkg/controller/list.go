@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+const (
+	orderAsc  = "asc"
+	orderDesc = "desc"
+
+	// 排序分页令牌的前缀，用于与按存储键分页的令牌区分
+	offsetContinuePrefix = "offset:"
+)
+
+// listOrder 解析排序方向，默认为升序
+func listOrder(ctx *gin.Context) (bool, error) {
+	switch order := strings.ToLower(ctx.Query("order")); order {
+	case "", orderAsc:
+		return false, nil
+	case orderDesc:
+		return true, nil
+	default:
+		return false, e.Errorf("invalid order %s", order)
+	}
+}
+
+// encodeOffsetContinue 生成排序后分页的令牌，令牌记录下一页在排序结果中的起始位置
+func encodeOffsetContinue(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(offsetContinuePrefix + strconv.Itoa(offset)))
+}
+
+// decodeOffsetContinue 解析排序后分页的令牌，令牌为空时从第一个对象开始
+func decodeOffsetContinue(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(data), offsetContinuePrefix) {
+		return 0, e.InvalidContinueError{Continue: token}
+	}
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(data), offsetContinuePrefix))
+	if err != nil || offset < 0 {
+		return 0, e.InvalidContinueError{Continue: token}
+	}
+	return offset, nil
+}
+
+// pageList 从排序结果中截取一页，返回该页对象与获取下一页的令牌
+func pageList(list core.ApiObjectList, offset int, limit int64) (core.ApiObjectList, string) {
+	if offset >= len(list) {
+		return core.ApiObjectList{}, ""
+	}
+	list = list[offset:]
+	if limit <= 0 || int64(len(list)) <= limit {
+		return list, ""
+	}
+	return list[:limit], encodeOffsetContinue(offset + int(limit))
+}
+
+// projectList 对列表中的每个对象进行字段投影
+func projectList(list core.ApiObjectList, paths [][]string) ([]map[string]interface{}, error) {
+	result := []map[string]interface{}{}
+	for _, obj := range list {
+		projected, err := core.ProjectFields(obj, paths)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, projected)
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestListSortAndProjection(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	configMapCtl := controller.NewController(v1.NewConfigMapRegistry())
+	router.GET("/api/v1/namespaces/:namespace/configmaps", func(ctx *gin.Context) { configMapCtl.List(ctx) })
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	for _, name := range []string{"cm-b", "cm-a", "cm-c"} {
+		configMap := v1.NewConfigMap()
+		configMap.Metadata.Namespace = core.DefaultNamespace
+		configMap.Metadata.Name = name
+		configMap.Metadata.Annotations["ShortName"] = "配置" + strings.ToUpper(strings.TrimPrefix(name, "cm-"))
+		configMap.Data = map[string]string{"key": "value"}
+		if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	list := func(query string) ([]map[string]interface{}, string) {
+		resp, err := http.Get(server.URL + "/api/v1/namespaces/" + core.DefaultNamespace + "/configmaps?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body struct {
+			Data     []map[string]interface{}
+			Continue string
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status %d for %s", resp.StatusCode, query)
+		}
+		return body.Data, body.Continue
+	}
+	names := func(items []map[string]interface{}) string {
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item["Metadata"].(map[string]interface{})["Name"].(string))
+		}
+		return strings.Join(result, ",")
+	}
+
+	// 默认按名称排序
+	if items, _ := list(""); names(items) != "cm-a,cm-b,cm-c" {
+		t.Fatalf("unexpected default order %s", names(items))
+	}
+
+	// 按创建时间倒序分页
+	items, continueToken := list("sortBy=metadata.createTime&order=desc&limit=2")
+	if names(items) != "cm-c,cm-a" || continueToken == "" {
+		t.Fatalf("unexpected first page %s, continue %q", names(items), continueToken)
+	}
+	items, continueToken = list("sortBy=metadata.createTime&order=desc&limit=2&continue=" + continueToken)
+	if names(items) != "cm-b" || continueToken != "" {
+		t.Fatalf("unexpected last page %s, continue %q", names(items), continueToken)
+	}
+
+	// 按名称或ShortName注解搜索
+	if items, _ := list("q=CM-B"); names(items) != "cm-b" {
+		t.Fatalf("unexpected search result by name %s", names(items))
+	}
+	if items, _ := list("q=" + url.QueryEscape("配置C")); names(items) != "cm-c" {
+		t.Fatalf("unexpected search result by short name %s", names(items))
+	}
+
+	// 字段投影只保留指定字段与对象标识
+	items, _ = list("fields=metadata.createTime")
+	if len(items) != 3 {
+		t.Fatalf("unexpected projected items %+v", items)
+	}
+	metadata := items[0]["Metadata"].(map[string]interface{})
+	if _, ok := items[0]["Data"]; ok || metadata["CreateTime"] == nil || metadata["Annotations"] != nil || items[0]["Kind"] == nil {
+		t.Fatalf("unexpected projected item %+v", items[0])
+	}
+
+	for _, query := range []string{"order=random", "fields=metadata..name", "sortBy=metadata.name&continue=invalid"} {
+		resp, err := http.Get(server.URL + "/api/v1/namespaces/" + core.DefaultNamespace + "/configmaps?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestListFilterBeforePaging(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	configMapCtl := controller.NewController(v1.NewConfigMapRegistry())
+	// 过滤掉名称以-x结尾的对象
+	filter := func(ctx *gin.Context, objs []core.ApiObject) []core.ApiObject {
+		result := []core.ApiObject{}
+		for _, obj := range objs {
+			if !strings.HasSuffix(obj.GetKey(), "-x") {
+				result = append(result, obj)
+			}
+		}
+		return result
+	}
+	router.GET("/api/v1/namespaces/:namespace/configmaps", func(ctx *gin.Context) { configMapCtl.List(ctx, filter) })
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	for _, name := range []string{"cm-a-x", "cm-b-x", "cm-c", "cm-d-x", "cm-e", "cm-f"} {
+		configMap := v1.NewConfigMap()
+		configMap.Metadata.Namespace = core.DefaultNamespace
+		configMap.Metadata.Name = name
+		if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list := func(query string) (string, string) {
+		resp, err := http.Get(server.URL + "/api/v1/namespaces/" + core.DefaultNamespace + "/configmaps?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body struct {
+			Data     []map[string]interface{}
+			Continue string
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		result := []string{}
+		for _, item := range body.Data {
+			result = append(result, item["Metadata"].(map[string]interface{})["Name"].(string))
+		}
+		return strings.Join(result, ","), body.Continue
+	}
+
+	// 过滤在分页前生效，每页返回limit个过滤后的对象
+	names, continueToken := list("limit=2")
+	if names != "cm-c,cm-e" || continueToken == "" {
+		t.Fatalf("unexpected first page %s, continue %q", names, continueToken)
+	}
+	names, continueToken = list("limit=2&continue=" + continueToken)
+	if names != "cm-f" || continueToken != "" {
+		t.Fatalf("unexpected last page %s, continue %q", names, continueToken)
+	}
+}
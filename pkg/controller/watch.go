@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+const (
+	// 侦听连接的心跳间隔，避免空闲连接被代理断开
+	watchHeartbeatInterval = 30 * time.Second
+
+	contentTypeEventStream = "text/event-stream"
+	contentTypeJSONLines   = "application/x-ndjson"
+)
+
+// isWatch 判断请求是否为侦听请求
+func isWatch(ctx *gin.Context) (bool, error) {
+	watchStr := ctx.Query("watch")
+	if watchStr == "" {
+		return false, nil
+	}
+	watch, err := strconv.ParseBool(watchStr)
+	if err != nil {
+		return false, e.Errorf("invalid watch %s", watchStr)
+	}
+	return watch, nil
+}
+
+// watchRevision 获取恢复侦听的起始修订版本，优先使用revision参数，其次使用EventSource断线重连时携带的Last-Event-ID请求头。
+// 修订版本为存储的全局修订版本号，取自列举响应或变更中的Revision，而不是资源对象的Metadata.ResourceVersion
+func watchRevision(ctx *gin.Context) (int64, error) {
+	revisionStr := ctx.Query("revision")
+	if revisionStr == "" {
+		revisionStr = ctx.GetHeader("Last-Event-ID")
+	}
+	if revisionStr == "" {
+		return 0, nil
+	}
+	revision, err := strconv.ParseInt(revisionStr, 10, 64)
+	if err != nil || revision < 0 {
+		return 0, e.Errorf("invalid revision %s", revisionStr)
+	}
+	return revision, nil
+}
+
+// Watch 持续推送资源对象的变更，名称为空时推送命名空间下所有对象的变更。
+// 未指定修订版本时先推送当前的对象，否则推送该修订版本之后的变更。
+// 请求头Accept为text/event-stream时以Server-Sent Events格式推送，事件id为变更的修订版本，否则每行推送一个JSON格式的变更
+func (c *BaseController) Watch(ctx *gin.Context, namespace string, name string, filts ...ListFilter) {
+	revision, err := watchRevision(ctx)
+	if err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	labelSelector, err := core.ParseLabelSelector(ctx.Query("labelSelector"))
+	if err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	fieldSelector, err := core.ParseFieldSelector(ctx.Query("fieldSelector"))
+	if err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+
+	var actions <-chan core.ApiObjectAction
+	if revision > 0 {
+		actions, err = c.registry.WatchFrom(ctx.Request.Context(), namespace, name, revision)
+		if err != nil {
+			c.ResponseError(ctx, err)
+			return
+		}
+	} else if name != "" {
+		actions = c.registry.GetWatch(ctx.Request.Context(), namespace, name)
+		if actions == nil {
+			meta := core.Metadata{
+				Namespace: namespace,
+				Name:      name,
+			}
+			c.Response(ctx, 404, e.ERROR, fmt.Sprintf("%s not found", meta.GetKey(c.registry.GVK().Kind, c.registry.Namespaced())), nil)
+			return
+		}
+	} else {
+		actions = c.registry.ListWatch(ctx.Request.Context(), namespace)
+		if actions == nil {
+			c.ResponseError(ctx, e.InvalidNamespaceError{Namespace: namespace})
+			return
+		}
+	}
+
+	eventStream := strings.Contains(ctx.GetHeader("Accept"), contentTypeEventStream)
+	if eventStream {
+		ctx.Header("Content-Type", contentTypeEventStream)
+	} else {
+		ctx.Header("Content-Type", contentTypeJSONLines)
+	}
+	ctx.Header("Cache-Control", "no-cache")
+	// 禁用nginx等反向代理的响应缓冲
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(watchHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if eventStream {
+				fmt.Fprint(ctx.Writer, ": heartbeat\n\n")
+			} else {
+				fmt.Fprint(ctx.Writer, "\n")
+			}
+			ctx.Writer.Flush()
+		case action, ok := <-actions:
+			if !ok {
+				return
+			}
+			if action.Obj == nil || !labelSelector.MatchesLabels(action.Obj) || !fieldSelector.MatchesFields(action.Obj) {
+				continue
+			}
+			filtered := []core.ApiObject{action.Obj}
+			for _, filt := range filts {
+				filtered = filt(ctx, filtered)
+			}
+			if len(filtered) == 0 {
+				continue
+			}
+			action.Obj = filtered[0]
+
+			data, err := json.Marshal(action)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+			if eventStream {
+				fmt.Fprintf(ctx.Writer, "id: %d\ndata: %s\n\n", action.Revision, data)
+			} else {
+				fmt.Fprintf(ctx.Writer, "%s\n", data)
+			}
+			ctx.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package controller_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	clientset "github.com/wujie1993/waves/pkg/client"
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	// 注册资源对象的实例化与转换方法
+	_ "github.com/wujie1993/waves/pkg/orm"
+)
+
+func TestWatchAPI(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	configMapCtl := controller.NewController(v1.NewConfigMapRegistry())
+	router.GET("/api/v1/namespaces/:namespace/configmaps", func(ctx *gin.Context) { configMapCtl.List(ctx) })
+	router.GET("/api/v1/namespaces/:namespace/configmaps/:name", configMapCtl.Get)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	configMap := v1.NewConfigMap()
+	configMap.Metadata.Namespace = core.DefaultNamespace
+	configMap.Metadata.Name = "cm1"
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client := clientset.NewClientSet(server.URL).V1().ConfigMaps(core.DefaultNamespace)
+
+	// 未指定修订版本时先收到当前的对象，再收到后续变更
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	actions, err := client.Watch(watchCtx, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receive := func(actions <-chan core.ApiObjectAction) core.ApiObjectAction {
+		select {
+		case action, ok := <-actions:
+			if !ok {
+				t.Fatal("watch channel closed unexpectedly")
+			}
+			return action
+		case <-ctx.Done():
+			t.Fatal("watch channel has nothing received")
+		}
+		return core.ApiObjectAction{}
+	}
+	action := receive(actions)
+	if action.Type != db.KVActionTypeSet || action.Obj.(*v1.ConfigMap).Metadata.Name != "cm1" {
+		t.Fatalf("unexpected action %+v", action)
+	}
+	lastRevision := action.Revision
+
+	configMap.Metadata.Name = "cm2"
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	action = receive(actions)
+	if action.Type != db.KVActionTypeSet || action.Obj.(*v1.ConfigMap).Metadata.Name != "cm2" || action.Revision <= lastRevision {
+		t.Fatalf("unexpected action %+v", action)
+	}
+	lastRevision = action.Revision
+	watchCancel()
+
+	// 断开期间的变更在从修订版本恢复侦听后补发
+	if _, err := configMapRegistry.Delete(context.TODO(), core.DefaultNamespace, "cm1"); err != nil {
+		t.Fatal(err)
+	}
+	actions, err = client.Watch(ctx, "", lastRevision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	action = receive(actions)
+	if action.Obj.(*v1.ConfigMap).Metadata.Name != "cm1" || action.Revision <= lastRevision {
+		t.Fatalf("unexpected action %+v", action)
+	}
+
+	// 从列举返回的修订版本开始侦听，只收到列举之后的变更
+	list, listRevision, err := client.ListWithRevision(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) == 0 || listRevision < action.Revision {
+		t.Fatalf("unexpected list %+v at revision %d", list, listRevision)
+	}
+	listCtx, listCancel := context.WithCancel(ctx)
+	actions, err = client.Watch(listCtx, "", listRevision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configMap.Metadata.Name = "cm3"
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+	if action := receive(actions); action.Obj.(*v1.ConfigMap).Metadata.Name != "cm3" || action.Revision <= listRevision {
+		t.Fatalf("unexpected action %+v", action)
+	}
+	listCancel()
+
+	// 侦听单个对象
+	actions, err = client.Watch(ctx, "cm2", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if action := receive(actions); action.Obj.(*v1.ConfigMap).Metadata.Name != "cm2" {
+		t.Fatalf("unexpected action %+v", action)
+	}
+	if _, err := client.Watch(ctx, "cm4", 0); err == nil {
+		t.Fatal("expected error when watching a nonexistent object")
+	}
+}
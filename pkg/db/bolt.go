@@ -0,0 +1,618 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+var (
+	boltBucket = []byte("kv")
+	// boltRevBucket 记录每个键最后一次修改时的修订版本号
+	boltRevBucket = []byte("revs")
+	// boltMetaBucket 记录全局修订版本号等元数据
+	boltMetaBucket = []byte("meta")
+	// boltTTLBucket 记录设置了存活时间的键的过期时间
+	boltTTLBucket = []byte("ttls")
+
+	boltRevisionKey = []byte("revision")
+)
+
+const (
+	// boltSweepInterval 清理过期键的时间间隔
+	boltSweepInterval = 10 * time.Second
+
+	// boltHistorySize 内存中保留的最近变更数量，用于从指定修订版本恢复侦听
+	boltHistorySize = 1000
+)
+
+// BoltClient 基于bolt内嵌数据库实现的单机键值存储，适用于无法部署etcd集群的单机环境
+type BoltClient struct {
+	db *bolt.DB
+
+	// 写入锁，保证写入顺序与变更通知顺序一致
+	writeMutex sync.Mutex
+
+	watchers      map[*boltWatcher]struct{}
+	watchersMutex sync.RWMutex
+
+	// 最近的变更记录，由写入锁保护。historyFloor及之前的变更不再保留
+	history      []KVAction
+	historyFloor int64
+
+	locks      map[string]chan struct{}
+	locksMutex sync.Mutex
+
+	// 关闭时停止过期键清理
+	stopCh chan struct{}
+}
+
+// boltWatcher 记录单个侦听者的侦听范围与推送通道
+type boltWatcher struct {
+	ctx context.Context
+	// 停止侦听，侦听者处理过慢导致通道已满时由推送方调用
+	cancel     context.CancelFunc
+	key        string
+	withPrefix bool
+	watcher    chan KVAction
+}
+
+// match 判断键是否处于侦听范围内
+func (w boltWatcher) match(key string) bool {
+	if w.withPrefix {
+		return strings.HasPrefix(key, w.key)
+	}
+	return key == w.key
+}
+
+// NewBoltClient 打开或创建本地bolt数据库文件
+func NewBoltClient(path string) (*BoltClient, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{boltBucket, boltRevBucket, boltMetaBucket, boltTTLBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// 启动前的变更均未保留在内存中
+	var historyFloor int64
+	if err := db.View(func(tx *bolt.Tx) error {
+		if revBytes := tx.Bucket(boltMetaBucket).Get(boltRevisionKey); len(revBytes) == 8 {
+			historyFloor = int64(binary.BigEndian.Uint64(revBytes))
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	boltClient := &BoltClient{
+		db:           db,
+		watchers:     make(map[*boltWatcher]struct{}),
+		historyFloor: historyFloor,
+		locks:        make(map[string]chan struct{}),
+		stopCh:       make(chan struct{}),
+	}
+	go boltClient.runSweeper()
+	return boltClient, nil
+}
+
+// Close 关闭数据库文件
+func (c *BoltClient) Close() error {
+	close(c.stopCh)
+	return c.db.Close()
+}
+
+// runSweeper 定期清理过期的键，模拟etcd租约到期后的自动删除
+func (c *BoltClient) runSweeper() {
+	ticker := time.NewTicker(boltSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			if err := c.sweep(time.Now()); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
+
+// sweep 删除所有在指定时间之前过期的键
+func (c *BoltClient) sweep(now time.Time) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	actions := []KVAction{}
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		expiredKeys := []string{}
+		cursor := tx.Bucket(boltTTLBucket).Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			if len(v) == 8 && int64(binary.BigEndian.Uint64(v)) <= now.UnixNano() {
+				expiredKeys = append(expiredKeys, string(k))
+			}
+		}
+		for _, key := range expiredKeys {
+			action, err := deleteBolt(tx, key)
+			if err != nil {
+				return err
+			}
+			if action != nil {
+				actions = append(actions, *action)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, action := range actions {
+		log.Tracef("expired %s", action.Key)
+		c.notify(action)
+	}
+	return nil
+}
+
+func (c *BoltClient) Get(key string) (string, error) {
+	var value string
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		value = string(tx.Bucket(boltBucket).Get([]byte(key)))
+		return nil
+	}); err != nil {
+		log.Error(err)
+		return "", err
+	}
+	return value, nil
+}
+
+func (c *BoltClient) GetWithRevision(key string) (string, int64, error) {
+	var value string
+	var revision int64
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		value = string(tx.Bucket(boltBucket).Get([]byte(key)))
+		revision = getBoltRevision(tx, key)
+		return nil
+	}); err != nil {
+		log.Error(err)
+		return "", 0, err
+	}
+	return value, revision, nil
+}
+
+func (c *BoltClient) Range(begin string, end string) (map[string]string, error) {
+	result := make(map[string]string)
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(boltBucket).Cursor()
+		for k, v := cursor.Seek([]byte(begin)); k != nil && bytes.Compare(k, []byte(end)) < 0; k, v = cursor.Next() {
+			result[string(k)] = string(v)
+		}
+		return nil
+	}); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *BoltClient) ListPage(prefix string, startKey string, limit int64) ([]KVPair, bool, int64, error) {
+	if startKey < prefix {
+		startKey = prefix
+	}
+	result := []KVPair{}
+	more := false
+	var revision int64
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		revision = currentBoltRevision(tx)
+		cursor := tx.Bucket(boltBucket).Cursor()
+		for k, v := cursor.Seek([]byte(startKey)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, v = cursor.Next() {
+			if limit > 0 && int64(len(result)) >= limit {
+				more = true
+				break
+			}
+			result = append(result, KVPair{Key: string(k), Value: string(v)})
+		}
+		return nil
+	}); err != nil {
+		log.Error(err)
+		return nil, false, 0, err
+	}
+	return result, more, revision, nil
+}
+
+func (c *BoltClient) Set(key string, value string) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	var revision int64
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		revision, err = putBolt(tx, key, value, time.Time{})
+		return err
+	}); err != nil {
+		log.Error(err)
+		return err
+	}
+
+	c.notify(KVAction{
+		Key:        key,
+		Value:      value,
+		ActionType: KVActionTypeSet,
+		Revision:   revision,
+	})
+	return nil
+}
+
+func (c *BoltClient) CompareAndSet(key string, value string, revision int64) (bool, error) {
+	return c.Txn(OpCompareAndSet(key, value, revision))
+}
+
+func (c *BoltClient) Txn(ops ...KVOp) (bool, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	var succeeded bool
+	actions := []KVAction{}
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		// 所有比较条件成立后才执行写入
+		for _, op := range ops {
+			if op.Compare && getBoltRevision(tx, op.Key) != op.Revision {
+				return nil
+			}
+		}
+		succeeded = true
+
+		// 同一事务中存活时间相同的键值使用相同的过期时间
+		now := time.Now()
+		for _, op := range ops {
+			switch op.ActionType {
+			case KVActionTypeSet:
+				var expireAt time.Time
+				if op.TTL > 0 {
+					expireAt = now.Add(op.TTL)
+				}
+				revision, err := putBolt(tx, op.Key, op.Value, expireAt)
+				if err != nil {
+					return err
+				}
+				actions = append(actions, KVAction{
+					Key:        op.Key,
+					Value:      op.Value,
+					ActionType: KVActionTypeSet,
+					Revision:   revision,
+				})
+			case KVActionTypeDelete:
+				action, err := deleteBolt(tx, op.Key)
+				if err != nil {
+					return err
+				}
+				if action != nil {
+					actions = append(actions, *action)
+				}
+			default:
+				return e.Errorf("unknown kv action type %s", op.ActionType)
+			}
+		}
+		return nil
+	}); err != nil {
+		log.Error(err)
+		return false, err
+	}
+
+	for _, action := range actions {
+		c.notify(action)
+	}
+	return succeeded, nil
+}
+
+func (c *BoltClient) Delete(key string) (string, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	var action *KVAction
+	if err := c.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		action, err = deleteBolt(tx, key)
+		return err
+	}); err != nil {
+		log.Error(err)
+		return "", err
+	}
+
+	if action == nil {
+		return "", nil
+	}
+
+	c.notify(*action)
+	return action.Value, nil
+}
+
+func (c *BoltClient) List(key string, withPrefix bool) (map[string]string, error) {
+	result, _, err := c.ListWithRevision(key, withPrefix)
+	return result, err
+}
+
+func (c *BoltClient) ListWithRevision(key string, withPrefix bool) (map[string]string, int64, error) {
+	result := make(map[string]string)
+	var revision int64
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		revision = currentBoltRevision(tx)
+		bucket := tx.Bucket(boltBucket)
+		if !withPrefix {
+			if value := bucket.Get([]byte(key)); value != nil {
+				result[key] = string(value)
+			}
+			return nil
+		}
+		cursor := bucket.Cursor()
+		prefix := []byte(key)
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			result[string(k)] = string(v)
+		}
+		return nil
+	}); err != nil {
+		log.Error(err)
+		return nil, 0, err
+	}
+	return result, revision, nil
+}
+
+func (c *BoltClient) Watch(ctx context.Context, key string, withPrefix bool) <-chan KVAction {
+	w := c.addWatcher(ctx, key, withPrefix, nil)
+	return w.watcher
+}
+
+func (c *BoltClient) ListWatch(ctx context.Context, key string, withPrefix bool) <-chan KVAction {
+	// 持有写入锁，保证快照与后续变更之间不会遗漏或重复
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	snapshot := []KVAction{}
+	if err := c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		if !withPrefix {
+			if value := bucket.Get([]byte(key)); value != nil {
+				snapshot = append(snapshot, KVAction{
+					Key:        key,
+					Value:      string(value),
+					ActionType: KVActionTypeSet,
+					Revision:   getBoltRevision(tx, key),
+				})
+			}
+			return nil
+		}
+		cursor := bucket.Cursor()
+		prefix := []byte(key)
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			snapshot = append(snapshot, KVAction{
+				Key:        string(k),
+				Value:      string(v),
+				ActionType: KVActionTypeSet,
+				Revision:   getBoltRevision(tx, string(k)),
+			})
+		}
+		return nil
+	}); err != nil {
+		log.Error(err)
+	}
+
+	w := c.addWatcher(ctx, key, withPrefix, snapshot)
+	return w.watcher
+}
+
+func (c *BoltClient) WatchFrom(ctx context.Context, key string, withPrefix bool, revision int64) (<-chan KVAction, error) {
+	// 持有写入锁，保证补发的历史变更与后续变更之间不会遗漏或重复
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	if revision < c.historyFloor {
+		return nil, e.ExpiredRevisionError{Revision: revision}
+	}
+
+	w := boltWatcher{key: key, withPrefix: withPrefix}
+	replay := []KVAction{}
+	for _, action := range c.history {
+		if action.Revision > revision && w.match(action.Key) {
+			replay = append(replay, action)
+		}
+	}
+	return c.addWatcher(ctx, key, withPrefix, replay).watcher, nil
+}
+
+// addWatcher 注册侦听者，并预先推送快照中的变更
+func (c *BoltClient) addWatcher(ctx context.Context, key string, withPrefix bool, snapshot []KVAction) *boltWatcher {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &boltWatcher{
+		ctx:        ctx,
+		cancel:     cancel,
+		key:        key,
+		withPrefix: withPrefix,
+		watcher:    make(chan KVAction, 1000+len(snapshot)),
+	}
+	for _, action := range snapshot {
+		w.watcher <- action
+	}
+
+	c.watchersMutex.Lock()
+	c.watchers[w] = struct{}{}
+	c.watchersMutex.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		// 在移除侦听者后才关闭通道，避免向已关闭的通道推送变更
+		c.watchersMutex.Lock()
+		delete(c.watchers, w)
+		c.watchersMutex.Unlock()
+		close(w.watcher)
+	}()
+	return w
+}
+
+// notify 记录变更并将其推送给所有匹配的侦听者，调用方需持有写入锁。
+// 推送不会阻塞写入，通道已满的侦听者被停止并关闭通道，需要重新列举或从最后收到的修订版本恢复侦听
+func (c *BoltClient) notify(action KVAction) {
+	c.history = append(c.history, action)
+	if len(c.history) > boltHistorySize {
+		c.historyFloor = c.history[0].Revision
+		c.history = c.history[1:]
+	}
+
+	c.watchersMutex.RLock()
+	defer c.watchersMutex.RUnlock()
+
+	for w := range c.watchers {
+		// 已停止的侦听者不再推送，避免跳过部分变更后继续推送
+		if w.ctx.Err() != nil || !w.match(action.Key) {
+			continue
+		}
+		select {
+		case w.watcher <- action:
+		default:
+			log.Warnf("watcher of %s falls behind, stop watching", w.key)
+			w.cancel()
+		}
+	}
+}
+
+func (c *BoltClient) Lock(ctx context.Context, key string) error {
+	c.locksMutex.Lock()
+	mutex, ok := c.locks[key]
+	if !ok {
+		mutex = make(chan struct{}, 1)
+		c.locks[key] = mutex
+	}
+	c.locksMutex.Unlock()
+
+	select {
+	case mutex <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		log.Error(ctx.Err())
+		return ctx.Err()
+	}
+}
+
+func (c *BoltClient) Unlock(ctx context.Context, key string) error {
+	c.locksMutex.Lock()
+	mutex, ok := c.locks[key]
+	c.locksMutex.Unlock()
+	if !ok {
+		err := e.Errorf("lock key %s not found", key)
+		log.Error(err)
+		return err
+	}
+
+	select {
+	case <-mutex:
+		return nil
+	default:
+		err := e.Errorf("lock key %s not locked", key)
+		log.Error(err)
+		return err
+	}
+}
+
+// putBolt 在事务内写入键值，并将键的修订版本号更新为新的全局修订版本号。过期时间不为零值时重新设置键的过期时间
+func putBolt(tx *bolt.Tx, key string, value string, expireAt time.Time) (int64, error) {
+	revision, err := nextBoltRevision(tx)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Bucket(boltBucket).Put([]byte(key), []byte(value)); err != nil {
+		return 0, err
+	}
+	revBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(revBytes, uint64(revision))
+	if err := tx.Bucket(boltRevBucket).Put([]byte(key), revBytes); err != nil {
+		return 0, err
+	}
+	if !expireAt.IsZero() {
+		expireBytes := make([]byte, 8)
+		binary.BigEndian.PutUint64(expireBytes, uint64(expireAt.UnixNano()))
+		if err := tx.Bucket(boltTTLBucket).Put([]byte(key), expireBytes); err != nil {
+			return 0, err
+		}
+	}
+	return revision, nil
+}
+
+// deleteBolt 在事务内删除键及其修订版本号与过期时间，返回对应的删除事件，键不存在时返回空
+func deleteBolt(tx *bolt.Tx, key string) (*KVAction, error) {
+	bucket := tx.Bucket(boltBucket)
+	value := bucket.Get([]byte(key))
+	if value == nil {
+		return nil, nil
+	}
+	// bolt返回的值仅在事务内有效，需要复制后使用
+	prevValue := string(append([]byte{}, value...))
+
+	revision, err := nextBoltRevision(tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range [][]byte{boltRevBucket, boltTTLBucket, boltBucket} {
+		if err := tx.Bucket(b).Delete([]byte(key)); err != nil {
+			return nil, err
+		}
+	}
+	return &KVAction{
+		Key:        key,
+		Value:      prevValue,
+		ActionType: KVActionTypeDelete,
+		Revision:   revision,
+	}, nil
+}
+
+// getBoltRevision 获取键的修订版本号，键不存在时返回0
+func getBoltRevision(tx *bolt.Tx, key string) int64 {
+	revBytes := tx.Bucket(boltRevBucket).Get([]byte(key))
+	if len(revBytes) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(revBytes))
+}
+
+// currentBoltRevision 获取当前的全局修订版本号
+func currentBoltRevision(tx *bolt.Tx) int64 {
+	revBytes := tx.Bucket(boltMetaBucket).Get(boltRevisionKey)
+	if len(revBytes) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(revBytes))
+}
+
+// nextBoltRevision 递增并返回全局修订版本号
+func nextBoltRevision(tx *bolt.Tx) (int64, error) {
+	revision := currentBoltRevision(tx) + 1
+	revBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(revBytes, uint64(revision))
+	if err := tx.Bucket(boltMetaBucket).Put(boltRevisionKey, revBytes); err != nil {
+		return 0, err
+	}
+	return revision, nil
+}
@@ -0,0 +1,427 @@
+package db_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+func newBoltClient(t *testing.T) (*db.BoltClient, func()) {
+	dir, err := ioutil.TempDir("", "waves-bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := db.NewBoltClient(filepath.Join(dir, "waves.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return cli, func() {
+		cli.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestBoltCRUD(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	key := RegistryPrefix + "/hosts/host1"
+	value := "{\"name\":\"host1\",\"address\":\"192.168.1.1\"}"
+
+	// 写入
+	if err := cli.Set(key, value); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Set(RegistryPrefix+"/hostsx/host2", value); err != nil {
+		t.Fatal(err)
+	}
+
+	// 读取
+	if respValue, err := cli.Get(key); err != nil {
+		t.Fatal(err)
+	} else if respValue != value {
+		t.Fatal("get result incorrect")
+	}
+
+	// 列举
+	if result, err := cli.List(RegistryPrefix+"/hosts/", true); err != nil {
+		t.Fatal(err)
+	} else if len(result) != 1 || result[key] != value {
+		t.Fatalf("list result incorrect: %+v", result)
+	}
+
+	// 删除
+	if result, err := cli.Delete(key); err != nil {
+		t.Fatal(err)
+	} else if result != value {
+		t.Fatal("delete result incorrect")
+	}
+
+	if respValue, err := cli.Get(key); err != nil {
+		t.Fatal(err)
+	} else if respValue != "" {
+		t.Fatal("delete unsuccessful")
+	}
+}
+
+func TestBoltRange(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	dataset := make(map[string]string)
+	dataset[RegistryPrefix+"/audits/1587092947"] = "1"
+	dataset[RegistryPrefix+"/audits/1587092952"] = "2"
+	dataset[RegistryPrefix+"/audits/1587092960"] = "3"
+
+	for k, v := range dataset {
+		if err := cli.Set(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := cli.Range(RegistryPrefix+"/audits/1587092947", RegistryPrefix+"/audits/1587092960")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("range result incorrect: %+v", result)
+	}
+}
+
+func TestBoltWatch(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	watcher := cli.Watch(ctx, RegistryPrefix+"/hosts/", true)
+
+	key := RegistryPrefix + "/hosts/host1"
+	if err := cli.Set(key, "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, actionType := range []string{db.KVActionTypeSet, db.KVActionTypeDelete} {
+		select {
+		case action := <-watcher:
+			if action.Key != key || action.ActionType != actionType || action.Value != "1" {
+				t.Fatalf("unexpected action: %+v", action)
+			}
+		case <-ctx.Done():
+			t.Fatal("watch channel has nothing received")
+		}
+	}
+}
+
+func TestBoltMutex(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	key := RegistryPrefix + "/locks/lock1"
+	if err := cli.Lock(context.TODO(), key); err != nil {
+		t.Fatal(err)
+	}
+
+	// 锁已被占用时，再次加锁会阻塞直至超时
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := cli.Lock(ctx, key); err == nil {
+		t.Fatal("lock should be held")
+	}
+
+	if err := cli.Unlock(context.TODO(), key); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Lock(context.TODO(), key); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBoltCompareAndSet(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	key := RegistryPrefix + "/hosts/host1"
+
+	// 修订版本号为0时要求键不存在
+	if ok, err := cli.CompareAndSet(key, "1", 0); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("create with revision 0 should succeed")
+	}
+	if ok, err := cli.CompareAndSet(key, "2", 0); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("create existing key should fail")
+	}
+
+	value, revision, err := cli.GetWithRevision(key)
+	if err != nil {
+		t.Fatal(err)
+	} else if value != "1" || revision == 0 {
+		t.Fatalf("unexpected value %s with revision %d", value, revision)
+	}
+
+	// 其他写入使修订版本号发生变化后，基于旧修订版本号的写入应当失败
+	if err := cli.Set(key, "3"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := cli.CompareAndSet(key, "4", revision); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("stale compare and set should fail")
+	}
+
+	_, revision, err = cli.GetWithRevision(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := cli.CompareAndSet(key, "4", revision); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("compare and set with latest revision should succeed")
+	}
+	if value, err := cli.Get(key); err != nil {
+		t.Fatal(err)
+	} else if value != "4" {
+		t.Fatalf("unexpected value %s", value)
+	}
+}
+
+func TestBoltListWatch(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	key := RegistryPrefix + "/hosts/host1"
+	if err := cli.Set(key, "1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 先收到当前已存在对象的快照，再收到后续变更
+	watcher := cli.ListWatch(ctx, RegistryPrefix+"/hosts/", true)
+	if err := cli.Set(key, "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	var lastRevision int64
+	for _, value := range []string{"1", "2"} {
+		select {
+		case action := <-watcher:
+			if action.Key != key || action.ActionType != db.KVActionTypeSet || action.Value != value {
+				t.Fatalf("unexpected action: %+v", action)
+			}
+			if action.Revision <= lastRevision {
+				t.Fatalf("revision should increase: %+v", action)
+			}
+			lastRevision = action.Revision
+		case <-ctx.Done():
+			t.Fatal("watch channel has nothing received")
+		}
+	}
+}
+
+func TestBoltWatchFrom(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	key := RegistryPrefix + "/hosts/host1"
+	if err := cli.Set(key, "1"); err != nil {
+		t.Fatal(err)
+	}
+	_, revision, err := cli.GetWithRevision(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Set(key, "2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 先补发修订版本之后的变更，再推送新的变更
+	watchCtx, watchCancel := context.WithCancel(ctx)
+	watcher, err := cli.WatchFrom(watchCtx, RegistryPrefix+"/hosts/", true, revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Set(key, "3"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []struct {
+		actionType string
+		value      string
+	}{
+		{db.KVActionTypeSet, "2"},
+		{db.KVActionTypeDelete, "2"},
+		{db.KVActionTypeSet, "3"},
+	}
+	for _, exp := range expected {
+		select {
+		case action := <-watcher:
+			if action.Key != key || action.ActionType != exp.actionType || action.Value != exp.value || action.Revision <= revision {
+				t.Fatalf("unexpected action: %+v", action)
+			}
+			revision = action.Revision
+		case <-ctx.Done():
+			t.Fatal("watch channel has nothing received")
+		}
+	}
+
+	watchCancel()
+
+	// 超出内存中保留的变更数量后，较早的修订版本无法恢复
+	for i := 0; i <= 1000; i++ {
+		if err := cli.Set(key, "4"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cli.WatchFrom(ctx, RegistryPrefix+"/hosts/", true, revision); err == nil {
+		t.Fatal("expected error when watching from an expired revision")
+	} else if _, ok := err.(e.ExpiredRevisionError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestBoltTxn(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	jobKey := RegistryPrefix + "/jobs/job1"
+	configMapKey := RegistryPrefix + "/configmaps/default/job1"
+
+	// 多个写入操作在同一事务中提交
+	if ok, err := cli.Txn(db.OpCompareAndSet(jobKey, "1", 0), db.OpCompareAndSet(configMapKey, "1", 0)); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("txn should succeed")
+	}
+
+	// 任一比较条件不成立时，所有操作均不生效
+	_, revision, err := cli.GetWithRevision(jobKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := cli.Txn(db.OpCompareAndDelete(jobKey, revision), db.OpCompareAndSet(configMapKey, "2", 0)); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("txn should fail")
+	}
+	if value, err := cli.Get(jobKey); err != nil {
+		t.Fatal(err)
+	} else if value != "1" {
+		t.Fatal("job should not be deleted")
+	}
+
+	if ok, err := cli.Txn(db.OpCompareAndDelete(jobKey, revision), db.OpDelete(configMapKey)); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("txn should succeed")
+	}
+	if result, err := cli.List(RegistryPrefix, true); err != nil {
+		t.Fatal(err)
+	} else if len(result) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestBoltTTL(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	eventKey := RegistryPrefix + "/events/default/event1"
+	indexKey := RegistryPrefix + "/indexes/events/type/Normal/default%2Fevent1"
+	jobKey := RegistryPrefix + "/jobs/job1"
+
+	if ok, err := cli.Txn(db.OpCompareAndSet(eventKey, "1", 0).WithTTL(time.Hour), db.OpSet(indexKey, eventKey).WithTTL(time.Hour), db.OpSet(jobKey, "1")); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("txn should succeed")
+	}
+	// 未指定存活时间的写入保留键原有的存活时间
+	if err := cli.Set(eventKey, "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cli.Sweep(time.Now().Add(30 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := cli.List(RegistryPrefix, true); err != nil {
+		t.Fatal(err)
+	} else if len(result) != 3 {
+		t.Fatalf("keys should not expire yet: %+v", result)
+	}
+
+	// 到期后同一事务中写入的键值同时删除
+	if err := cli.Sweep(time.Now().Add(2 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := cli.List(RegistryPrefix, true); err != nil {
+		t.Fatal(err)
+	} else if len(result) != 1 || result[jobKey] != "1" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestBoltSlowWatcher(t *testing.T) {
+	cli, cleanup := newBoltClient(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 不读取变更的侦听者不会阻塞写入，通道写满后被关闭
+	key := RegistryPrefix + "/hosts/host1"
+	watcher := cli.Watch(ctx, key, false)
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; i <= 1000; i++ {
+			if err := cli.Set(key, "1"); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-ctx.Done():
+		t.Fatal("writes blocked by slow watcher")
+	}
+
+	received := 0
+	for {
+		select {
+		case _, ok := <-watcher:
+			if !ok {
+				if received != 1000 {
+					t.Fatalf("unexpected %d actions received before closed", received)
+				}
+				return
+			}
+			received++
+		case <-ctx.Done():
+			t.Fatal("watch channel of slow watcher is not closed")
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Package dbtest 提供测试中使用的存储后端
+package dbtest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db"
+)
+
+// NewBoltKV 使用临时的bolt数据库作为存储后端，返回用于关闭数据库并清理临时目录的函数
+func NewBoltKV(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "waves-orm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli, err := db.NewBoltClient(filepath.Join(dir, "waves.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	db.KV = cli
+	return func() {
+		cli.Close()
+		os.RemoveAll(dir)
+	}
+}
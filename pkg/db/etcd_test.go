@@ -0,0 +1,100 @@
+package db_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/setting"
+)
+
+// writeCert 生成自签名证书与私钥并写入指定目录，返回证书与私钥的路径
+func writeCert(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "waves"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "client.crt")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "client.key")
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestEtcdConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "waves-etcd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeCert(t, dir)
+
+	// 未配置证书与用户名时不启用TLS与认证，超时时长使用默认值
+	config, err := db.EtcdConfig(setting.Etcd{Endpoints: []string{"127.0.0.1:2379"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.TLS != nil || config.Username != "" || config.DialTimeout <= 0 {
+		t.Fatalf("unexpected config %+v", config)
+	}
+
+	// 配置证书时启用TLS，使用CA证书校验服务端并携带客户端证书
+	config, err = db.EtcdConfig(setting.Etcd{
+		Endpoints: []string{"127.0.0.1:2379"},
+		CAFile:    certFile,
+		CertFile:  certFile,
+		KeyFile:   keyFile,
+		Username:  "root",
+		Password:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.TLS == nil || config.TLS.RootCAs == nil {
+		t.Fatal("tls should be enabled with trusted ca")
+	}
+	if config.TLS.GetClientCertificate == nil && len(config.TLS.Certificates) == 0 {
+		t.Fatal("client certificate should be configured")
+	}
+	if config.Username != "root" || config.Password != "secret" {
+		t.Fatalf("unexpected auth %s:%s", config.Username, config.Password)
+	}
+
+	// 证书不存在时返回错误
+	if _, err := db.EtcdConfig(setting.Etcd{CAFile: filepath.Join(dir, "missing.crt")}); err == nil {
+		t.Fatal("expected error of missing ca file")
+	}
+}
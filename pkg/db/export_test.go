@@ -0,0 +1,31 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+
+	"github.com/wujie1993/waves/pkg/setting"
+)
+
+// Sweep 立即清理在指定时间之前过期的键，仅用于测试
+func (c *BoltClient) Sweep(now time.Time) error {
+	return c.sweep(now)
+}
+
+// EtcdConfig 根据etcd连接配置生成客户端配置，仅用于测试
+func EtcdConfig(etcdSetting setting.Etcd) (clientv3.Config, error) {
+	return etcdConfig(etcdSetting)
+}
+
+// LeaseCount 获取etcd中存活的租约数量，仅用于测试
+func (c *EtcdClient) LeaseCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	resp, err := c.client.Leases(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(resp.Leases), nil
+}
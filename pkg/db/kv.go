@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/etcdserver/api/v3rpc/rpctypes"
 	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/coreos/etcd/pkg/transport"
 	log "github.com/sirupsen/logrus"
 	"go.etcd.io/etcd/clientv3/concurrency"
 
@@ -19,6 +21,13 @@ import (
 const (
 	KVActionTypeSet    = "set"
 	KVActionTypeDelete = "delete"
+
+	DriverEtcd = "etcd"
+	DriverBolt = "bolt"
+
+	defaultEtcdDialTimeout    = 5 * time.Second
+	defaultEtcdRequestTimeout = 5 * time.Second
+	defaultEtcdRetryTimes     = 3
 )
 
 var KV KVStorage
@@ -26,19 +35,43 @@ var ClientV3 *clientv3.Client
 
 type KVStorage interface {
 	Get(string) (string, error)
+	// GetWithRevision 获取键值及其修订版本号，键不存在时修订版本号为0
+	GetWithRevision(string) (string, int64, error)
+	// Set 写入键值，键已存在时保留原有的存活时间
 	Set(string, string) error
+	// CompareAndSet 仅当键的当前修订版本号与指定值一致时写入，修订版本号为0表示键必须不存在
+	CompareAndSet(string, string, int64) (bool, error)
+	// Txn 在同一事务中提交多个写入操作，任一比较条件不成立时所有操作均不执行并返回false
+	Txn(...KVOp) (bool, error)
 	List(string, bool) (map[string]string, error)
+	// ListWithRevision 获取键值及读取时存储的全局修订版本号，可从该修订版本开始侦听后续的变更
+	ListWithRevision(string, bool) (map[string]string, int64, error)
 	Delete(string) (string, error)
 	Range(string, string) (map[string]string, error)
+	// ListPage 按键的顺序获取前缀下从起始键(包含)开始的至多limit个键值，并返回前缀下是否还有剩余的键值与读取时存储的全局修订版本号
+	ListPage(prefix string, startKey string, limit int64) ([]KVPair, bool, int64, error)
+	// Watch 侦听变更，连接中断后会从最后收到的修订版本自动恢复
 	Watch(context.Context, string, bool) <-chan KVAction
+	// ListWatch 先将当前所有键值作为set事件推送，再从快照的修订版本开始侦听变更
+	ListWatch(context.Context, string, bool) <-chan KVAction
+	// WatchFrom 推送指定修订版本之后的所有变更并继续侦听，修订版本之后的变更已无法找回时返回e.ExpiredRevisionError
+	WatchFrom(ctx context.Context, key string, withPrefix bool, revision int64) (<-chan KVAction, error)
 	Lock(context.Context, string) error
 	Unlock(context.Context, string) error
 }
 
+// KVPair 有序获取时返回的键值对
+type KVPair struct {
+	Key   string
+	Value string
+}
+
 type KVAction struct {
 	Key        string
 	Value      string
 	ActionType string
+	// 变更对应的修订版本号
+	Revision int64
 }
 
 type EtcdClient struct {
@@ -56,34 +89,82 @@ type KVMutex struct {
 	mutex   *concurrency.Mutex
 }
 
+// InitKV 根据存储驱动配置初始化键值存储
 func InitKV() error {
-	etcdCli, err := NewEtcdClient(setting.EtcdSetting.Endpoints)
-	if err != nil {
-		return err
+	switch setting.StorageSetting.Driver {
+	case DriverBolt:
+		boltCli, err := NewBoltClient(setting.StorageSetting.Path)
+		if err != nil {
+			return err
+		}
+		KV = boltCli
+	case DriverEtcd, "":
+		etcdCli, err := NewEtcdClient(*setting.EtcdSetting)
+		if err != nil {
+			return err
+		}
+		KV = etcdCli
+		ClientV3 = etcdCli.client
+	default:
+		return e.Errorf("unknown storage driver %s", setting.StorageSetting.Driver)
 	}
-	KV = etcdCli
-	ClientV3 = etcdCli.client
 	return nil
 }
 
-func NewEtcdClient(endpoints []string) (*EtcdClient, error) {
-	cli, err := clientv3.New(clientv3.Config{
-		Endpoints:   endpoints,
-		DialTimeout: 5 * time.Second,
-	})
+// NewEtcdClient 根据etcd连接配置创建客户端，未配置的超时时长与重试次数使用默认值
+func NewEtcdClient(etcdSetting setting.Etcd) (*EtcdClient, error) {
+	config, err := etcdConfig(etcdSetting)
+	if err != nil {
+		return nil, err
+	}
+
+	cli, err := clientv3.New(config)
 	if err != nil {
 		return nil, err
 	}
 
 	etcdClient := &EtcdClient{
 		client:     cli,
-		timeout:    time.Duration(5 * time.Second),
-		retryTimes: 3,
+		timeout:    etcdSetting.RequestTimeout,
+		retryTimes: etcdSetting.RetryTimes,
 		kvMutexMap: make(map[string]KVMutex),
 	}
+	if etcdClient.timeout <= 0 {
+		etcdClient.timeout = defaultEtcdRequestTimeout
+	}
+	if etcdClient.retryTimes <= 0 {
+		etcdClient.retryTimes = defaultEtcdRetryTimes
+	}
 	return etcdClient, nil
 }
 
+// etcdConfig 根据etcd连接配置生成客户端配置，配置了证书时启用TLS，配置了用户名时启用认证
+func etcdConfig(etcdSetting setting.Etcd) (clientv3.Config, error) {
+	config := clientv3.Config{
+		Endpoints:   etcdSetting.Endpoints,
+		DialTimeout: etcdSetting.DialTimeout,
+		Username:    etcdSetting.Username,
+		Password:    etcdSetting.Password,
+	}
+	if config.DialTimeout <= 0 {
+		config.DialTimeout = defaultEtcdDialTimeout
+	}
+	if etcdSetting.CAFile != "" || etcdSetting.CertFile != "" {
+		tlsInfo := transport.TLSInfo{
+			TrustedCAFile: etcdSetting.CAFile,
+			CertFile:      etcdSetting.CertFile,
+			KeyFile:       etcdSetting.KeyFile,
+		}
+		tlsConfig, err := tlsInfo.ClientConfig()
+		if err != nil {
+			log.Error(err)
+			return clientv3.Config{}, err
+		}
+		config.TLS = tlsConfig
+	}
+	return config, nil
+}
+
 func (c *EtcdClient) Get(key string) (string, error) {
 	for retry := 0; retry < c.retryTimes; retry++ {
 		ctx, _ := context.WithTimeout(context.Background(), c.timeout)
@@ -111,6 +192,34 @@ func (c *EtcdClient) Get(key string) (string, error) {
 	return "", errors.New("failed to get " + key)
 }
 
+func (c *EtcdClient) GetWithRevision(key string) (string, int64, error) {
+	for retry := 0; retry < c.retryTimes; retry++ {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		resp, err := c.client.Get(ctx, key)
+		cancel()
+		if err != nil {
+			switch err {
+			case context.DeadlineExceeded:
+				log.Warn(err)
+				// 出现超时错误时进行重试
+				continue
+			default:
+				log.Error(err)
+				return "", 0, err
+			}
+		}
+		if resp.Count < 1 {
+			return "", 0, nil
+		}
+		valueBytes, err := base64.RawStdEncoding.DecodeString(string(resp.Kvs[0].Value))
+		if err != nil {
+			return "", 0, err
+		}
+		return string(valueBytes), resp.Kvs[0].ModRevision, nil
+	}
+	return "", 0, errors.New("failed to get " + key)
+}
+
 func (c *EtcdClient) Range(begin string, end string) (map[string]string, error) {
 	for retry := 0; retry < c.retryTimes; retry++ {
 		ctx, _ := context.WithTimeout(context.Background(), c.timeout)
@@ -139,11 +248,112 @@ func (c *EtcdClient) Range(begin string, end string) (map[string]string, error)
 	return nil, errors.New("failed to range " + begin + " to " + end)
 }
 
+func (c *EtcdClient) ListPage(prefix string, startKey string, limit int64) ([]KVPair, bool, int64, error) {
+	if startKey < prefix {
+		startKey = prefix
+	}
+	for retry := 0; retry < c.retryTimes; retry++ {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		resp, err := c.client.Get(ctx, startKey,
+			clientv3.WithRange(clientv3.GetPrefixRangeEnd(prefix)),
+			clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend),
+			clientv3.WithLimit(limit),
+		)
+		cancel()
+		if err != nil {
+			switch err {
+			case context.DeadlineExceeded:
+				log.Warn(err)
+				// 出现超时错误时进行重试
+				continue
+			default:
+				log.Error(err)
+				return nil, false, 0, err
+			}
+		}
+		result := []KVPair{}
+		for _, kvs := range resp.Kvs {
+			valueBytes, err := base64.RawStdEncoding.DecodeString(string(kvs.Value))
+			if err != nil {
+				return nil, false, 0, err
+			}
+			result = append(result, KVPair{Key: string(kvs.Key), Value: string(valueBytes)})
+		}
+		return result, resp.More, resp.Header.Revision, nil
+	}
+	return nil, false, 0, errors.New("failed to list page of " + prefix)
+}
+
 func (c *EtcdClient) Set(key string, value string) error {
-	base64Value := base64.RawStdEncoding.EncodeToString([]byte(value))
+	if _, err := c.Txn(OpSet(key, value)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *EtcdClient) CompareAndSet(key string, value string, revision int64) (bool, error) {
+	return c.Txn(OpCompareAndSet(key, value, revision))
+}
+
+func (c *EtcdClient) Txn(ops ...KVOp) (bool, error) {
+	cmps := []clientv3.Cmp{}
+	thenOps := []clientv3.Op{}
+	// 同一事务中相同存活时间的键值共用一个租约，保证资源对象与其索引键同时到期
+	leases := make(map[time.Duration]clientv3.LeaseID)
+	// 事务未成功提交时撤销已申请的租约，避免租约泄漏
+	succeeded := false
+	defer func() {
+		if !succeeded {
+			c.revoke(leases)
+		}
+	}()
+	for _, op := range ops {
+		if op.Compare {
+			cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(op.Key), "=", op.Revision))
+		}
+		switch op.ActionType {
+		case KVActionTypeSet:
+			value := base64.RawStdEncoding.EncodeToString([]byte(op.Value))
+			switch {
+			case op.TTL > 0:
+				// 为键值绑定新的租约，租约到期后由etcd自动删除
+				leaseID, ok := leases[op.TTL]
+				if !ok {
+					var err error
+					leaseID, err = c.grant(op.TTL)
+					if err != nil {
+						return false, err
+					}
+					leases[op.TTL] = leaseID
+				}
+				thenOps = append(thenOps, clientv3.OpPut(op.Key, value, clientv3.WithLease(leaseID)))
+			case op.Compare && op.Revision > 0:
+				// 更新已存在的键时保留原有的租约
+				thenOps = append(thenOps, clientv3.OpPut(op.Key, value, clientv3.WithIgnoreLease()))
+			case op.Compare:
+				thenOps = append(thenOps, clientv3.OpPut(op.Key, value))
+			default:
+				// 无法确定键是否存在时，键已存在则保留原有的租约
+				thenOps = append(thenOps, clientv3.OpTxn(
+					[]clientv3.Cmp{clientv3.Compare(clientv3.CreateRevision(op.Key), ">", 0)},
+					[]clientv3.Op{clientv3.OpPut(op.Key, value, clientv3.WithIgnoreLease())},
+					[]clientv3.Op{clientv3.OpPut(op.Key, value)},
+				))
+			}
+		case KVActionTypeDelete:
+			thenOps = append(thenOps, clientv3.OpDelete(op.Key))
+		default:
+			err := e.Errorf("unknown kv action type %s", op.ActionType)
+			log.Error(err)
+			return false, err
+		}
+	}
+
 	for retry := 0; retry < c.retryTimes; retry++ {
-		ctx, _ := context.WithTimeout(context.Background(), c.timeout)
-		if _, err := c.client.Put(ctx, key, base64Value); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		resp, err := c.client.Txn(ctx).If(cmps...).Then(thenOps...).Commit()
+		cancel()
+		if err != nil {
 			switch err {
 			case context.DeadlineExceeded:
 				log.Warn(err)
@@ -151,12 +361,50 @@ func (c *EtcdClient) Set(key string, value string) error {
 				continue
 			default:
 				log.Error(err)
-				return err
+				return false, err
 			}
 		}
-		return nil
+		succeeded = resp.Succeeded
+		return resp.Succeeded, nil
 	}
-	return errors.New("failed to set " + key)
+	return false, errors.New("failed to commit txn")
+}
+
+// revoke 撤销租约，撤销失败时租约在到期后自动失效，因此只记录日志
+func (c *EtcdClient) revoke(leases map[time.Duration]clientv3.LeaseID) {
+	for _, leaseID := range leases {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		if _, err := c.client.Revoke(ctx, leaseID); err != nil {
+			log.Warn(err)
+		}
+		cancel()
+	}
+}
+
+// grant 申请指定存活时间的租约
+func (c *EtcdClient) grant(ttl time.Duration) (clientv3.LeaseID, error) {
+	seconds := int64(ttl / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	for retry := 0; retry < c.retryTimes; retry++ {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		resp, err := c.client.Grant(ctx, seconds)
+		cancel()
+		if err != nil {
+			switch err {
+			case context.DeadlineExceeded:
+				log.Warn(err)
+				// 出现超时错误时进行重试
+				continue
+			default:
+				log.Error(err)
+				return 0, err
+			}
+		}
+		return resp.ID, nil
+	}
+	return 0, errors.New("failed to grant lease")
 }
 
 func (c *EtcdClient) Delete(key string) (string, error) {
@@ -187,6 +435,11 @@ func (c *EtcdClient) Delete(key string) (string, error) {
 }
 
 func (c *EtcdClient) List(key string, withPrefix bool) (map[string]string, error) {
+	result, _, err := c.ListWithRevision(key, withPrefix)
+	return result, err
+}
+
+func (c *EtcdClient) ListWithRevision(key string, withPrefix bool) (map[string]string, int64, error) {
 	var err error
 	var resp *clientv3.GetResponse
 
@@ -205,20 +458,20 @@ func (c *EtcdClient) List(key string, withPrefix bool) (map[string]string, error
 				continue
 			default:
 				log.Error(err)
-				return nil, err
+				return nil, 0, err
 			}
 		}
 		result := make(map[string]string)
 		for _, kvs := range resp.Kvs {
 			valueBytes, err := base64.RawStdEncoding.DecodeString(string(kvs.Value))
 			if err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 			result[string(kvs.Key)] = string(valueBytes)
 		}
-		return result, nil
+		return result, resp.Header.Revision, nil
 	}
-	return nil, errors.New("failed to list " + key)
+	return nil, 0, errors.New("failed to list " + key)
 }
 
 func (c *EtcdClient) Watch(ctx context.Context, key string, withPrefix bool) <-chan KVAction {
@@ -226,37 +479,179 @@ func (c *EtcdClient) Watch(ctx context.Context, key string, withPrefix bool) <-c
 		return nil
 	}
 
-	var kvsWatcher clientv3.WatchChan
-	if withPrefix {
-		kvsWatcher = c.client.Watch(ctx, key, clientv3.WithPrefix(), clientv3.WithPrevKV())
-	} else {
-		kvsWatcher = c.client.Watch(ctx, key, clientv3.WithPrevKV())
-	}
 	watcher := make(chan KVAction, 1000)
 	go func() {
 		defer close(watcher)
+
+		// 从当前修订版本的下一个版本开始侦听
+		revision, err := c.currentRevision(ctx, key)
+		if err != nil {
+			return
+		}
 		for {
-			select {
-			case <-ctx.Done():
+			compactRevision := c.watchFrom(ctx, key, withPrefix, revision+1, nil, watcher)
+			if ctx.Err() != nil {
 				return
-			case wresp, ok := <-kvsWatcher:
-				if !ok {
+			}
+			// 侦听的起始版本已被压缩，中间的变更无法找回，只能从压缩后的最小版本继续侦听
+			log.Warnf("watch %s from revision %d has been compacted, resume from revision %d", key, revision+1, compactRevision)
+			revision = compactRevision - 1
+		}
+	}()
+	return watcher
+}
+
+func (c *EtcdClient) ListWatch(ctx context.Context, key string, withPrefix bool) <-chan KVAction {
+	watcher := make(chan KVAction, 1000)
+	go func() {
+		defer close(watcher)
+
+		// 记录已推送的键值，用于重新同步时识别期间被删除的键
+		known := make(map[string]string)
+		for {
+			revision, err := c.snapshot(ctx, key, withPrefix, known, watcher)
+			if err != nil {
+				if ctx.Err() != nil {
 					return
 				}
+				time.Sleep(time.Second)
+				continue
+			}
+			c.watchFrom(ctx, key, withPrefix, revision+1, known, watcher)
+			if ctx.Err() != nil {
+				return
+			}
+			// 侦听的起始版本已被压缩，重新获取快照进行同步
+			log.Warnf("watch %s from revision %d has been compacted, resync", key, revision+1)
+		}
+	}()
+	return watcher
+}
+
+func (c *EtcdClient) WatchFrom(ctx context.Context, key string, withPrefix bool, revision int64) (<-chan KVAction, error) {
+	// 读取指定修订版本以确认其未被压缩
+	getCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	_, err := c.client.Get(getCtx, key, clientv3.WithRev(revision), clientv3.WithCountOnly())
+	cancel()
+	if err == rpctypes.ErrCompacted {
+		return nil, e.ExpiredRevisionError{Revision: revision}
+	} else if err != nil && err != rpctypes.ErrFutureRev {
+		log.Error(err)
+		return nil, err
+	}
+
+	watcher := make(chan KVAction, 1000)
+	go func() {
+		defer close(watcher)
+
+		// 侦听期间起始版本被压缩时结束侦听，由调用方重新获取全量数据
+		if compactRevision := c.watchFrom(ctx, key, withPrefix, revision+1, nil, watcher); compactRevision != 0 {
+			log.Warnf("watch %s from revision %d has been compacted", key, revision+1)
+		}
+	}()
+	return watcher, nil
+}
+
+// currentRevision 获取etcd当前的修订版本号
+func (c *EtcdClient) currentRevision(ctx context.Context, key string) (int64, error) {
+	for {
+		getCtx, cancel := context.WithTimeout(ctx, c.timeout)
+		resp, err := c.client.Get(getCtx, key, clientv3.WithCountOnly())
+		cancel()
+		if err == nil {
+			return resp.Header.Revision, nil
+		}
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		log.Warn(err)
+		time.Sleep(time.Second)
+	}
+}
+
+// snapshot 获取当前所有键值并作为set事件推送，对于已推送过但已不存在的键推送delete事件，返回快照对应的修订版本号
+func (c *EtcdClient) snapshot(ctx context.Context, key string, withPrefix bool, known map[string]string, watcher chan<- KVAction) (int64, error) {
+	opts := []clientv3.OpOption{}
+	if withPrefix {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+	getCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	resp, err := c.client.Get(getCtx, key, opts...)
+	cancel()
+	if err != nil {
+		log.Error(err)
+		return 0, err
+	}
+
+	current := make(map[string]struct{})
+	for _, kv := range resp.Kvs {
+		valueBytes, err := base64.RawStdEncoding.DecodeString(string(kv.Value))
+		if err != nil {
+			log.Error(err)
+		}
+		current[string(kv.Key)] = struct{}{}
+		if !sendKVAction(ctx, watcher, known, KVAction{
+			Key:        string(kv.Key),
+			Value:      string(valueBytes),
+			ActionType: KVActionTypeSet,
+			Revision:   kv.ModRevision,
+		}) {
+			return 0, ctx.Err()
+		}
+	}
+	for k, v := range known {
+		if _, ok := current[k]; ok {
+			continue
+		}
+		if !sendKVAction(ctx, watcher, known, KVAction{
+			Key:        k,
+			Value:      v,
+			ActionType: KVActionTypeDelete,
+			Revision:   resp.Header.Revision,
+		}) {
+			return 0, ctx.Err()
+		}
+	}
+	return resp.Header.Revision, nil
+}
+
+// watchFrom 从指定修订版本开始侦听变更，网络中断时从最后收到的修订版本自动重连。
+// 当起始版本已被压缩时返回压缩后的最小修订版本号，上下文结束时返回0
+func (c *EtcdClient) watchFrom(ctx context.Context, key string, withPrefix bool, revision int64, known map[string]string, watcher chan<- KVAction) int64 {
+	for {
+		watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+		opts := []clientv3.OpOption{clientv3.WithPrevKV(), clientv3.WithRev(revision)}
+		if withPrefix {
+			opts = append(opts, clientv3.WithPrefix())
+		}
+		kvsWatcher := c.client.Watch(watchCtx, key, opts...)
+
+		compactRevision, ok := func() (int64, bool) {
+			for wresp := range kvsWatcher {
+				if wresp.CompactRevision != 0 {
+					return wresp.CompactRevision, true
+				}
+				if err := wresp.Err(); err != nil {
+					log.Warn(err)
+					return 0, false
+				}
 				for _, event := range wresp.Events {
+					var action KVAction
 					switch event.Type {
 					case mvccpb.PUT:
 						valueBytes, err := base64.RawStdEncoding.DecodeString(string(event.Kv.Value))
 						if err != nil {
 							log.Error(err)
 						}
-						watcher <- KVAction{
+						action = KVAction{
 							Key:        string(event.Kv.Key),
 							Value:      string(valueBytes),
 							ActionType: KVActionTypeSet,
+							Revision:   event.Kv.ModRevision,
 						}
 					case mvccpb.DELETE:
 						if event.PrevKv == nil {
+							revision = event.Kv.ModRevision + 1
 							continue
 						}
 
@@ -264,19 +659,56 @@ func (c *EtcdClient) Watch(ctx context.Context, key string, withPrefix bool) <-c
 						if err != nil {
 							log.Error(err)
 						}
-						watcher <- KVAction{
+						action = KVAction{
 							Key:        string(event.PrevKv.Key),
 							Value:      string(valueBytes),
 							ActionType: KVActionTypeDelete,
+							Revision:   event.Kv.ModRevision,
 						}
 					default:
 						log.Warn(errors.New("unknown kv action type"))
+						continue
+					}
+					// 记录最后收到的修订版本，重连时从下一个版本开始侦听
+					revision = action.Revision + 1
+					if !sendKVAction(ctx, watcher, known, action) {
+						return 0, false
 					}
 				}
 			}
+			return 0, false
+		}()
+		cancel()
+
+		if ctx.Err() != nil {
+			return 0
 		}
-	}()
-	return watcher
+		if ok {
+			return compactRevision
+		}
+
+		// 侦听通道因网络异常等原因关闭，稍后从最后收到的修订版本重连
+		log.Warnf("watch %s interrupted, reconnect from revision %d", key, revision)
+		time.Sleep(time.Second)
+	}
+}
+
+// sendKVAction 推送变更并记录已推送的键值，上下文结束时返回false
+func sendKVAction(ctx context.Context, watcher chan<- KVAction, known map[string]string, action KVAction) bool {
+	select {
+	case watcher <- action:
+	case <-ctx.Done():
+		return false
+	}
+	if known != nil {
+		switch action.ActionType {
+		case KVActionTypeSet:
+			known[action.Key] = action.Value
+		case KVActionTypeDelete:
+			delete(known, action.Key)
+		}
+	}
+	return true
 }
 
 func (c *EtcdClient) Lock(ctx context.Context, key string) error {
@@ -121,3 +121,59 @@ func TestMutex(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestTTL(t *testing.T) {
+	eventKey := RegistryPrefix + "/events/default/event1"
+	indexKey := RegistryPrefix + "/indexes/events/type/Normal/default%2Fevent1"
+	jobKey := RegistryPrefix + "/jobs/job1"
+	defer db.KV.Delete(jobKey)
+
+	if ok, err := db.KV.Txn(db.OpCompareAndSet(eventKey, "1", 0).WithTTL(2*time.Second), db.OpSet(indexKey, eventKey).WithTTL(2*time.Second), db.OpSet(jobKey, "1")); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("txn should succeed")
+	}
+	// 事务未成功提交时撤销为其申请的租约
+	if etcdClient, ok := db.KV.(*db.EtcdClient); ok {
+		count, err := etcdClient.LeaseCount()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := db.KV.Txn(db.OpCompareAndSet(eventKey, "1", 0).WithTTL(2 * time.Second)); err != nil {
+			t.Fatal(err)
+		} else if ok {
+			t.Fatal("txn on existing key should fail")
+		}
+		if newCount, err := etcdClient.LeaseCount(); err != nil {
+			t.Fatal(err)
+		} else if newCount != count {
+			t.Fatalf("lease of failed txn should be revoked, %d leases before and %d after", count, newCount)
+		}
+	}
+	// 未指定存活时间的写入保留键原有的租约
+	if err := db.KV.Set(eventKey, "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 租约到期后同一事务中写入的键值同时删除
+	timeout := time.After(10 * time.Second)
+	for {
+		result, err := db.KV.List(RegistryPrefix+"/", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := result[eventKey]; !ok {
+			if _, ok := result[indexKey]; ok {
+				t.Fatal("index key should expire with event")
+			} else if result[jobKey] != "1" {
+				t.Fatal("job should not expire")
+			}
+			return
+		}
+		select {
+		case <-timeout:
+			t.Fatal("event should expire")
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
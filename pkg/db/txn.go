@@ -0,0 +1,61 @@
+package db
+
+import "time"
+
+// KVOp 事务中的单个写入操作
+type KVOp struct {
+	// 操作类型，取值为KVActionTypeSet或KVActionTypeDelete
+	ActionType string
+	Key        string
+	Value      string
+	// 是否在提交前比较键的修订版本号
+	Compare bool
+	// 期望的修订版本号，0表示键必须不存在
+	Revision int64
+	// 键值的存活时间，大于0时到期后自动删除，否则保留键原有的存活时间。同一事务中存活时间相同的键值同时到期
+	TTL time.Duration
+}
+
+// OpSet 写入键值
+func OpSet(key string, value string) KVOp {
+	return KVOp{
+		ActionType: KVActionTypeSet,
+		Key:        key,
+		Value:      value,
+	}
+}
+
+// OpCompareAndSet 仅当键的修订版本号与指定值一致时写入键值
+func OpCompareAndSet(key string, value string, revision int64) KVOp {
+	return KVOp{
+		ActionType: KVActionTypeSet,
+		Key:        key,
+		Value:      value,
+		Compare:    true,
+		Revision:   revision,
+	}
+}
+
+// OpDelete 删除键
+func OpDelete(key string) KVOp {
+	return KVOp{
+		ActionType: KVActionTypeDelete,
+		Key:        key,
+	}
+}
+
+// OpCompareAndDelete 仅当键的修订版本号与指定值一致时删除键
+func OpCompareAndDelete(key string, revision int64) KVOp {
+	return KVOp{
+		ActionType: KVActionTypeDelete,
+		Key:        key,
+		Compare:    true,
+		Revision:   revision,
+	}
+}
+
+// WithTTL 为写入操作设置存活时间
+func (op KVOp) WithTTL(ttl time.Duration) KVOp {
+	op.TTL = ttl
+	return op
+}
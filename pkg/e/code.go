@@ -4,6 +4,8 @@ const (
 	SUCCESS        = 0
 	ERROR          = 500
 	INVALID_PARAMS = 400
+	FORBIDDEN      = 403
+	CONFLICT       = 409
 
 	ERROR_EXIST_TAG       = 10001
 	ERROR_EXIST_TAG_FAIL  = 10002
@@ -3,6 +3,7 @@ package e
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 func Errorf(format string, args ...interface{}) error {
@@ -25,6 +26,14 @@ func (e ResourceNotFoundError) Error() string {
 	return fmt.Sprintf("资源 %s 不存在", e.Key)
 }
 
+type ResourceConflictError struct {
+	Key string
+}
+
+func (e ResourceConflictError) Error() string {
+	return fmt.Sprintf("资源 %s 已被修改，请获取最新版本后重试", e.Key)
+}
+
 type OperationForbidenError struct{}
 
 func (e OperationForbidenError) Error() string {
@@ -47,8 +56,132 @@ func (e InvalidNameError) Error() string {
 	return fmt.Sprintf("无效的名称 %s", e.Name)
 }
 
+type InvalidContinueError struct {
+	Continue string
+}
+
+func (e InvalidContinueError) Error() string {
+	return fmt.Sprintf("无效的分页令牌 %s", e.Continue)
+}
+
+type InvalidSelectorError struct {
+	Selector string
+}
+
+func (e InvalidSelectorError) Error() string {
+	return fmt.Sprintf("无效的选择器 %s", e.Selector)
+}
+
+type InvalidPatchError struct {
+	Reason string
+}
+
+func (e InvalidPatchError) Error() string {
+	return fmt.Sprintf("无效的补丁: %s", e.Reason)
+}
+
+type InvalidFieldError struct {
+	Field  string
+	Reason string
+}
+
+func (e InvalidFieldError) Error() string {
+	return fmt.Sprintf("字段 %s 无效: %s", e.Field, e.Reason)
+}
+
+// InvalidFieldsError 多个字段校验失败
+type InvalidFieldsError []InvalidFieldError
+
+func (e InvalidFieldsError) Error() string {
+	msgs := []string{}
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+type UnsupportedPatchTypeError struct {
+	PatchType string
+}
+
+func (e UnsupportedPatchTypeError) Error() string {
+	return fmt.Sprintf("不支持的补丁类型 %s", e.PatchType)
+}
+
+type InvalidPropagationPolicyError struct {
+	Policy string
+}
+
+func (e InvalidPropagationPolicyError) Error() string {
+	return fmt.Sprintf("无效的级联删除策略 %s", e.Policy)
+}
+
+type AdmissionDeniedError struct {
+	Webhook string
+	Reason  string
+}
+
+func (e AdmissionDeniedError) Error() string {
+	return fmt.Sprintf("准入控制钩子 %s 拒绝了请求: %s", e.Webhook, e.Reason)
+}
+
+type AdmissionWebhookError struct {
+	Webhook string
+	Reason  string
+}
+
+func (e AdmissionWebhookError) Error() string {
+	return fmt.Sprintf("调用准入控制钩子 %s 失败: %s", e.Webhook, e.Reason)
+}
+
 type JobExecTimeoutError struct{}
 
 func (e JobExecTimeoutError) Error() string {
 	return "任务运行超时"
 }
+
+type UnauthorizedError struct {
+	Reason string
+}
+
+func (e UnauthorizedError) Error() string {
+	return fmt.Sprintf("认证失败: %s", e.Reason)
+}
+
+type AccessDeniedError struct {
+	User string
+	Verb string
+	Kind string
+	Key  string
+}
+
+func (e AccessDeniedError) Error() string {
+	return fmt.Sprintf("用户 %s 无权对 %s %s 执行 %s 操作", e.User, e.Kind, e.Key, e.Verb)
+}
+
+type ExpiredRevisionError struct {
+	Revision int64
+}
+
+func (e ExpiredRevisionError) Error() string {
+	return fmt.Sprintf("修订版本 %d 已过期，请重新获取资源列表后再侦听", e.Revision)
+}
+
+type InvalidBackupError struct {
+	Reason string
+}
+
+func (e InvalidBackupError) Error() string {
+	return fmt.Sprintf("无效的备份归档: %s", e.Reason)
+}
+
+// PartialRestoreError 还原过程中写入存储失败，备份中的数据只有部分被还原
+type PartialRestoreError struct {
+	Restored int
+	Total    int
+	Reason   string
+}
+
+func (e PartialRestoreError) Error() string {
+	return fmt.Sprintf("还原未完成，已还原备份中的 %d/%d 条数据: %s", e.Restored, e.Total, e.Reason)
+}
@@ -4,6 +4,8 @@ var MsgFlags = map[int]string{
 	SUCCESS:                         "ok",
 	ERROR:                           "fail",
 	INVALID_PARAMS:                  "请求参数错误",
+	FORBIDDEN:                       "禁止访问",
+	CONFLICT:                        "资源已被修改",
 	ERROR_EXIST_TAG:                 "已存在该标签名称",
 	ERROR_EXIST_TAG_FAIL:            "获取已存在标签失败",
 	ERROR_NOT_EXIST_TAG:             "该标签不存在",
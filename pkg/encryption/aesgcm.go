@@ -0,0 +1,246 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/setting"
+)
+
+const (
+	// 密文前缀，完整的密文格式为 enc:aesgcm:<密钥ID>:<base64编码的随机数与密文>
+	ciphertextPrefix = "enc:aesgcm:"
+	// AES-256密钥长度
+	keySize = 32
+)
+
+// AESGCMEncrypter 使用AES-GCM算法加密字段，实现了registry.Encrypter接口。
+// 密钥文件中每行一个密钥，第一行为加密使用的主密钥，其余密钥仅用于解密由旧密钥加密的数据
+type AESGCMEncrypter struct {
+	keyFile string
+	primary string
+	aeads   map[string]cipher.AEAD
+	mutex   sync.RWMutex
+}
+
+// Encrypt 使用主密钥加密明文，附加数据参与认证
+func (enc *AESGCMEncrypter) Encrypt(plaintext string, additionalData string) (string, error) {
+	enc.mutex.RLock()
+	defer enc.mutex.RUnlock()
+
+	aead := enc.aeads[enc.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(additionalData))
+	return ciphertextPrefix + enc.primary + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 根据密文中的密钥ID选择对应的密钥解密，附加数据与加密时不一致时解密失败
+func (enc *AESGCMEncrypter) Decrypt(ciphertext string, additionalData string) (string, error) {
+	keyID, sealed, err := parseCiphertext(ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	enc.mutex.RLock()
+	aead, ok := enc.aeads[keyID]
+	enc.mutex.RUnlock()
+	if !ok {
+		return "", e.Errorf("encryption key %s not found", keyID)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", e.Errorf("invalid ciphertext")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(additionalData))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// Encrypted 判断值是否为密文
+func (enc *AESGCMEncrypter) Encrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
+
+// Stale 判断密文是否由非主密钥加密
+func (enc *AESGCMEncrypter) Stale(ciphertext string) bool {
+	keyID, _, err := parseCiphertext(ciphertext)
+	if err != nil {
+		return false
+	}
+	enc.mutex.RLock()
+	defer enc.mutex.RUnlock()
+	return keyID != enc.primary
+}
+
+// Rotate 生成新的主密钥并写入密钥文件首行，原有的密钥保留用于解密
+func (enc *AESGCMEncrypter) Rotate() (string, error) {
+	enc.mutex.Lock()
+	defer enc.mutex.Unlock()
+
+	keyID, err := generateKey(enc.keyFile)
+	if err != nil {
+		return "", err
+	}
+	primary, aeads, err := loadKeyFile(enc.keyFile)
+	if err != nil {
+		return "", err
+	}
+	enc.primary = primary
+	enc.aeads = aeads
+	log.Infof("rotated encryption key to %s", keyID)
+	return keyID, nil
+}
+
+// parseCiphertext 拆分密文中的密钥ID与加密数据
+func parseCiphertext(ciphertext string) (string, []byte, error) {
+	parts := strings.SplitN(strings.TrimPrefix(ciphertext, ciphertextPrefix), ":", 2)
+	if !strings.HasPrefix(ciphertext, ciphertextPrefix) || len(parts) != 2 {
+		return "", nil, e.Errorf("invalid ciphertext")
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, err
+	}
+	return parts[0], sealed, nil
+}
+
+// loadKeyFile 读取密钥文件，返回主密钥ID与所有密钥
+func loadKeyFile(keyFile string) (string, map[string]cipher.AEAD, error) {
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var primary string
+	aeads := make(map[string]cipher.AEAD)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return "", nil, e.Errorf("invalid encryption key line in %s", keyFile)
+		}
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return "", nil, err
+		}
+		if len(key) != keySize {
+			return "", nil, e.Errorf("encryption key %s must be %d bytes", parts[0], keySize)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return "", nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return "", nil, err
+		}
+		if primary == "" {
+			primary = parts[0]
+		}
+		aeads[parts[0]] = aead
+	}
+	if primary == "" {
+		return "", nil, e.Errorf("no encryption key found in %s", keyFile)
+	}
+	return primary, aeads, nil
+}
+
+// generateKey 生成新的随机密钥并写入密钥文件首行，文件不存在时创建
+func generateKey(keyFile string) (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	keyID := fmt.Sprintf("%s-%x", time.Now().Format("20060102"), suffix)
+
+	data, err := ioutil.ReadFile(keyFile)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return "", err
+	}
+	content := keyID + ":" + base64.StdEncoding.EncodeToString(key) + "\n" + string(data)
+	if err := ioutil.WriteFile(keyFile, []byte(content), 0600); err != nil {
+		return "", err
+	}
+	return keyID, nil
+}
+
+// NewAESGCMEncrypter 从密钥文件创建加密器，密钥文件不存在时自动生成
+func NewAESGCMEncrypter(keyFile string) (*AESGCMEncrypter, error) {
+	if _, err := os.Stat(keyFile); os.IsNotExist(err) {
+		keyID, err := generateKey(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("generated encryption key %s to %s", keyID, keyFile)
+	}
+
+	primary, aeads, err := loadKeyFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMEncrypter{
+		keyFile: keyFile,
+		primary: primary,
+		aeads:   aeads,
+	}, nil
+}
+
+var defaultEncrypter *AESGCMEncrypter
+
+// Setup 根据配置开启敏感字段的加密存储
+func Setup() error {
+	if setting.EncryptionSetting.KeyFile == "" {
+		return nil
+	}
+	enc, err := NewAESGCMEncrypter(setting.EncryptionSetting.KeyFile)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	defaultEncrypter = enc
+	registry.SetEncrypter(enc)
+	return nil
+}
+
+// RotateKey 生成新的主密钥，并使用新密钥重新加密数据库中的所有资源对象
+func RotateKey() (string, error) {
+	if defaultEncrypter == nil {
+		return "", e.Errorf("encryption is not enabled")
+	}
+	keyID, err := defaultEncrypter.Rotate()
+	if err != nil {
+		log.Error(err)
+		return "", err
+	}
+	if err := registry.EncryptStorage(); err != nil {
+		return "", err
+	}
+	return keyID, nil
+}
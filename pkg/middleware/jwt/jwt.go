@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/setting"
+)
+
+const (
+	bearerPrefix = "Bearer "
+
+	// 浏览器无法为websocket连接设置请求头，此时可通过该查询参数传递访问令牌
+	tokenQuery = "access_token"
+	// 允许通过查询参数传递访问令牌的websocket接口路径后缀，即任务日志接口
+	tokenQueryPathSuffix = "/log"
+
+	// 请求上下文中保存查询参数访问令牌的键
+	contextKeyQueryToken = "queryToken"
+)
+
+// StripQueryToken 将查询参数中的访问令牌移至请求上下文，避免令牌被记录到访问日志中，需要在gin.Logger之前注册
+func StripQueryToken() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query := ctx.Request.URL.Query()
+		if token := query.Get(tokenQuery); token != "" {
+			ctx.Set(contextKeyQueryToken, token)
+			query.Del(tokenQuery)
+			ctx.Request.URL.RawQuery = query.Encode()
+		}
+		ctx.Next()
+	}
+}
+
+// JWT 校验请求头 Authorization: Bearer <访问令牌或服务账号令牌>，通过后将用户名保存至请求上下文，未启用认证时直接放行。
+// 任务日志的websocket请求也可以通过查询参数access_token传递访问令牌
+func JWT() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !setting.AuthSetting.Enabled {
+			ctx.Next()
+			return
+		}
+
+		var token string
+		if websocket.IsWebSocketUpgrade(ctx.Request) && strings.HasSuffix(ctx.Request.URL.Path, tokenQueryPathSuffix) {
+			token = ctx.GetString(contextKeyQueryToken)
+			if token == "" {
+				token = ctx.Query(tokenQuery)
+			}
+		}
+		if header := ctx.GetHeader("Authorization"); strings.HasPrefix(header, bearerPrefix) {
+			token = strings.TrimSpace(strings.TrimPrefix(header, bearerPrefix))
+		}
+		if token == "" {
+			abort(ctx, http.StatusUnauthorized, e.ERROR_AUTH, e.GetMsg(e.ERROR_AUTH))
+			return
+		}
+
+		identity, err := auth.Authenticate(token)
+		if err != nil {
+			if _, ok := err.(e.UnauthorizedError); ok {
+				abort(ctx, http.StatusUnauthorized, e.ERROR_AUTH_CHECK_TOKEN_FAIL, err.Error())
+			} else {
+				abort(ctx, http.StatusInternalServerError, e.ERROR, err.Error())
+			}
+			return
+		}
+
+		ctx.Set(auth.ContextKeyUsername, identity.Username)
+		if len(identity.Scopes) > 0 {
+			ctx.Set(auth.ContextKeyScopes, identity.Scopes)
+		}
+		ctx.Next()
+	}
+}
+
+func abort(ctx *gin.Context, httpCode int, opCode int, opMsg string) {
+	ctx.AbortWithStatusJSON(httpCode, controller.Response{
+		OpCode: opCode,
+		OpDesc: opMsg,
+	})
+}
@@ -20,23 +20,6 @@ import (
 	"github.com/wujie1993/waves/pkg/setting"
 )
 
-const (
-	ArgTypeInteger = "integer"
-	ArgTypeNumber  = "number"
-	ArgTypeString  = "string"
-	ArgTypeBoolean = "boolean"
-
-	ArgFormatInt32     = "int32"
-	ArgFormatInt64     = "int64"
-	ArgFormatPort      = "port"
-	ArgFormatFloat     = "float"
-	ArgFormatDouble    = "double"
-	ArgFormatDate      = "date"
-	ArgFormatPassword  = "password"
-	ArgFormatArray     = "array"
-	ArgFormatGroupHost = "groupHost"
-)
-
 // ModuleAction 描述应用实例模块中每个主机所需要执行的操作，用于生成任务
 type ModuleAction struct {
 	ModuleName    string
@@ -117,22 +100,6 @@ func (o AppInstanceOperator) finalizeAppInstance(ctx context.Context, obj core.A
 	appInstance := obj.(*v2.AppInstance)
 	// 每次只处理一项Finalizer
 	switch appInstance.Metadata.Finalizers[0] {
-	case core.FinalizerCleanRefEvent:
-		// 同步删除关联的事件
-		eventList, err := o.helper.V1.Event.List(context.TODO(), "")
-		if err != nil {
-			log.Error(err)
-			return err
-		}
-		for _, eventObj := range eventList {
-			event := eventObj.(*v1.Event)
-			if event.Spec.ResourceRef.Kind == core.KindAppInstance && event.Spec.ResourceRef.Namespace == appInstance.Metadata.Namespace && event.Spec.ResourceRef.Name == appInstance.Metadata.Name {
-				if _, err := o.helper.V1.Event.Delete(context.TODO(), "", event.Metadata.Name); err != nil {
-					log.Error(err)
-					return err
-				}
-			}
-		}
 	case core.FinalizerCleanRefConfigMap:
 		// 清除关联的配置字典
 		for _, module := range appInstance.Spec.Modules {
@@ -340,13 +307,13 @@ func (o *AppInstanceOperator) healthCheck(ctx context.Context, obj core.ApiObjec
 					log.Debugf("healthcheck succeed of %s", appInstance.GetKey())
 
 					// 清除健康检查历史事件日志
-					eventObjs, err := o.helper.V1.Event.List(context.TODO(), "")
+					eventObjs, err := o.helper.V1.Event.ListByIndex(context.TODO(), core.IndexResourceRef, core.IndexValue(core.KindAppInstance, appInstance.Metadata.Namespace, appInstance.Metadata.Name))
 					if err != nil {
 						log.Error(err)
 					} else {
 						for _, eventObj := range eventObjs {
 							event := eventObj.(*v1.Event)
-							if event.Spec.ResourceRef.Namespace == appInstance.Metadata.Namespace && event.Spec.ResourceRef.Name == appInstance.Metadata.Name && event.Spec.ResourceRef.Kind == core.KindAppInstance && event.Spec.Action == core.EventActionHealthCheck {
+							if event.Spec.Action == core.EventActionHealthCheck {
 								if _, err := o.helper.V1.Event.Delete(context.TODO(), "", event.Metadata.Name); err != nil {
 									log.Error(err)
 								}
@@ -439,13 +406,13 @@ func (o AppInstanceOperator) failback(obj core.ApiObject, action string, reason
 		}
 	case core.EventActionHealthCheck:
 		// 清除健康检查历史事件日志
-		eventObjs, err := o.helper.V1.Event.List(context.TODO(), "")
+		eventObjs, err := o.helper.V1.Event.ListByIndex(context.TODO(), core.IndexResourceRef, core.IndexValue(core.KindAppInstance, appInstance.Metadata.Namespace, appInstance.Metadata.Name))
 		if err != nil {
 			log.Error(err)
 		} else {
 			for _, eventObj := range eventObjs {
 				event := eventObj.(*v1.Event)
-				if event.Spec.ResourceRef.Namespace == appInstance.Metadata.Namespace && event.Spec.ResourceRef.Name == appInstance.Metadata.Name && event.Spec.ResourceRef.Kind == core.KindAppInstance && event.Spec.Action == core.EventActionHealthCheck {
+				if event.Spec.Action == core.EventActionHealthCheck {
 					if _, err := o.helper.V1.Event.Delete(context.TODO(), "", event.Metadata.Name); err != nil {
 						log.Error(err)
 					}
@@ -464,18 +431,32 @@ func (o AppInstanceOperator) failback(obj core.ApiObject, action string, reason
 			log.Error(err)
 		}
 
-		// 在健康状态发生变化时更新
-		if appInstance.Status.GetCondition(core.ConditionTypeHealthy) != reason {
-			appInstance.Status.SetCondition(core.ConditionTypeHealthy, reason)
-			if _, err := o.helper.V2.AppInstance.Update(context.TODO(), appInstance, core.WithAllFields()); err != nil {
-				log.Error(err)
+		// 在健康状态发生变化时更新，发生冲突时基于最新的应用实例重试
+		if err := registry.RetryOnConflict(func() error {
+			obj, err := o.helper.V2.AppInstance.Get(context.TODO(), appInstance.Metadata.Namespace, appInstance.Metadata.Name, core.WithoutDecorate())
+			if err != nil {
+				return err
+			} else if obj == nil {
+				return nil
+			}
+			latest := obj.(*v2.AppInstance)
+			if latest.Status.GetCondition(core.ConditionTypeHealthy) == reason {
+				return nil
 			}
+			latest.Status.SetCondition(core.ConditionTypeHealthy, reason)
+			_, err = o.helper.V2.AppInstance.Update(context.TODO(), latest, core.WithAllFields())
+			return err
+		}); err != nil {
+			log.Error(err)
 		}
 		return
 	}
 
 	// 更新应用实例状态
-	if _, err := o.helper.V2.AppInstance.UpdateStatus(appInstance.Metadata.Namespace, appInstance.Metadata.Name, appInstance.Status); err != nil {
+	if err := registry.RetryOnConflict(func() error {
+		_, err := o.helper.V2.AppInstance.UpdateStatus(appInstance.Metadata.Namespace, appInstance.Metadata.Name, appInstance.Status)
+		return err
+	}); err != nil {
 		log.Error(err)
 	}
 
@@ -603,6 +584,7 @@ func (o *AppInstanceOperator) setupJob(obj core.ApiObject, action string) (core.
 	job.Spec.Exec.Type = core.JobExecTypeAnsible
 	job.Spec.Exec.Ansible.Bin = setting.AnsibleSetting.Bin
 	job.Spec.Exec.Ansible.Plays = plays
+	job.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindAppInstance, appInstance.Metadata)}
 	if action == core.EventActionHealthCheck {
 		job.Spec.Exec.Ansible.RecklessMode = true
 	}
@@ -807,6 +789,7 @@ func (o AppInstanceOperator) setupUpgradeJob(oldAppInstance *v2.AppInstance, new
 	job.Spec.Exec.Type = core.JobExecTypeAnsible
 	job.Spec.Exec.Ansible.Bin = setting.AnsibleSetting.Bin
 	job.Spec.Exec.Ansible.Plays = plays
+	job.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindAppInstance, newAppInstance.Metadata)}
 	job.Spec.TimeoutSeconds = 3600
 	job.Spec.FailureThreshold = 1
 	if _, err := o.helper.V2.Job.Create(context.TODO(), job); err != nil {
@@ -876,55 +859,55 @@ func (o AppInstanceOperator) setupBareMetalJobPlay(appInstance *v2.AppInstance,
 					if appArg.Name == arg.Name {
 						// 根据参数类型填充参数值
 						switch appArg.Type {
-						case ArgTypeInteger:
+						case core.ArgTypeInteger:
 							// 由于json反序列化会将数字类型统一转为float64, 因此需要先用类型推断转为float64再转为int
 							var value interface{}
 							switch v := arg.Value.(type) {
 							case float64:
 								switch appArg.Format {
-								case ArgFormatInt32:
+								case core.ArgFormatInt32:
 									value = int32(v)
-								case ArgFormatInt64:
+								case core.ArgFormatInt64:
 									value = int64(v)
-								case ArgFormatPort:
+								case core.ArgFormatPort:
 									value = uint16(v)
 								default:
 									value = int64(v)
 								}
 							}
 							groupVars.Set(arg.Name, value)
-						case ArgTypeNumber:
+						case core.ArgTypeNumber:
 							var value interface{}
 							switch v := arg.Value.(type) {
 							case float64:
 								switch appArg.Format {
-								case ArgFormatFloat:
+								case core.ArgFormatFloat:
 									value = float32(v)
-								case ArgFormatDouble:
+								case core.ArgFormatDouble:
 									value = float64(v)
 								default:
 									value = float64(v)
 								}
 							}
 							groupVars.Set(arg.Name, value)
-						case ArgTypeBoolean:
+						case core.ArgTypeBoolean:
 							var value bool
 							switch v := arg.Value.(type) {
 							case bool:
 								value = v
 							}
 							groupVars.Set(arg.Name, value)
-						case ArgTypeString:
+						case core.ArgTypeString:
 							var value interface{}
 							switch v := arg.Value.(type) {
 							case string:
 								switch appArg.Format {
-								case ArgFormatDate:
+								case core.ArgFormatDate:
 									value, _ = time.Parse(time.RFC3339, v)
 									groupVars.Set(arg.Name, value)
-								case ArgFormatArray:
+								case core.ArgFormatArray:
 									groupVars.Set(arg.Name, strings.Split(v, ";"))
-								case ArgFormatGroupHost:
+								case core.ArgFormatGroupHost:
 									hostRefs := strings.Split(v, ";")
 									inventoryGroupHosts := make(map[string]ansible.InventoryHost)
 									for _, hostRef := range hostRefs {
@@ -1091,6 +1074,7 @@ func (o AppInstanceOperator) setupBareMetalJobPlay(appInstance *v2.AppInstance,
 	inventoryGroupHosts := make(map[string]ansible.InventoryHost)
 	tags := []string{}
 	algorithmGPUIDs := make(map[string]interface{})
+	gpuBindOps := []registry.BatchOp{}
 
 	// 构建inventory和tags
 	for hostRef, action := range moduleAction.HostActionMap {
@@ -1124,15 +1108,26 @@ func (o AppInstanceOperator) setupBareMetalJobPlay(appInstance *v2.AppInstance,
 
 		// 在每台主机上寻找型号匹配且空闲的GPU与实例绑定
 		if appModule.Resources.AlgorithmPlugin && requestGPU {
-			gpuID, err := o.allocGPUSlot(appInstance, moduleAction.ModuleName, moduleAction.ReplicaIndex, host, supportGPUModels, action)
+			gpuID, bindOp, err := o.allocGPUSlot(appInstance, moduleAction.ModuleName, moduleAction.ReplicaIndex, host, supportGPUModels, action)
 			if err != nil {
 				log.Error(err)
 				return play, err
 			}
+			if bindOp != nil {
+				gpuBindOps = append(gpuBindOps, *bindOp)
+			}
 			algorithmGPUIDs[hostRef] = gpuID
 		}
 	}
 
+	// 在同一事务中绑定副本在所有主机上的GPU，避免出现部分GPU已绑定而其余绑定失败的情况
+	if len(gpuBindOps) > 0 {
+		if _, err := registry.Commit(context.TODO(), gpuBindOps...); err != nil {
+			log.Error(err)
+			return play, err
+		}
+	}
+
 	// 为算法实例设置gpu插槽序号
 	groupVars["algorithm_gpu_ids"] = algorithmGPUIDs
 	inventory[module.Name] = ansible.InventoryGroup{
@@ -1256,55 +1251,55 @@ func (o AppInstanceOperator) setupK8sJobPlay(appInstance *v2.AppInstance, module
 					if appArg.Name == arg.Name {
 						// 根据参数类型填充参数值
 						switch appArg.Type {
-						case ArgTypeInteger:
+						case core.ArgTypeInteger:
 							// 由于json反序列化会将数字类型统一转为float64, 因此需要先用类型推断转为float64再转为int
 							var value interface{}
 							switch v := arg.Value.(type) {
 							case float64:
 								switch appArg.Format {
-								case ArgFormatInt32:
+								case core.ArgFormatInt32:
 									value = int32(v)
-								case ArgFormatInt64:
+								case core.ArgFormatInt64:
 									value = int64(v)
-								case ArgFormatPort:
+								case core.ArgFormatPort:
 									value = uint16(v)
 								default:
 									value = int64(v)
 								}
 							}
 							groupVars.Set(arg.Name, value)
-						case ArgTypeNumber:
+						case core.ArgTypeNumber:
 							var value interface{}
 							switch v := arg.Value.(type) {
 							case float64:
 								switch appArg.Format {
-								case ArgFormatFloat:
+								case core.ArgFormatFloat:
 									value = float32(v)
-								case ArgFormatDouble:
+								case core.ArgFormatDouble:
 									value = float64(v)
 								default:
 									value = float64(v)
 								}
 							}
 							groupVars.Set(arg.Name, value)
-						case ArgTypeBoolean:
+						case core.ArgTypeBoolean:
 							var value bool
 							switch v := arg.Value.(type) {
 							case bool:
 								value = v
 							}
 							groupVars.Set(arg.Name, value)
-						case ArgTypeString:
+						case core.ArgTypeString:
 							var value interface{}
 							switch v := arg.Value.(type) {
 							case string:
 								switch appArg.Format {
-								case ArgFormatDate:
+								case core.ArgFormatDate:
 									value, _ = time.Parse(time.RFC3339, v)
 									groupVars.Set(arg.Name, value)
-								case ArgFormatArray:
+								case core.ArgFormatArray:
 									groupVars.Set(arg.Name, strings.Split(v, ";"))
-								case ArgFormatGroupHost:
+								case core.ArgFormatGroupHost:
 									hostRefs := strings.Split(v, ";")
 									inventoryGroupHosts := make(map[string]ansible.InventoryHost)
 									for _, hostRef := range hostRefs {
@@ -1394,8 +1389,8 @@ func (o AppInstanceOperator) setupK8sJobPlay(appInstance *v2.AppInstance, module
 	return play, nil
 }
 
-// allocGPUSlot 将应用实例与主机上的GPU绑定并返回GPU插槽序号
-func (o AppInstanceOperator) allocGPUSlot(appInstance *v2.AppInstance, moduleName string, replicaIndex int, host *v1.Host, supportGPUModels []string, action string) (int, error) {
+// allocGPUSlot 返回应用实例在主机上使用的GPU插槽序号。安装时会挑选空闲的GPU，并返回待提交的GPU绑定操作
+func (o AppInstanceOperator) allocGPUSlot(appInstance *v2.AppInstance, moduleName string, replicaIndex int, host *v1.Host, supportGPUModels []string, action string) (int, *registry.BatchOp, error) {
 	switch action {
 	case core.AppActionInstall:
 		// 绑定GPU
@@ -1417,12 +1412,12 @@ func (o AppInstanceOperator) allocGPUSlot(appInstance *v2.AppInstance, moduleNam
 			gpuObj, err := o.helper.V1.GPU.Get(context.TODO(), "", gpuName)
 			if err != nil {
 				log.Error(err)
-				return -1, err
+				return -1, nil, err
 			}
 			if gpuObj == nil {
 				err := e.Errorf("gpu %s not found", gpuName)
 				log.Error(err)
-				return -1, err
+				return -1, nil, err
 			}
 			gpu := gpuObj.(*v1.GPU)
 			if gpu.Status.Phase != core.PhaseBound {
@@ -1435,28 +1430,25 @@ func (o AppInstanceOperator) allocGPUSlot(appInstance *v2.AppInstance, moduleNam
 					Replica: replicaIndex,
 				}
 				gpu.Status.Phase = core.PhaseBound
-				if _, err := o.helper.V1.GPU.Update(context.TODO(), gpu, core.WithAllFields()); err != nil {
-					log.Error(err)
-					return -1, err
-				}
-				return gpuInfo.ID, nil
+				bindOp := o.helper.V1.GPU.BatchUpdate(gpu, core.WithAllFields())
+				return gpuInfo.ID, &bindOp, nil
 			}
 		}
 	case core.AppActionUninstall, core.AppActionConfigure, core.AppActionHealthcheck:
 		// 获取已绑定的GPU
-		gpuObjs, err := o.helper.V1.GPU.List(context.TODO(), "")
+		gpuObjs, err := o.helper.V1.GPU.ListByIndex(context.TODO(), core.IndexAppInstanceRef, core.IndexValue(appInstance.Metadata.Namespace, appInstance.Metadata.Name))
 		if err != nil {
 			log.Error(err)
-			return -1, err
+			return -1, nil, err
 		}
 		for _, gpuObj := range gpuObjs {
 			gpu := gpuObj.(*v1.GPU)
-			if gpu.Spec.AppInstanceModuleRef.Namespace == appInstance.Metadata.Namespace && gpu.Spec.AppInstanceModuleRef.Name == appInstance.Metadata.Name && gpu.Spec.AppInstanceModuleRef.Module == moduleName && gpu.Spec.AppInstanceModuleRef.Replica == replicaIndex {
-				return gpu.Spec.Info.ID, nil
+			if gpu.Spec.AppInstanceModuleRef.Module == moduleName && gpu.Spec.AppInstanceModuleRef.Replica == replicaIndex {
+				return gpu.Spec.Info.ID, nil, nil
 			}
 		}
 	}
-	return -1, e.Errorf("host %s is not bound with gpu types %v", host.Metadata.Name, supportGPUModels)
+	return -1, nil, e.Errorf("host %s is not bound with gpu types %v", host.Metadata.Name, supportGPUModels)
 }
 
 // uninstallAppInstance 卸载应用实例
@@ -1495,10 +1487,11 @@ func (o AppInstanceOperator) uninstallAppInstance(ctx context.Context, appInstan
 			}
 
 			// 如果初始化任务执行成功, 将应用实例状态更新为已卸载并结束任务侦听
-			appInstance.Status.SetCondition(core.ConditionTypeInstalled, core.ConditionStatusFalse)
-			appInstance.Status.UnsetCondition(core.ConditionTypeHealthy)
-			appInstance.SetStatusPhase(core.PhaseUninstalled)
-			if _, err := o.helper.V2.AppInstance.Update(context.TODO(), appInstance, core.WithAllFields()); err != nil {
+			if err := o.updateLatest(appInstance, func(latest *v2.AppInstance) {
+				latest.Status.SetCondition(core.ConditionTypeInstalled, core.ConditionStatusFalse)
+				latest.Status.UnsetCondition(core.ConditionTypeHealthy)
+				latest.SetStatusPhase(core.PhaseUninstalled)
+			}); err != nil {
 				log.Error(err)
 				return true
 			}
@@ -1559,9 +1552,10 @@ func (o AppInstanceOperator) installAppInstance(ctx context.Context, appInstance
 			return false
 		case core.PhaseCompleted:
 			// 如果任务执行成功, 将应用实例状态更新为已安装并结束任务侦听
-			appInstance.Status.SetCondition(core.ConditionTypeInstalled, core.ConditionStatusTrue)
-			appInstance.SetStatusPhase(core.PhaseInstalled)
-			if _, err := o.helper.V2.AppInstance.Update(context.TODO(), appInstance, core.WithAllFields()); err != nil {
+			if err := o.updateLatest(appInstance, func(latest *v2.AppInstance) {
+				latest.Status.SetCondition(core.ConditionTypeInstalled, core.ConditionStatusTrue)
+				latest.SetStatusPhase(core.PhaseInstalled)
+			}); err != nil {
 				log.Error(err)
 				return true
 			}
@@ -1642,13 +1636,13 @@ func (o AppInstanceOperator) updateAppInstance(ctx context.Context, obj core.Api
 			}); err != nil {
 				log.Error(err)
 			}
-			delete(newAppInstance.Metadata.Annotations, core.AnnotationPrefix+"upgrade/last-applied-configuration")
 
 			// 如果任务执行成功, 将应用实例置为Installed状态
-			newAppInstance.Status.SetCondition(core.ConditionTypeInstalled, core.ConditionStatusTrue)
-			newAppInstance.SetStatusPhase(core.PhaseInstalled)
-
-			if _, err := o.helper.V2.AppInstance.Update(context.TODO(), newAppInstance, core.WithAllFields()); err != nil {
+			if err := o.updateLatest(newAppInstance, func(latest *v2.AppInstance) {
+				delete(latest.Metadata.Annotations, core.AnnotationPrefix+"upgrade/last-applied-configuration")
+				latest.Status.SetCondition(core.ConditionTypeInstalled, core.ConditionStatusTrue)
+				latest.SetStatusPhase(core.PhaseInstalled)
+			}); err != nil {
 				log.Error(err)
 			}
 			return true
@@ -1662,23 +1656,37 @@ func (o AppInstanceOperator) updateAppInstance(ctx context.Context, obj core.Api
 	})
 }
 
+// updateLatest 获取最新的应用实例并修改后写入，任务执行期间其他写入导致冲突时基于最新的应用实例重试，应用实例已不存在时不做处理
+func (o *AppInstanceOperator) updateLatest(appInstance *v2.AppInstance, mutate func(latest *v2.AppInstance)) error {
+	return registry.RetryOnConflict(func() error {
+		obj, err := o.helper.V2.AppInstance.Get(context.TODO(), appInstance.Metadata.Namespace, appInstance.Metadata.Name, core.WithoutDecorate())
+		if err != nil {
+			return err
+		} else if obj == nil {
+			return nil
+		}
+		latest := obj.(*v2.AppInstance)
+		mutate(latest)
+		_, err = o.helper.V2.AppInstance.Update(context.TODO(), latest, core.WithAllFields())
+		return err
+	})
+}
+
 // releaseGPU 释放应用实例中绑定的所有GPU
 func (o AppInstanceOperator) releaseGPU(appInstance *v2.AppInstance) error {
-	gpuObjs, err := o.helper.V1.GPU.List(context.TODO(), "")
+	gpuObjs, err := o.helper.V1.GPU.ListByIndex(context.TODO(), core.IndexAppInstanceRef, core.IndexValue(appInstance.Metadata.Namespace, appInstance.Metadata.Name))
 	if err != nil {
 		log.Error(err)
 		return err
 	}
 	for _, gpuObj := range gpuObjs {
 		gpu := gpuObj.(*v1.GPU)
-		if gpu.Spec.AppInstanceModuleRef.Namespace == appInstance.Metadata.Namespace && gpu.Spec.AppInstanceModuleRef.Name == appInstance.Metadata.Name {
-			gpu.Spec.AppInstanceModuleRef = v1.AppInstanceModuleRef{}
-			gpu.Status.Phase = core.PhaseWaiting
-			log.Debugf("%+v", gpu)
-			if _, err := o.helper.V1.GPU.Update(context.TODO(), gpu, core.WithAllFields()); err != nil {
-				log.Error(err)
-				return err
-			}
+		gpu.Spec.AppInstanceModuleRef = v1.AppInstanceModuleRef{}
+		gpu.Status.Phase = core.PhaseWaiting
+		log.Debugf("%+v", gpu)
+		if _, err := o.helper.V1.GPU.Update(context.TODO(), gpu, core.WithAllFields()); err != nil {
+			log.Error(err)
+			return err
 		}
 	}
 	return nil
@@ -0,0 +1,331 @@
+package operators
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+)
+
+// GarbageCollector 垃圾回收器，根据资源对象的所有者引用级联删除依赖对象。
+// 后台删除时所有者被删除后再删除依赖对象；前台删除时所有者处于删除中状态，直至依赖对象全部删除后才继续删除；
+// 保留依赖对象时从依赖对象中移除指向所有者的引用
+type GarbageCollector struct {
+	helper            *orm.Helper
+	ownerQueue        chan core.ApiObject
+	sweepPeriodSecond int
+	registries        []registry.ApiObjectRegistry
+}
+
+// Run 运行垃圾回收器
+func (gc *GarbageCollector) Run(ctx context.Context) {
+	// 开启分布式锁
+	lockKey := core.RegistryPrefix + "/locks/gc"
+	if err := db.KV.Lock(context.TODO(), lockKey); err != nil {
+		log.Error(err)
+		return
+	}
+	defer db.KV.Unlock(context.TODO(), lockKey)
+
+	// 通过共享缓存接收所有资源对象的变更，将被删除或等待处理依赖对象的所有者加入处理队列
+	gc.registries = registry.ListStorageRegistries()
+	for _, r := range gc.registries {
+		gc.helper.Informers.For(r).AddEventHandler(registry.ResourceEventHandler{
+			OnSet: func(obj core.ApiObject) {
+				if ctx.Err() == nil && waitingForGC(obj) {
+					gc.enqueue(obj)
+				}
+			},
+			OnDelete: func(obj core.ApiObject) {
+				if ctx.Err() != nil {
+					return
+				}
+				gc.enqueue(obj)
+				// 依赖对象被删除时，重新检查正在前台删除的所有者
+				for _, ref := range obj.GetMetadata().OwnerReferences {
+					if owner, err := gc.getOwner(ref); err == nil && owner != nil && waitingForGC(owner) {
+						gc.enqueue(owner)
+					}
+				}
+			},
+		})
+	}
+	log.Debug("garbage collector is running")
+
+	ticker := time.NewTicker(time.Duration(gc.sweepPeriodSecond) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("garbage collector stopped")
+			return
+		case owner := <-gc.ownerQueue:
+			gc.processOwner(ctx, owner)
+		case <-ticker.C:
+			gc.sweep(ctx)
+		}
+	}
+}
+
+func (gc *GarbageCollector) enqueue(obj core.ApiObject) {
+	select {
+	case gc.ownerQueue <- obj:
+	default:
+		// 队列已满时丢弃，遗漏的对象会在定时清理时处理
+		log.Warnf("garbage collector queue is full, drop %s", obj.GetKey())
+	}
+}
+
+// processOwner 根据所有者当前的状态处理其依赖对象
+func (gc *GarbageCollector) processOwner(ctx context.Context, owner core.ApiObject) {
+	kind := owner.GetMetaType().Kind
+	r := registry.GetStorageRegistry(kind)
+	if r == nil {
+		return
+	}
+	metadata := owner.GetMetadata()
+	current, err := r.Get(context.TODO(), metadata.Namespace, metadata.Name)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+
+	// 所有者已被删除，删除没有其他所有者的依赖对象
+	if current == nil || current.GetMetadata().Uid != metadata.Uid {
+		dependents, err := gc.listDependents(ctx, kind, metadata)
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		for _, dependent := range dependents {
+			if !gc.hasOwner(dependent) {
+				gc.deleteOrphan(dependent)
+			}
+		}
+		return
+	}
+	if !waitingForGC(current) {
+		return
+	}
+
+	dependents, err := gc.listDependents(ctx, kind, current.GetMetadata())
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	switch current.GetMetadata().Finalizers[0] {
+	case core.FinalizerForegroundDeletion:
+		// 存在其他所有者的依赖对象只移除指向当前所有者的引用，其余的依赖对象同样进行前台删除
+		pending := false
+		for _, dependent := range dependents {
+			if gc.hasOwner(dependent, current) {
+				if err := gc.removeOwnerReference(dependent, current); err != nil {
+					return
+				}
+				continue
+			}
+			pending = true
+			if dependent.GetStatusPhase() != core.PhaseDeleting {
+				gc.deleteDependent(dependent, core.PropagationPolicyForeground)
+			}
+		}
+		// 所有依赖对象删除完毕后才继续删除所有者
+		if pending {
+			return
+		}
+	case core.FinalizerOrphanDependents:
+		for _, dependent := range dependents {
+			if err := gc.removeOwnerReference(dependent, current); err != nil {
+				return
+			}
+		}
+	}
+	gc.removeFinalizer(r, current)
+}
+
+// sweep 定时清理所有者均已不存在的依赖对象，以及等待处理依赖对象的所有者，避免遗漏期间的变更
+func (gc *GarbageCollector) sweep(ctx context.Context) {
+	for _, r := range gc.registries {
+		objs, err := gc.helper.Informers.For(r).List(ctx, "")
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		for _, obj := range objs {
+			if waitingForGC(obj) {
+				gc.processOwner(ctx, obj)
+			} else if len(obj.GetMetadata().OwnerReferences) > 0 && obj.GetStatusPhase() != core.PhaseDeleting && !gc.hasOwner(obj) {
+				gc.deleteOrphan(obj)
+			}
+		}
+	}
+}
+
+// listDependents 通过所有者引用索引获取所有者引用指向该资源对象的所有依赖对象
+func (gc *GarbageCollector) listDependents(ctx context.Context, kind string, metadata core.Metadata) (core.ApiObjectList, error) {
+	dependents := core.ApiObjectList{}
+	for _, r := range gc.registries {
+		objs, err := gc.helper.Informers.For(r).ListByIndex(ctx, core.IndexOwnerReferences, core.IndexValue(kind, metadata.Namespace, metadata.Name))
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			for _, ref := range obj.GetMetadata().OwnerReferences {
+				if ref.Refers(kind, metadata) {
+					dependents = append(dependents, obj)
+					break
+				}
+			}
+		}
+	}
+	return dependents, nil
+}
+
+// getOwner 从缓存中获取所有者引用所指向的资源对象，不存在时返回空，资源类型未注册时返回错误
+func (gc *GarbageCollector) getOwner(ref core.OwnerReference) (core.ApiObject, error) {
+	r := registry.GetStorageRegistry(ref.Kind)
+	if r == nil {
+		return nil, e.Errorf("registry of %s not found", ref.Kind)
+	}
+	owner, err := gc.helper.Informers.For(r).Get(context.TODO(), ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil || !ref.Refers(ref.Kind, owner.GetMetadata()) {
+		return nil, nil
+	}
+	return owner, nil
+}
+
+// hasOwner 判断依赖对象是否存在未被删除的所有者，excludes中的所有者不计算在内。
+// 所有者无法获取时视为存在，避免误删
+func (gc *GarbageCollector) hasOwner(dependent core.ApiObject, excludes ...core.ApiObject) bool {
+	for _, ref := range dependent.GetMetadata().OwnerReferences {
+		excluded := false
+		for _, exclude := range excludes {
+			if ref.Refers(exclude.GetMetaType().Kind, exclude.GetMetadata()) {
+				excluded = true
+				break
+			}
+		}
+		if excluded {
+			continue
+		}
+		if owner, err := gc.getOwner(ref); err != nil || owner != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteDependent 按照级联删除策略删除依赖对象
+func (gc *GarbageCollector) deleteDependent(dependent core.ApiObject, policy string) {
+	r := registry.GetStorageRegistry(dependent.GetMetaType().Kind)
+	if r == nil {
+		return
+	}
+	metadata := dependent.GetMetadata()
+	log.Debugf("garbage collector delete %s", dependent.GetKey())
+	if _, err := r.Delete(context.TODO(), metadata.Namespace, metadata.Name, core.WithPropagationPolicy(policy)); err != nil {
+		log.Error(err)
+	}
+}
+
+// deleteOrphan 删除所有者均已不存在的依赖对象。各资源类型的缓存独立同步，同一事务中创建的所有者可能晚于依赖对象出现在缓存中，
+// 因此缓存只用于发现待删除的依赖对象，删除前从存储中重新获取依赖对象，并确认其所有者均已不存在
+func (gc *GarbageCollector) deleteOrphan(dependent core.ApiObject) {
+	r := registry.GetStorageRegistry(dependent.GetMetaType().Kind)
+	if r == nil {
+		return
+	}
+	metadata := dependent.GetMetadata()
+	current, err := r.Get(context.TODO(), metadata.Namespace, metadata.Name, core.WithoutDecorate())
+	if err != nil {
+		log.Error(err)
+		return
+	} else if current == nil || current.GetMetadata().Uid != metadata.Uid || current.GetStatusPhase() == core.PhaseDeleting {
+		return
+	}
+
+	refs := current.GetMetadata().OwnerReferences
+	if len(refs) == 0 {
+		return
+	}
+	for _, ref := range refs {
+		ownerRegistry := registry.GetStorageRegistry(ref.Kind)
+		if ownerRegistry == nil {
+			return
+		}
+		owner, err := ownerRegistry.Get(context.TODO(), ref.Namespace, ref.Name, core.WithoutDecorate())
+		if err != nil {
+			log.Error(err)
+			return
+		}
+		if owner != nil && ref.Refers(ref.Kind, owner.GetMetadata()) {
+			return
+		}
+	}
+	gc.deleteDependent(current, core.PropagationPolicyBackground)
+}
+
+// removeOwnerReference 从依赖对象中移除指向所有者的引用
+func (gc *GarbageCollector) removeOwnerReference(dependent core.ApiObject, owner core.ApiObject) error {
+	r := registry.GetStorageRegistry(dependent.GetMetaType().Kind)
+	if r == nil {
+		return nil
+	}
+	metadata := dependent.GetMetadata()
+	ownerRefs := []core.OwnerReference{}
+	for _, ref := range metadata.OwnerReferences {
+		if !ref.Refers(owner.GetMetaType().Kind, owner.GetMetadata()) {
+			ownerRefs = append(ownerRefs, ref)
+		}
+	}
+	metadata.OwnerReferences = ownerRefs
+	dependent.SetMetadata(metadata)
+	if _, err := r.Update(context.TODO(), dependent, core.WithAllFields()); err != nil {
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+// removeFinalizer 移除由垃圾回收器处理的finalizer，没有剩余的finalizer时删除所有者，否则交由对应的管理器继续清理
+func (gc *GarbageCollector) removeFinalizer(r registry.ApiObjectRegistry, owner core.ApiObject) {
+	metadata := owner.GetMetadata()
+	metadata.Finalizers = metadata.Finalizers[1:]
+	owner.SetMetadata(metadata)
+	if _, err := r.Update(context.TODO(), owner, core.WithFinalizer()); err != nil {
+		log.Error(err)
+		return
+	}
+	if len(metadata.Finalizers) == 0 {
+		if _, err := r.Delete(context.TODO(), metadata.Namespace, metadata.Name); err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+// waitingForGC 判断资源对象是否处于删除中状态，并等待垃圾回收器处理其依赖对象
+func waitingForGC(obj core.ApiObject) bool {
+	finalizers := obj.GetMetadata().Finalizers
+	if obj.GetStatusPhase() != core.PhaseDeleting || len(finalizers) == 0 {
+		return false
+	}
+	return finalizers[0] == core.FinalizerForegroundDeletion || finalizers[0] == core.FinalizerOrphanDependents
+}
+
+// NewGarbageCollector 创建垃圾回收器
+func NewGarbageCollector() *GarbageCollector {
+	return &GarbageCollector{
+		helper:            orm.GetHelper(),
+		ownerQueue:        make(chan core.ApiObject, 1000),
+		sweepPeriodSecond: 60,
+	}
+}
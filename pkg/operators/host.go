@@ -13,6 +13,7 @@ import (
 	"github.com/wujie1993/waves/pkg/db"
 	"github.com/wujie1993/waves/pkg/e"
 	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
 	"github.com/wujie1993/waves/pkg/orm/v1"
 	"github.com/wujie1993/waves/pkg/setting"
 	"github.com/wujie1993/waves/pkg/util"
@@ -85,6 +86,7 @@ func (c *HostOperator) handleHost(ctx context.Context, obj core.ApiObject) error
 				gpu.Metadata.Name = fmt.Sprintf("%s-slot-%d", host.Metadata.Name, slotIndex)
 				gpu.Spec.HostRef = host.Metadata.Name
 				gpu.Spec.Info = gpuInfo
+				gpu.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindHost, host.Metadata)}
 				if _, err := c.helper.V1.GPU.Create(context.TODO(), gpu); err != nil {
 					log.Error(err)
 					c.failback(host, core.EventActionConnect, err.Error(), nil)
@@ -203,10 +205,6 @@ func (c *HostOperator) handleHost(ctx context.Context, obj core.ApiObject) error
 		configMap.Metadata.Name = fmt.Sprintf("%s-%s-%d", ansible.ANSIBLE_ROLE_HOST_INIT, host.Metadata.Name, time.Now().Unix())
 		configMap.Data["common"] = commonInventoryStr
 		configMap.Data["inventory"] = inventoryBuf.String()
-		if _, err := c.helper.V1.ConfigMap.Create(context.TODO(), configMap); err != nil {
-			log.Error(err)
-			return err
-		}
 
 		// 生成playbook
 		playbook := ansible.Playbook{
@@ -222,6 +220,7 @@ func (c *HostOperator) handleHost(ctx context.Context, obj core.ApiObject) error
 		// 创建任务
 		job := v1.NewJob()
 		job.Metadata.Name = fmt.Sprintf("%s-%s-%d", ansible.ANSIBLE_ROLE_HOST_INIT, host.Metadata.Name, time.Now().Unix())
+		job.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindHost, host.Metadata)}
 		job.Spec.Exec.Type = core.JobExecTypeAnsible
 		job.Spec.Exec.Ansible.Bin = setting.AnsibleSetting.Bin
 		job.Spec.Exec.Ansible.Inventories = []v1.AnsibleInventory{
@@ -240,14 +239,19 @@ func (c *HostOperator) handleHost(ctx context.Context, obj core.ApiObject) error
 		job.Spec.Exec.Ansible.Playbook = playbookStr
 		job.Spec.TimeoutSeconds = 300
 		job.Spec.FailureThreshold = 3
-		if _, err := c.helper.V1.Job.Create(context.TODO(), job); err != nil {
-			log.Error(err)
-			return err
-		}
+
+		// 配置随任务一同回收，任务与配置在同一事务中创建，此时任务尚未分配uid
+		configMap.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindJob, job.Metadata)}
 
 		// 将主机与任务关联
 		host.Metadata.Annotations[core.AnnotationJobPrefix+ansible.ANSIBLE_ROLE_HOST_INIT] = job.Metadata.Name
-		if _, err := c.helper.V1.Host.Update(context.TODO(), host); err != nil {
+
+		// 在同一事务中创建配置与任务并关联主机，避免中途失败时遗留无主的配置或任务
+		if _, err := registry.Commit(context.TODO(),
+			c.helper.V1.ConfigMap.BatchCreate(configMap),
+			c.helper.V1.Job.BatchCreate(job),
+			c.helper.V1.Host.BatchUpdate(host),
+		); err != nil {
 			log.Error(err)
 			return err
 		}
@@ -268,48 +272,6 @@ func (c *HostOperator) handleHost(ctx context.Context, obj core.ApiObject) error
 	return nil
 }
 
-// finalizeHost 级联清除主机的关联资源
-func (o HostOperator) finalizeHost(ctx context.Context, obj core.ApiObject) error {
-	host := obj.(*v1.Host)
-
-	// 每次只处理一项Finalizer
-	switch host.Metadata.Finalizers[0] {
-	case core.FinalizerCleanRefGPU:
-		// 同步删除关联的GPU
-		gpuList, err := o.helper.V1.GPU.List(context.TODO(), "")
-		if err != nil {
-			log.Error(err)
-			return err
-		}
-		for _, gpuObj := range gpuList {
-			gpu := gpuObj.(*v1.GPU)
-			if gpu.Spec.HostRef == host.Metadata.Name {
-				if _, err := o.helper.V1.GPU.Delete(context.TODO(), "", gpu.Metadata.Name, core.WithSync()); err != nil {
-					log.Error(err)
-					return err
-				}
-			}
-		}
-	case core.FinalizerCleanRefEvent:
-		// 同步删除关联的事件
-		eventList, err := o.helper.V1.Event.List(context.TODO(), "")
-		if err != nil {
-			log.Error(err)
-			return err
-		}
-		for _, eventObj := range eventList {
-			event := eventObj.(*v1.Event)
-			if event.Spec.ResourceRef.Kind == core.KindHost && event.Spec.ResourceRef.Name == host.Metadata.Name {
-				if _, err := o.helper.V1.Event.Delete(context.TODO(), "", event.Metadata.Name); err != nil {
-					log.Error(err)
-					return err
-				}
-			}
-		}
-	}
-	return nil
-}
-
 // reconcileHost 主机定时收敛
 func (o *HostOperator) reconcileHost(ctx context.Context, obj core.ApiObject) {
 	host := obj.(*v1.Host)
@@ -333,19 +295,17 @@ func (o *HostOperator) reconcileHost(ctx context.Context, obj core.ApiObject) {
 	}
 
 	// 重置GPU记录
-	gpuObjs, err := o.helper.V1.GPU.List(ctx, "")
+	gpuObjs, err := o.helper.V1.GPU.ListByIndex(ctx, core.IndexHostRef, host.Metadata.Name)
 	if err != nil {
 		log.Error(err)
 		return
 	}
 	for _, gpuObj := range gpuObjs {
 		gpu := gpuObj.(*v1.GPU)
-		if gpu.Spec.HostRef == host.Metadata.Name {
-			gpu.Spec.Info = v1.GPUInfo{}
-			if _, err := o.helper.V1.GPU.Update(context.TODO(), gpu, core.WithAllFields()); err != nil {
-				log.Error(err)
-				return
-			}
+		gpu.Spec.Info = v1.GPUInfo{}
+		if _, err := o.helper.V1.GPU.Update(context.TODO(), gpu, core.WithAllFields()); err != nil {
+			log.Error(err)
+			return
 		}
 	}
 
@@ -370,6 +330,7 @@ func (o *HostOperator) reconcileHost(ctx context.Context, obj core.ApiObject) {
 			gpu.Metadata.Name = o.helper.V1.GPU.GetGPUName(host.Metadata.Name, slotIndex)
 			gpu.Spec.HostRef = host.Metadata.Name
 			gpu.Spec.Info = gpuInfo
+			gpu.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindHost, host.Metadata)}
 			if _, err := o.helper.V1.GPU.Create(context.TODO(), gpu); err != nil {
 				log.Error(err)
 				return
@@ -433,7 +394,6 @@ func NewHostOperator() *HostOperator {
 		BaseOperator: NewBaseOperator(v1.NewHostRegistry()),
 	}
 	o.SetHandleFunc(o.handleHost)
-	o.SetFinalizeFunc(o.finalizeHost)
 	o.SetReconcileFunc(o.reconcileHost, 30)
 	return o
 }
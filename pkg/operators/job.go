@@ -35,25 +35,6 @@ func (o JobOperator) finalizeJob(ctx context.Context, obj core.ApiObject) error
 
 	// 每次只处理一项Finalizer
 	switch job.Metadata.Finalizers[0] {
-	case core.FinalizerCleanRefConfigMap:
-		for _, play := range job.Spec.Exec.Ansible.Plays {
-			// 删除关联的inventory
-			if play.Inventory.ValueFrom.ConfigMapRef.Name != "" {
-				if _, err := o.helper.V1.ConfigMap.Delete(context.TODO(), play.Inventory.ValueFrom.ConfigMapRef.Namespace, play.Inventory.ValueFrom.ConfigMapRef.Name); err != nil {
-					log.Error(err)
-					return err
-				}
-			}
-			// 删除关联的group_vars
-			if play.GroupVars.ValueFrom.ConfigMapRef.Name != "" {
-				if _, err := o.helper.V1.ConfigMap.Delete(context.TODO(),
-					play.GroupVars.ValueFrom.ConfigMapRef.Namespace,
-					play.GroupVars.ValueFrom.ConfigMapRef.Name); err != nil {
-					log.Error(err)
-					return err
-				}
-			}
-		}
 	case core.FinalizerCleanJobWorkDir:
 		if err := os.RemoveAll(path.Join(setting.AppSetting.DataDir, setting.JobsDir, job.GetMetadata().Uid)); err != nil {
 			log.Error(err)
@@ -184,6 +184,7 @@ func (c *K8sInstallOperator) handleK8s(ctx context.Context, obj core.ApiObject)
 			job := v1.NewJob()
 			job.Metadata.Namespace = "default"
 			job.Metadata.Name = fmt.Sprintf("%s-%s-%d", "k8sinstall", k8s.Metadata.Name, time.Now().Unix())
+			job.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindK8sConfig, k8s.Metadata)}
 			job.Spec.Exec.Type = core.JobExecTypeAnsible
 			job.Spec.Exec.Ansible.Bin = "/usr/bin/ansible-playbook"
 			job.Spec.Exec.Ansible.Inventories = []v1.AnsibleInventory{
@@ -312,31 +313,6 @@ func (c *K8sInstallOperator) handleK8s(ctx context.Context, obj core.ApiObject)
 	return nil
 }
 
-func (o K8sInstallOperator) finalizeK8s(ctx context.Context, obj core.ApiObject) error {
-	k8s := obj.(*v1.K8sConfig)
-
-	// 每次只处理一项Finalizer
-	switch k8s.Metadata.Finalizers[0] {
-	case core.FinalizerCleanRefEvent:
-		// 同步删除关联的事件
-		eventList, err := o.helper.V1.Event.List(context.TODO(), "")
-		if err != nil {
-			log.Error(err)
-			return err
-		}
-		for _, eventObj := range eventList {
-			event := eventObj.(*v1.Event)
-			if event.Spec.ResourceRef.Kind == core.KindK8sConfig && event.Spec.ResourceRef.Namespace == k8s.Metadata.Namespace && event.Spec.ResourceRef.Name == k8s.Metadata.Name {
-				if _, err := o.helper.V1.Event.Delete(context.TODO(), "", event.Metadata.Name); err != nil {
-					log.Error(err)
-					return err
-				}
-			}
-		}
-	}
-	return nil
-}
-
 //卸载k8s 集群
 func (c *K8sInstallOperator) deleteK8s(ctx context.Context, k8s *v1.K8sConfig, inventoryBuf bytes.Buffer) {
 	log.Debug("卸载k8s", k8s.Spec.K8SWorkerNew.Hosts)
@@ -375,6 +351,7 @@ func (c *K8sInstallOperator) deleteK8s(ctx context.Context, k8s *v1.K8sConfig, i
 	job := v1.NewJob()
 	job.Metadata.Namespace = "default"
 	job.Metadata.Name = fmt.Sprintf("%s-%s-%d", "k8s", "uninstall", time.Now().Unix())
+	job.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindK8sConfig, k8s.Metadata)}
 	job.Spec.Exec.Type = core.JobExecTypeAnsible
 	job.Spec.Exec.Ansible.Bin = "/usr/bin/ansible-playbook"
 	job.Spec.Exec.Ansible.Inventories = []v1.AnsibleInventory{
@@ -588,6 +565,7 @@ func (c *K8sInstallOperator) handleK8sLabel(ctx context.Context, k8s *v1.K8sConf
 	job := v1.NewJob()
 	job.Metadata.Namespace = "default"
 	job.Metadata.Name = fmt.Sprintf("%s-%s-%d", "k8slabel", k8s.Metadata.Name, time.Now().Unix())
+	job.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(core.KindK8sConfig, k8s.Metadata)}
 	job.Spec.Exec.Type = core.JobExecTypeAnsible
 	job.Spec.Exec.Ansible.Bin = "/usr/bin/ansible-playbook"
 	job.Spec.Exec.Ansible.Inventories = []v1.AnsibleInventory{
@@ -687,6 +665,5 @@ func NewK8sInstallOperator() *K8sInstallOperator {
 		BaseOperator: NewBaseOperator(v1.NewK8sConfigRegistry()),
 	}
 	o.SetHandleFunc(o.handleK8s)
-	o.SetFinalizeFunc(o.finalizeK8s)
 	return o
 }
@@ -72,6 +72,7 @@ type ReconcileFunc func(ctx context.Context, obj core.ApiObject)
 type BaseOperator struct {
 	helper                *orm.Helper
 	registry              registry.ApiObjectRegistry
+	informer              *registry.Informer
 	handle                HandleFunc
 	reconcile             ReconcileFunc
 	finalize              HandleFunc
@@ -114,7 +115,7 @@ func (o BaseOperator) runReconcile(ctx context.Context, wg *sync.WaitGroup) {
 			log.Debugf("%+v reconcile stopped", o.registry.GVK())
 			return
 		default:
-			objs, err := o.registry.List(context.TODO(), "")
+			objs, err := o.informer.List(context.TODO(), "")
 			if err != nil {
 				log.Error(err)
 			}
@@ -127,7 +128,7 @@ func (o BaseOperator) runReconcile(ctx context.Context, wg *sync.WaitGroup) {
 	}
 }
 
-// runHandle 开启资源变更的监听，当资源发生创建，更新或删除时会触发自定义的handle处理逻辑
+// runHandle 通过共享缓存接收资源变更，当资源发生创建，更新或删除时会触发自定义的handle处理逻辑
 func (o BaseOperator) runHandle(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 
@@ -135,56 +136,50 @@ func (o BaseOperator) runHandle(ctx context.Context, wg *sync.WaitGroup) {
 		return
 	}
 
-	watchCtx, _ := context.WithCancel(ctx)
-	watcher := o.registry.ListWatch(watchCtx, "")
+	o.informer.AddEventHandler(registry.ResourceEventHandler{
+		OnSet: func(obj core.ApiObject) {
+			if ctx.Err() == nil {
+				go o.handleObj(ctx, obj)
+			}
+		},
+		OnDelete: func(obj core.ApiObject) {
+			// 资源未经过finalizer清理即被删除（如存活时间到期），补充执行剩余的清理
+			if ctx.Err() == nil && len(obj.GetMetadata().Finalizers) > 0 {
+				go o.finalizeExpired(ctx, obj)
+			}
+		},
+	})
 
 	for {
 		select {
 		case <-ctx.Done():
 			log.Debugf("%+v handle stopped", o.registry.GVK())
 			return
-		case objAction, ok := <-watcher:
-			if !ok {
-				log.Warnf("%+v action watcher closed", o.registry.GVK())
-				return
-			}
-			if objAction.Type == db.KVActionTypeSet && objAction.Obj != nil {
-				handleCtx, _ := context.WithCancel(ctx)
-				go func() {
-					defer func() {
-						e := recover()
-						if err, ok := e.(error); ok {
-							log.Error(err)
-						} else if e != nil {
-							log.Error(e)
-						}
-					}()
-					o.handle(handleCtx, objAction.Obj)
-				}()
-			}
 		case obj, ok := <-o.objQueue:
 			if !ok {
 				log.Errorf("%+v action queue closed", o.registry.GVK())
 				return
 			}
 			if obj != nil {
-				handleCtx, _ := context.WithCancel(ctx)
-				go func() {
-					defer func() {
-						e := recover()
-						if err, ok := e.(error); ok {
-							log.Error(err)
-						} else {
-							log.Error(e)
-						}
-					}()
-					o.handle(handleCtx, obj)
-				}()
+				go o.handleObj(ctx, obj)
 			}
 		}
 	}
 }
 
+// handleObj 调用自定义的handle处理逻辑，并恢复处理过程中的panic
+func (o *BaseOperator) handleObj(ctx context.Context, obj core.ApiObject) {
+	defer func() {
+		e := recover()
+		if err, ok := e.(error); ok {
+			log.Error(err)
+		} else if e != nil {
+			log.Error(e)
+		}
+	}()
+	o.handle(ctx, obj)
+}
+
 // SetHandleFunc 设置自定义资源变更处理防范
 func (o *BaseOperator) SetHandleFunc(f HandleFunc) {
 	o.handle = f
@@ -222,13 +217,20 @@ func (o BaseOperator) delete(ctx context.Context, obj core.ApiObject) error {
 
 	metadata := obj.GetMetadata()
 
-	if len(metadata.Finalizers) > 0 && o.finalize != nil {
+	// 由垃圾回收器处理的finalizer，等待其处理完依赖对象后再继续清理
+	if waitingForGC(obj) {
+		return nil
+	}
+
+	if len(metadata.Finalizers) > 0 {
 		log.Tracef("finalizing %s of %s", metadata.Finalizers[0], obj.GetKey())
 
-		// 每次只处理一项Finalizer
-		if err := o.finalize(ctx, obj); err != nil {
-			log.Error(err)
-			return err
+		// 每次只处理一项Finalizer，没有对应清理方法的finalizer直接移除
+		if o.finalize != nil {
+			if err := o.finalize(ctx, obj); err != nil {
+				log.Error(err)
+				return err
+			}
 		}
 
 		metadata.Finalizers = metadata.Finalizers[1:]
@@ -237,7 +239,7 @@ func (o BaseOperator) delete(ctx context.Context, obj core.ApiObject) error {
 			log.Error(err)
 			return err
 		}
-	} else if len(metadata.Finalizers) == 0 {
+	} else {
 		if _, err := o.registry.Delete(context.TODO(), metadata.Namespace, metadata.Name); err != nil {
 			log.Error(err)
 			return err
@@ -247,6 +249,23 @@ func (o BaseOperator) delete(ctx context.Context, obj core.ApiObject) error {
 	return nil
 }
 
+// finalizeExpired 对已被删除但仍有finalizer未处理的资源依次执行剩余的清理，清理失败时仅记录错误
+func (o *BaseOperator) finalizeExpired(ctx context.Context, obj core.ApiObject) {
+	if o.finalize == nil {
+		return
+	}
+
+	metadata := obj.GetMetadata()
+	log.Debugf("finalizing expired %s", obj.GetKey())
+	for len(metadata.Finalizers) > 0 {
+		obj.SetMetadata(metadata)
+		if err := o.finalize(ctx, obj); err != nil {
+			log.Error(err)
+		}
+		metadata.Finalizers = metadata.Finalizers[1:]
+	}
+}
+
 // getLockKey 获取分布式锁键名
 func (o BaseOperator) getLockKey() string {
 	return core.RegistryPrefix + "/locks/" + o.registry.GVK().Kind
@@ -263,14 +282,17 @@ func (o BaseOperator) recordEvent(event Event) error {
 	e.Spec.Msg = event.Msg
 	e.Spec.JobRef = event.JobRef
 	e.Status.Phase = event.Phase
+	e.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(event.Kind, event.Metadata)}
 	return o.helper.V1.Event.Record(e)
 }
 
 // NewBaseOperator 创建基础管理器
 func NewBaseOperator(r registry.ApiObjectRegistry) BaseOperator {
+	helper := orm.GetHelper()
 	return BaseOperator{
 		registry:  r,
-		helper:    orm.GetHelper(),
+		informer:  helper.Informers.For(r),
+		helper:    helper,
 		objQueue:  make(chan core.ApiObject, 1000),
 		applyings: NewMutexMap(),
 		deletings: NewMutexMap(),
@@ -69,6 +69,11 @@ type SpecHasher interface {
 	SpecHash() string
 }
 
+// SpecResetter 清空Spec字段的内容，用于以替换而非合并的方式更新Spec
+type SpecResetter interface {
+	SpecReset()
+}
+
 type Versioner interface {
 	RaiseVersion()
 }
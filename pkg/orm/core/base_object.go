@@ -12,6 +12,8 @@ type BaseApiObj struct {
 type ApiObjectAction struct {
 	Type string
 	Obj  ApiObject
+	// 变更对应的存储修订版本号
+	Revision int64
 }
 
 type BaseRuntimeObj struct {
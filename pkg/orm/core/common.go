@@ -3,6 +3,7 @@ package core
 import (
 	"encoding/json"
 	"errors"
+	"strings"
 )
 
 const (
@@ -10,6 +11,7 @@ const (
 	AnnotationJobPrefix                = AnnotationPrefix + "job/"
 	AnnotationAlgorithmPluginPrefix    = AnnotationPrefix + "algorithm-plugin/"
 	AnnotationLastAppliedConfiguration = AnnotationPrefix + "last-applied-configuration"
+	AnnotationTTL                      = AnnotationPrefix + "ttl"
 
 	Group        = "core"
 	ApiVersionV1 = "v1"
@@ -19,6 +21,21 @@ const (
 	AppCategoryHostPlugin      = "hostPlugin"
 	AppCategoryAlgorithmPlugin = "algorithmPlugin"
 
+	ArgTypeInteger = "integer"
+	ArgTypeNumber  = "number"
+	ArgTypeString  = "string"
+	ArgTypeBoolean = "boolean"
+
+	ArgFormatInt32     = "int32"
+	ArgFormatInt64     = "int64"
+	ArgFormatPort      = "port"
+	ArgFormatFloat     = "float"
+	ArgFormatDouble    = "double"
+	ArgFormatDate      = "date"
+	ArgFormatPassword  = "password"
+	ArgFormatArray     = "array"
+	ArgFormatGroupHost = "groupHost"
+
 	AppPlatformBareMetal = "bareMetal"
 	AppPlatformK8s       = "k8s"
 
@@ -30,6 +47,29 @@ const (
 	AppActionUpgrade     = "upgrade"
 	AppActionRevert      = "revert"
 
+	AdmissionOperationCreate = "create"
+	AdmissionOperationUpdate = "update"
+	AdmissionOperationDelete = "delete"
+
+	AdmissionFailurePolicyFail   = "Fail"
+	AdmissionFailurePolicyIgnore = "Ignore"
+
+	AdmissionDefaultTimeoutSeconds = 10
+	AdmissionMaxTimeoutSeconds     = 30
+
+	// 角色授权的操作，VerbAction为修改应用实例的Action以触发安装、卸载等动作
+	VerbGet    = "get"
+	VerbList   = "list"
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbDelete = "delete"
+	VerbAction = "action"
+
+	// 角色绑定的主体类型
+	SubjectKindUser           = "User"
+	SubjectKindGroup          = "Group"
+	SubjectKindServiceAccount = "ServiceAccount"
+
 	AuditActionCreate = "create"
 	AuditActionUpdate = "update"
 	AuditActionDelete = "delete"
@@ -40,20 +80,25 @@ const (
 
 	RegistryPrefix = "/prophet"
 
-	KindApp         = "app"
-	KindAppInstance = "appInstance"
-	KindAudit       = "audit"
-	KindEvent       = "event"
-	KindHost        = "host"
-	KindJob         = "job"
-	KindConfigMap   = "configMap"
-	KindK8sConfig   = "k8sconfig"
-	KindK8sLabel    = "k8slabel"
-	KindNamespace   = "namespace"
-	KindPkg         = "pkg"
-	KindGPU         = "gpu"
-	KindProject     = "project"
-	KindRevision    = "revision"
+	KindAdmissionConfig = "admissionConfig"
+	KindApp             = "app"
+	KindAppInstance     = "appInstance"
+	KindAudit           = "audit"
+	KindEvent           = "event"
+	KindHost            = "host"
+	KindJob             = "job"
+	KindConfigMap       = "configMap"
+	KindK8sConfig       = "k8sconfig"
+	KindK8sLabel        = "k8slabel"
+	KindNamespace       = "namespace"
+	KindPkg             = "pkg"
+	KindGPU             = "gpu"
+	KindProject         = "project"
+	KindRevision        = "revision"
+	KindRole            = "role"
+	KindRoleBinding     = "roleBinding"
+	KindServiceAccount  = "serviceAccount"
+	KindUser            = "user"
 
 	ConditionTypeConnected   = "Connected"
 	ConditionTypeInitialized = "Initialized"
@@ -81,12 +126,13 @@ const (
 
 	FinalizerCleanRefJob       = "CleanRefJob"
 	FinalizerCleanJobWorkDir   = "CleanJobWorkDir"
-	FinalizerCleanRefGPU       = "CleanRefGPU"
 	FinalizerReleaseRefGPU     = "ReleaseRefGPU"
-	FinalizerCleanRefEvent     = "CleanRefEvent"
 	FinalizerCleanRefConfigMap = "CleanRefConfigMap"
 	FinalizerCleanRevision     = "CleanRevision"
 	FinalizerCleanHostPlugin   = "CleanHostPlugin"
+	// 以下finalizer由垃圾回收器处理，用于前台删除与保留依赖对象
+	FinalizerForegroundDeletion = "ForegroundDeletion"
+	FinalizerOrphanDependents   = "OrphanDependents"
 
 	JobExecTypeAnsible         = "ansible"
 	JobDefaultFailureThreshold = 1
@@ -116,6 +162,17 @@ const (
 	PhaseInCompleted   = "InstallCompleted"
 	PhaseUnCompleted   = "UninstallCompleted"
 
+	PropagationPolicyForeground = "Foreground"
+	PropagationPolicyBackground = "Background"
+	PropagationPolicyOrphan     = "Orphan"
+
+	// 存储器索引名称，索引取值通过IndexValue生成
+	IndexOwnerReferences = "OwnerReferences"
+	IndexResourceRef     = "ResourceRef"
+	IndexHostRef         = "HostRef"
+	IndexAppInstanceRef  = "AppInstanceRef"
+	IndexSubject         = "Subject"
+
 	PkgProvisionFull = "full"
 	PkgProvisionThin = "thin"
 
@@ -125,6 +182,7 @@ const (
 var (
 	// 已注册的资源类型列表
 	kinds = []string{
+		KindAdmissionConfig,
 		KindApp,
 		KindAppInstance,
 		KindAudit,
@@ -136,58 +194,79 @@ var (
 		KindK8sConfig,
 		KindPkg,
 		KindProject,
+		KindRole,
+		KindRoleBinding,
+		KindServiceAccount,
+		KindUser,
 	}
 
 	// 资源复数别名，用于接口url，如：api/<version>/<plural>
 	kindPluralMap = map[string]string{
-		KindApp:         "apps",
-		KindAppInstance: "appinstances",
-		KindAudit:       "audits",
-		KindConfigMap:   "configmaps",
-		KindEvent:       "events",
-		KindGPU:         "gpus",
-		KindHost:        "hosts",
-		KindJob:         "jobs",
-		KindK8sConfig:   "k8sconfig",
-		KindPkg:         "pkgs",
-		KindProject:     "projects",
+		KindAdmissionConfig: "admissionconfigs",
+		KindApp:             "apps",
+		KindAppInstance:     "appinstances",
+		KindAudit:           "audits",
+		KindConfigMap:       "configmaps",
+		KindEvent:           "events",
+		KindGPU:             "gpus",
+		KindHost:            "hosts",
+		KindJob:             "jobs",
+		KindK8sConfig:       "k8sconfig",
+		KindPkg:             "pkgs",
+		KindProject:         "projects",
+		KindRole:            "roles",
+		KindRoleBinding:     "rolebindings",
+		KindServiceAccount:  "serviceaccounts",
+		KindUser:            "users",
 	}
 
 	// 资源单数名称
 	kindSingularMap = map[string]string{
-		KindApp:         "app",
-		KindAppInstance: "appinstance",
-		KindAudit:       "audit",
-		KindConfigMap:   "configmap",
-		KindEvent:       "event",
-		KindGPU:         "gpu",
-		KindHost:        "host",
-		KindJob:         "job",
-		KindK8sConfig:   "k8sconfig",
-		KindPkg:         "pkg",
-		KindProject:     "project",
+		KindAdmissionConfig: "admissionconfig",
+		KindApp:             "app",
+		KindAppInstance:     "appinstance",
+		KindAudit:           "audit",
+		KindConfigMap:       "configmap",
+		KindEvent:           "event",
+		KindGPU:             "gpu",
+		KindHost:            "host",
+		KindJob:             "job",
+		KindK8sConfig:       "k8sconfig",
+		KindPkg:             "pkg",
+		KindProject:         "project",
+		KindRole:            "role",
+		KindRoleBinding:     "rolebinding",
+		KindServiceAccount:  "serviceaccount",
+		KindUser:            "user",
 	}
 
 	// 资源简称，便于命令行使用资源
 	kindShortNamesMap = map[string][]string{
-		KindAppInstance: {"ins"},
-		KindConfigMap:   {"cm"},
-		KindHost:        {"node", "nodes"},
-		KindK8sConfig:   {"k8s"},
+		KindAdmissionConfig: {"admission"},
+		KindAppInstance:     {"ins"},
+		KindConfigMap:       {"cm"},
+		KindHost:            {"node", "nodes"},
+		KindK8sConfig:       {"k8s"},
+		KindServiceAccount:  {"sa"},
 	}
 
 	// 资源类型描述
 	kindMsg = map[string]string{
-		KindApp:         "应用",
-		KindAppInstance: "应用实例",
-		KindAudit:       "审计",
-		KindConfigMap:   "配置",
-		KindProject:     "项目名称",
-		KindHost:        "主机",
-		KindJob:         "任务",
-		KindK8sConfig:   "K8s集群",
-		KindPkg:         "部署包",
-		KindGPU:         "显卡",
+		KindAdmissionConfig: "准入控制配置",
+		KindApp:             "应用",
+		KindAppInstance:     "应用实例",
+		KindAudit:           "审计",
+		KindConfigMap:       "配置",
+		KindProject:         "项目名称",
+		KindHost:            "主机",
+		KindJob:             "任务",
+		KindK8sConfig:       "K8s集群",
+		KindPkg:             "部署包",
+		KindGPU:             "显卡",
+		KindRole:            "角色",
+		KindRoleBinding:     "角色绑定",
+		KindServiceAccount:  "服务账号",
+		KindUser:            "用户",
 	}
 
 	// 操作行为描述
@@ -236,6 +315,11 @@ func DeepCopy(src, dest interface{}) error {
 	return json.Unmarshal(bytes, dest)
 }
 
+// IndexValue 将多个字段组合为索引取值
+func IndexValue(fields ...string) string {
+	return strings.Join(fields, "/")
+}
+
 // GetKindMsg 根据资源类型获取其简称，在资源类型不存在的情况下返回类型描述
 func GetKindMsg(kind string) string {
 	msg, ok := kindMsg[kind]
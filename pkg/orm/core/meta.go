@@ -14,6 +14,32 @@ type Metadata struct {
 	CreateTime      time.Time
 	UpdateTime      time.Time
 	Finalizers      []string
+	// 所有者引用，所有的所有者均被删除后，由垃圾回收器级联删除该对象
+	OwnerReferences []OwnerReference `json:",omitempty" yaml:",omitempty"`
+}
+
+// OwnerReference 指向所有者资源对象的引用
+type OwnerReference struct {
+	Kind      string
+	Namespace string `json:",omitempty" yaml:",omitempty"`
+	Name      string
+	// 所有者的唯一标识，为空时只按照类型、命名空间与名称匹配所有者
+	Uid string `json:",omitempty" yaml:",omitempty"`
+}
+
+// NewOwnerReference 根据所有者的类型与元数据生成所有者引用
+func NewOwnerReference(kind string, metadata Metadata) OwnerReference {
+	return OwnerReference{
+		Kind:      kind,
+		Namespace: metadata.Namespace,
+		Name:      metadata.Name,
+		Uid:       metadata.Uid,
+	}
+}
+
+// Refers 判断所有者引用是否指向该类型与元数据所对应的资源对象
+func (ref OwnerReference) Refers(kind string, metadata Metadata) bool {
+	return ref.Kind == kind && ref.Namespace == metadata.Namespace && ref.Name == metadata.Name && (ref.Uid == "" || ref.Uid == metadata.Uid)
 }
 
 type MetaType struct {
@@ -27,6 +53,17 @@ func (m *Metadata) Init() {
 	m.Finalizers = []string{}
 }
 
+// AddOwnerReference 添加所有者引用，已存在指向相同所有者的引用时进行替换
+func (m *Metadata) AddOwnerReference(ref OwnerReference) {
+	for index, ownerRef := range m.OwnerReferences {
+		if ownerRef.Kind == ref.Kind && ownerRef.Namespace == ref.Namespace && ownerRef.Name == ref.Name {
+			m.OwnerReferences[index] = ref
+			return
+		}
+	}
+	m.OwnerReferences = append(m.OwnerReferences, ref)
+}
+
 func (m Metadata) CopyTo(dest *Metadata) {
 	DeepCopy(&m, &dest)
 }
@@ -7,6 +7,24 @@ type Option struct {
 	WithSync        bool
 	WithAllFields   bool
 	WhenSpecChanged bool
+	// 分页获取时每页的最大记录数，为0时不分页
+	Limit int64
+	// 分页获取时上一页返回的令牌，为空时从第一页开始获取
+	Continue string
+	// 列举时的标签选择器，为空时不过滤
+	LabelSelector string
+	// 列举时的字段选择器，为空时不过滤
+	FieldSelector string
+	// 删除时依赖对象的级联删除策略，为空时使用PropagationPolicyBackground
+	PropagationPolicy string
+	// 仅执行写入前的校验、填充与前置钩子并返回将要写入的对象，不写入数据库，不执行后置钩子，不生成修订版本
+	DryRun bool
+	// 更新状态时要求资源当前的版本号与之一致，为0时不校验
+	ResourceVersion int
+	// 获取时不执行装饰钩子，返回存储中的原始内容
+	WithoutDecorate bool
+	// 更新时使用新的Spec替换原有的Spec，未启用时新的Spec会合并至原有的Spec中
+	ReplaceSpec bool
 }
 
 func (o *Option) SetupOption(opts ...OpOpt) {
@@ -38,3 +56,57 @@ func WhenSpecChanged() OpOpt {
 		o.WhenSpecChanged = true
 	}
 }
+
+func WithLimit(limit int64) OpOpt {
+	return func(o *Option) {
+		o.Limit = limit
+	}
+}
+
+func WithContinue(token string) OpOpt {
+	return func(o *Option) {
+		o.Continue = token
+	}
+}
+
+func WithLabelSelector(selector string) OpOpt {
+	return func(o *Option) {
+		o.LabelSelector = selector
+	}
+}
+
+func WithFieldSelector(selector string) OpOpt {
+	return func(o *Option) {
+		o.FieldSelector = selector
+	}
+}
+
+func WithPropagationPolicy(policy string) OpOpt {
+	return func(o *Option) {
+		o.PropagationPolicy = policy
+	}
+}
+
+func WithDryRun() OpOpt {
+	return func(o *Option) {
+		o.DryRun = true
+	}
+}
+
+func WithResourceVersion(resourceVersion int) OpOpt {
+	return func(o *Option) {
+		o.ResourceVersion = resourceVersion
+	}
+}
+
+func WithoutDecorate() OpOpt {
+	return func(o *Option) {
+		o.WithoutDecorate = true
+	}
+}
+
+func WithReplaceSpec() OpOpt {
+	return func(o *Option) {
+		o.ReplaceSpec = true
+	}
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"strings"
+
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+// 字段投影时总是保留的字段，保证投影后的对象仍可被识别
+var projectionRequiredFields = [][]string{
+	{"ApiVersion"},
+	{"Kind"},
+	{"Metadata", "Namespace"},
+	{"Metadata", "Name"},
+}
+
+// ParseFields 解析以逗号分隔的字段路径列表，如metadata.createTime,spec.replicas
+func ParseFields(fields string) ([][]string, error) {
+	paths := [][]string{}
+	if fields == "" {
+		return paths, nil
+	}
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		path := strings.Split(field, ".")
+		for _, name := range path {
+			if name == "" {
+				return nil, e.InvalidFieldError{Field: "fields", Reason: "字段路径格式错误: " + field}
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// ProjectFields 只保留资源对象中指定路径的字段，字段名忽略大小写，不存在的字段被忽略
+func ProjectFields(obj ApiObject, paths [][]string) (map[string]interface{}, error) {
+	content, err := decodeContent(obj)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]interface{})
+	for _, path := range append(projectionRequiredFields, paths...) {
+		copyField(content, result, path)
+	}
+	return result, nil
+}
+
+// copyField 将源对象中路径所指向的字段复制到目标对象中
+func copyField(src interface{}, dst map[string]interface{}, path []string) {
+	fields, ok := src.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, child := range fields {
+		if !strings.EqualFold(key, path[0]) {
+			continue
+		}
+		if len(path) == 1 {
+			dst[key] = child
+			return
+		}
+		next, ok := dst[key].(map[string]interface{})
+		if !ok {
+			if _, exists := dst[key]; exists {
+				// 父字段已被完整保留
+				return
+			}
+			next = make(map[string]interface{})
+		}
+		copyField(child, next, path[1:])
+		if len(next) > 0 {
+			dst[key] = next
+		}
+		return
+	}
+}
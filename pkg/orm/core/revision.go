@@ -0,0 +1,53 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/wujie1993/waves/pkg/patch"
+)
+
+// RevisionDiff 资源两个修订版本之间Spec的差异
+type RevisionDiff struct {
+	// 比较的起始修订版本与目标修订版本
+	From int
+	To   int
+	// 将起始修订版本的Spec变更为目标修订版本的Spec的JSON补丁，路径相对于Spec
+	Patch []patch.Operation
+	// Spec的YAML文本按行比较的统一格式差异，无差异时为空
+	Diff string
+}
+
+// NewRevisionDiff 比较资源两个修订版本的Spec
+func NewRevisionDiff(from ApiObject, to ApiObject) (*RevisionDiff, error) {
+	fromSpec, err := from.SpecEncode()
+	if err != nil {
+		return nil, err
+	}
+	toSpec, err := to.SpecEncode()
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := patch.CreateJSONPatch(fromSpec, toSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	fromYAML, err := yaml.JSONToYAML(fromSpec)
+	if err != nil {
+		return nil, err
+	}
+	toYAML, err := yaml.JSONToYAML(toSpec)
+	if err != nil {
+		return nil, err
+	}
+	diff := &RevisionDiff{
+		From:  from.GetMetadata().ResourceVersion,
+		To:    to.GetMetadata().ResourceVersion,
+		Patch: ops,
+	}
+	diff.Diff = patch.UnifiedDiff(fmt.Sprintf("revision %d", diff.From), fmt.Sprintf("revision %d", diff.To), string(fromYAML), string(toYAML))
+	return diff, nil
+}
@@ -0,0 +1,248 @@
+package core
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+const (
+	SelectorOpEquals       = "="
+	SelectorOpNotEquals    = "!="
+	SelectorOpIn           = "in"
+	SelectorOpNotIn        = "notin"
+	SelectorOpExists       = "exists"
+	SelectorOpDoesNotExist = "!"
+)
+
+var (
+	selectorKeyRegex   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]*[A-Za-z0-9])?$`)
+	selectorValueRegex = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9_./:]*[A-Za-z0-9])?)?$`)
+	selectorSetRegex   = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+// Requirement 选择器中的单个条件
+type Requirement struct {
+	Key      string
+	Operator string
+	Values   []string
+}
+
+// Matches 判断条件是否成立，get返回键对应的值以及键是否存在
+func (r Requirement) Matches(get func(key string) (string, bool)) bool {
+	value, ok := get(r.Key)
+	switch r.Operator {
+	case SelectorOpEquals:
+		return ok && value == r.Values[0]
+	case SelectorOpNotEquals:
+		return !ok || value != r.Values[0]
+	case SelectorOpIn:
+		return ok && containsString(r.Values, value)
+	case SelectorOpNotIn:
+		return !ok || !containsString(r.Values, value)
+	case SelectorOpExists:
+		return ok
+	case SelectorOpDoesNotExist:
+		return !ok
+	}
+	return false
+}
+
+// Selector 由逗号分隔的多个条件组成的选择器，所有条件均成立时匹配，空选择器匹配所有对象
+type Selector []Requirement
+
+// Empty 判断选择器是否为空
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches 判断所有条件是否均成立
+func (s Selector) Matches(get func(key string) (string, bool)) bool {
+	for _, requirement := range s {
+		if !requirement.Matches(get) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchesLabels 判断资源对象的标签是否满足选择器
+func (s Selector) MatchesLabels(obj ApiObject) bool {
+	labels := obj.GetMetadata().Labels
+	return s.Matches(func(key string) (string, bool) {
+		value, ok := labels[key]
+		return value, ok
+	})
+}
+
+// MatchesFields 判断资源对象的字段是否满足选择器
+func (s Selector) MatchesFields(obj ApiObject) bool {
+	if s.Empty() {
+		return true
+	}
+	content, err := decodeContent(obj)
+	if err != nil {
+		return false
+	}
+	return s.Matches(func(key string) (string, bool) {
+		return getFieldValue(content, strings.Split(key, "."))
+	})
+}
+
+// ParseLabelSelector 解析标签选择器，支持 key=value、key==value、key!=value、key in (v1,v2)、key notin (v1,v2)、key 与 !key 形式的条件
+func ParseLabelSelector(selector string) (Selector, error) {
+	result := Selector{}
+	for _, term := range splitSelector(selector) {
+		var requirement Requirement
+		if strings.HasPrefix(term, "!") {
+			requirement = Requirement{Key: strings.TrimSpace(term[1:]), Operator: SelectorOpDoesNotExist}
+		} else if matches := selectorSetRegex.FindStringSubmatch(term); matches != nil {
+			requirement = Requirement{Key: matches[1], Operator: matches[2]}
+			for _, value := range strings.Split(matches[3], ",") {
+				value = strings.TrimSpace(value)
+				if !selectorValueRegex.MatchString(value) {
+					return nil, e.InvalidSelectorError{Selector: selector}
+				}
+				requirement.Values = append(requirement.Values, value)
+			}
+		} else if key, op, value, ok := splitEquality(term); ok {
+			if !selectorValueRegex.MatchString(value) {
+				return nil, e.InvalidSelectorError{Selector: selector}
+			}
+			requirement = Requirement{Key: key, Operator: op, Values: []string{value}}
+		} else {
+			requirement = Requirement{Key: term, Operator: SelectorOpExists}
+		}
+		if !selectorKeyRegex.MatchString(requirement.Key) {
+			return nil, e.InvalidSelectorError{Selector: selector}
+		}
+		result = append(result, requirement)
+	}
+	return result, nil
+}
+
+// ParseFieldSelector 解析字段选择器，支持 field=value、field==value 与 field!=value 形式的条件。
+// 字段为以点分隔且忽略大小写的字段路径，如 metadata.name、status.phase、spec.appRef.name
+func ParseFieldSelector(selector string) (Selector, error) {
+	result := Selector{}
+	for _, term := range splitSelector(selector) {
+		key, op, value, ok := splitEquality(term)
+		if !ok || !selectorKeyRegex.MatchString(key) {
+			return nil, e.InvalidSelectorError{Selector: selector}
+		}
+		result = append(result, Requirement{Key: key, Operator: op, Values: []string{value}})
+	}
+	return result, nil
+}
+
+// splitSelector 按不在括号内的逗号拆分选择器中的条件
+func splitSelector(selector string) []string {
+	terms := []string{}
+	depth := 0
+	start := 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	terms = append(terms, selector[start:])
+
+	result := []string{}
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			result = append(result, term)
+		}
+	}
+	return result
+}
+
+// splitEquality 拆分等值条件，返回键、操作符与值
+func splitEquality(term string) (string, string, string, bool) {
+	for _, op := range []string{"!=", "==", "="} {
+		if index := strings.Index(term, op); index >= 0 {
+			key := strings.TrimSpace(term[:index])
+			value := strings.TrimSpace(term[index+len(op):])
+			if op == "==" {
+				op = SelectorOpEquals
+			}
+			return key, op, value, true
+		}
+	}
+	return "", "", "", false
+}
+
+// decodeContent 将资源对象解析为通用的JSON结构，数值保留为json.Number
+func decodeContent(obj ApiObject) (interface{}, error) {
+	data, err := obj.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	var content interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// getFieldValue 获取路径所指向的字段值，路径中的字段名忽略大小写，仅支持字符串、数值与布尔类型的字段
+func getFieldValue(content interface{}, path []string) (string, bool) {
+	if len(path) == 0 {
+		switch value := content.(type) {
+		case string:
+			return value, true
+		case json.Number:
+			return value.String(), true
+		case bool:
+			if value {
+				return "true", true
+			}
+			return "false", true
+		}
+		return "", false
+	}
+
+	fields, ok := content.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	for key, child := range fields {
+		if strings.EqualFold(key, path[0]) {
+			return getFieldValue(child, path[1:])
+		}
+	}
+	return "", false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesKeyword 判断资源对象的名称或ShortName注解是否包含关键字，忽略大小写
+func MatchesKeyword(obj ApiObject, keyword string) bool {
+	if keyword == "" {
+		return true
+	}
+	keyword = strings.ToLower(keyword)
+	metadata := obj.GetMetadata()
+	if strings.Contains(strings.ToLower(metadata.Name), keyword) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(metadata.Annotations["ShortName"]), keyword)
+}
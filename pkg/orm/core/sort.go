@@ -1,5 +1,12 @@
 package core
 
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
 type SortByCreateTime ApiObjectList
 
 func (s SortByCreateTime) Len() int {
@@ -27,3 +34,66 @@ func (s SortByRevision) Swap(i, j int) {
 func (s SortByRevision) Less(i, j int) bool {
 	return s[i].GetMetadata().ResourceVersion < s[j].GetMetadata().ResourceVersion
 }
+
+// SortByField 按字段路径对资源对象进行稳定排序，路径以点号分隔且字段名忽略大小写，如metadata.createTime。
+// 时间字段按时间先后比较，数值字段按数值大小比较，其余按字符串比较。缺少该字段的对象总是排在最后，字段值相同时按存储键排序
+func SortByField(list ApiObjectList, field string, desc bool) {
+	path := strings.Split(field, ".")
+	values := make(map[ApiObject]string, len(list))
+	exists := make(map[ApiObject]bool, len(list))
+	for _, obj := range list {
+		content, err := decodeContent(obj)
+		if err != nil {
+			continue
+		}
+		values[obj], exists[obj] = getFieldValue(content, path)
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if exists[a] != exists[b] {
+			return exists[a]
+		}
+		if cmp := compareFieldValues(values[a], values[b]); cmp != 0 {
+			if desc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return a.GetKey() < b.GetKey()
+	})
+}
+
+// SortByKey 按存储键(即命名空间与名称)对资源对象排序
+func SortByKey(list ApiObjectList) {
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].GetKey() < list[j].GetKey()
+	})
+}
+
+// compareFieldValues 比较两个字段值，均为时间或数值时按时间或数值比较
+func compareFieldValues(a string, b string) int {
+	if timeA, err := time.Parse(time.RFC3339Nano, a); err == nil {
+		if timeB, err := time.Parse(time.RFC3339Nano, b); err == nil {
+			switch {
+			case timeA.Before(timeB):
+				return -1
+			case timeA.After(timeB):
+				return 1
+			}
+			return 0
+		}
+	}
+	if numA, err := strconv.ParseFloat(a, 64); err == nil {
+		if numB, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case numA < numB:
+				return -1
+			case numA > numB:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
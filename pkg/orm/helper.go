@@ -11,8 +11,9 @@ import (
 
 func init() {
 	helper = Helper{
-		V1: v1.GetHelper(),
-		V2: v2.GetHelper(),
+		V1:        v1.GetHelper(),
+		V2:        v2.GetHelper(),
+		Informers: NewInformerFactory(),
 	}
 }
 
@@ -22,6 +23,9 @@ var helper Helper
 type Helper struct {
 	V1 v1.Helper
 	V2 v2.Helper
+
+	// 共享的资源对象缓存，提供基于缓存的读取与变更通知
+	Informers *InformerFactory
 }
 
 func GetHelper() *Helper {
@@ -0,0 +1,57 @@
+package orm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+)
+
+const (
+	// informerResyncPeriod 缓存与数据库重新同步的周期
+	informerResyncPeriod = 5 * time.Minute
+)
+
+// InformerFactory 管理共享的资源对象缓存，每种资源的每个结构版本只创建一个缓存
+type InformerFactory struct {
+	mutex     sync.Mutex
+	ctx       context.Context
+	informers map[core.GVK]*registry.Informer
+}
+
+// For 获取存储器所对应资源的共享缓存，缓存不存在时创建。在Start之后创建的缓存会立即开始同步
+func (f *InformerFactory) For(r registry.ApiObjectRegistry) *registry.Informer {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	gvk := r.GVK()
+	informer, ok := f.informers[gvk]
+	if !ok {
+		informer = registry.NewInformer(r, informerResyncPeriod)
+		f.informers[gvk] = informer
+		if f.ctx != nil {
+			go informer.Run(f.ctx)
+		}
+	}
+	return informer
+}
+
+// Start 开始同步所有已创建的缓存，直至ctx结束
+func (f *InformerFactory) Start(ctx context.Context) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.ctx = ctx
+	for _, informer := range f.informers {
+		go informer.Run(ctx)
+	}
+}
+
+// NewInformerFactory 创建共享缓存管理器
+func NewInformerFactory() *InformerFactory {
+	return &InformerFactory{
+		informers: make(map[core.GVK]*registry.Informer),
+	}
+}
@@ -19,6 +19,7 @@ func InitStorage() {
 	registry.RegisterStorageVersion(core.GK{Group: core.Group, Kind: core.KindJob}, v2.ApiVersion)
 
 	// 注册实体对象存储器，用于数据迁移
+	registry.RegisterStorageRegistry(v1.NewAdmissionConfigRegistry())
 	registry.RegisterStorageRegistry(v1.NewAppRegistry())
 	registry.RegisterStorageRegistry(v1.NewAppInstanceRegistry())
 	registry.RegisterStorageRegistry(v1.NewAuditRegistry())
@@ -28,8 +29,16 @@ func InitStorage() {
 	registry.RegisterStorageRegistry(v1.NewGPURegistry())
 	registry.RegisterStorageRegistry(v1.NewHostRegistry())
 	registry.RegisterStorageRegistry(v1.NewJobRegistry())
+	registry.RegisterStorageRegistry(v1.NewNamespaceRegistry())
 	registry.RegisterStorageRegistry(v1.NewPkgRegistry())
+	registry.RegisterStorageRegistry(v1.NewProjectRegistry())
+	registry.RegisterStorageRegistry(v1.NewRevisionRegistry())
+	registry.RegisterStorageRegistry(v1.NewRoleRegistry())
+	registry.RegisterStorageRegistry(v1.NewRoleBindingRegistry())
+	registry.RegisterStorageRegistry(v1.NewServiceAccountRegistry())
+	registry.RegisterStorageRegistry(v1.NewUserRegistry())
 	registry.RegisterStorageRegistry(v2.NewAppInstanceRegistry())
+	registry.RegisterStorageRegistry(v2.NewHostRegistry())
 	registry.RegisterStorageRegistry(v2.NewJobRegistry())
 
 	// 迁移数据结构
@@ -46,6 +55,87 @@ func migrate() {
 
 	// 将数据库中的所有对象更新成Schema中所注册的存储版本
 	registry.MigrateStorageVersion()
+
+	// 根据当前的资源对象重建索引，补充旧版本中未建立的索引
+	if err := registry.RebuildStorageIndexes(); err != nil {
+		log.Error(err)
+	}
+
+	// 为旧版本中创建的事件、GPU与任务配置补充所有者引用，由垃圾回收器接管其级联删除
+	migrateOwnerReferences()
+}
+
+// migrateOwnerReferences 根据资源对象中已有的关联字段补充所有者引用
+func migrateOwnerReferences() {
+	ctx := context.TODO()
+
+	eventRegistry := v1.NewEventRegistry()
+	eventList, err := eventRegistry.List(ctx, "")
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, obj := range eventList {
+		event := obj.(*v1.Event)
+		if len(event.Metadata.OwnerReferences) > 0 || event.Spec.ResourceRef.Kind == "" {
+			continue
+		}
+		event.Metadata.OwnerReferences = []core.OwnerReference{{
+			Kind:      event.Spec.ResourceRef.Kind,
+			Namespace: event.Spec.ResourceRef.Namespace,
+			Name:      event.Spec.ResourceRef.Name,
+		}}
+		if _, err := eventRegistry.Update(ctx, event, core.WithAllFields()); err != nil {
+			log.Error(err)
+		}
+	}
+
+	gpuRegistry := v1.NewGPURegistry()
+	gpuList, err := gpuRegistry.List(ctx, "")
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	for _, obj := range gpuList {
+		gpu := obj.(*v1.GPU)
+		if len(gpu.Metadata.OwnerReferences) > 0 || gpu.Spec.HostRef == "" {
+			continue
+		}
+		gpu.Metadata.OwnerReferences = []core.OwnerReference{{Kind: core.KindHost, Name: gpu.Spec.HostRef}}
+		if _, err := gpuRegistry.Update(ctx, gpu, core.WithAllFields()); err != nil {
+			log.Error(err)
+		}
+	}
+
+	jobList, err := v2.NewJobRegistry().List(ctx, "")
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	configMapRegistry := v1.NewConfigMapRegistry()
+	for _, obj := range jobList {
+		job := obj.(*v2.Job)
+		for _, play := range job.Spec.Exec.Ansible.Plays {
+			for _, ref := range []v2.ConfigMapRef{play.Inventory.ValueFrom.ConfigMapRef, play.GroupVars.ValueFrom.ConfigMapRef} {
+				if ref.Name == "" {
+					continue
+				}
+				configMapObj, err := configMapRegistry.Get(ctx, ref.Namespace, ref.Name)
+				if err != nil {
+					log.Error(err)
+					continue
+				} else if configMapObj == nil || len(configMapObj.GetMetadata().OwnerReferences) > 0 {
+					continue
+				}
+				metadata := configMapObj.GetMetadata()
+				metadata.AddOwnerReference(core.NewOwnerReference(core.KindJob, job.Metadata))
+				configMapObj.SetMetadata(metadata)
+				if _, err := configMapRegistry.Update(ctx, configMapObj, core.WithAllFields()); err != nil {
+					log.Error(err)
+				}
+			}
+		}
+	}
 }
 
 // initData 创建初始数据
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+// AdmissionAttributes 准入控制的请求内容
+type AdmissionAttributes struct {
+	// 操作类型，可选值为core.AdmissionOperationCreate、core.AdmissionOperationUpdate与core.AdmissionOperationDelete
+	Operation  string
+	Kind       string
+	ApiVersion string
+	Namespace  string
+	Name       string
+	// 待写入的资源对象，删除时为空
+	Object json.RawMessage
+	// 写入前的资源对象，创建时为空
+	OldObject json.RawMessage
+}
+
+// Admitter 准入控制器，在资源对象写入数据库前决定是否允许写入，并可以修改待写入的对象
+type Admitter interface {
+	// Admit 拒绝写入时返回错误，允许写入时返回修改后的资源对象序列化数据，不修改对象时返回空
+	Admit(ctx context.Context, attrs AdmissionAttributes) ([]byte, error)
+}
+
+var admitter Admitter
+
+// SetAdmitter 设置准入控制器，为空时不进行准入控制
+func SetAdmitter(a Admitter) {
+	admitter = a
+}
+
+// admit 对待写入的资源对象执行准入控制。准入控制器修改了对象时，原地替换obj的内容并重新执行校验与填充钩子，
+// 修改仅对Spec、Metadata.Labels与Metadata.Annotations生效，其余字段保持不变
+func (r Registry) admit(ctx context.Context, operation string, obj core.ApiObject, oldObj core.ApiObject) error {
+	if admitter == nil {
+		return nil
+	}
+
+	attrs := AdmissionAttributes{
+		Operation:  operation,
+		Kind:       r.gvk.Kind,
+		ApiVersion: r.gvk.ApiVersion,
+	}
+	var err error
+	var metadata core.Metadata
+	if oldObj != nil {
+		if attrs.OldObject, err = json.Marshal(oldObj); err != nil {
+			return err
+		}
+	}
+	if obj != nil {
+		obj.GetMetadata().CopyTo(&metadata)
+		if attrs.Object, err = json.Marshal(obj); err != nil {
+			return err
+		}
+	} else if oldObj != nil {
+		metadata = oldObj.GetMetadata()
+	}
+	attrs.Namespace = metadata.Namespace
+	attrs.Name = metadata.Name
+
+	data, err := admitter.Admit(ctx, attrs)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if obj == nil || data == nil {
+		return nil
+	}
+
+	// 使用修改后的内容替换对象，并还原服务端维护的字段
+	status := obj.GetStatus()
+	value := reflect.ValueOf(obj).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	if err := json.Unmarshal(data, obj); err != nil {
+		err = e.InvalidPatchError{Reason: err.Error()}
+		log.Error(err)
+		return err
+	}
+	mutated := obj.GetMetadata()
+	metadata.Labels = mutated.Labels
+	metadata.Annotations = mutated.Annotations
+	obj.SetMetadata(metadata)
+	obj.SetStatus(status)
+	obj.SetGVK(r.gvk)
+
+	if err := r.commonValidate(obj); err != nil {
+		return err
+	}
+	if r.validateHook != nil {
+		if err := r.validateHook(obj); err != nil {
+			return err
+		}
+	}
+	if r.mutateHook != nil {
+		if err := r.mutateHook(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
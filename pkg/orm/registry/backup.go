@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/version"
+)
+
+const (
+	// BackupFormatVersion 当前备份归档的格式版本，格式发生不兼容变化时需要递增
+	BackupFormatVersion = "v1"
+)
+
+// BackupArchive 备份归档，记录了core.RegistryPrefix下的所有键值以及备份时各资源的存储版本
+type BackupArchive struct {
+	FormatVersion   string
+	ServerVersion   string
+	CreateTime      time.Time
+	StorageVersions []core.GVK
+	Items           []BackupItem
+}
+
+// BackupItem 备份归档中的单条键值，GVK为键值中资源对象的结构版本，非资源对象的键值为空
+type BackupItem struct {
+	Key   string
+	GVK   core.GVK
+	Value string
+}
+
+// isBackupKey 判断键是否需要备份，分布式锁等运行时数据以及可重建的索引键不参与备份与还原
+func isBackupKey(key string) bool {
+	return strings.HasPrefix(key, core.RegistryPrefix+"/") && !strings.HasPrefix(key, core.RegistryPrefix+"/locks/") && !strings.HasPrefix(key, core.RegistryPrefix+"/indexes/")
+}
+
+// Backup 将core.RegistryPrefix下的所有键值导出为gzip压缩的备份归档并写入w
+func Backup(w io.Writer) (*BackupArchive, error) {
+	kvList, err := db.KV.List(core.RegistryPrefix+"/", true)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	archive := &BackupArchive{
+		FormatVersion:   BackupFormatVersion,
+		ServerVersion:   version.Version,
+		CreateTime:      time.Now(),
+		StorageVersions: []core.GVK{},
+		Items:           []BackupItem{},
+	}
+	for gk, apiVersion := range storageVersion {
+		archive.StorageVersions = append(archive.StorageVersions, core.GVK{Group: gk.Group, ApiVersion: apiVersion, Kind: gk.Kind})
+	}
+
+	for key, value := range kvList {
+		if !isBackupKey(key) {
+			continue
+		}
+		item := BackupItem{
+			Key:   key,
+			Value: value,
+		}
+		// 记录资源对象的存储版本，用于还原时的结构转换
+		metaType := new(core.MetaType)
+		if err := json.Unmarshal([]byte(value), metaType); err == nil && metaType.Kind != "" {
+			item.GVK = core.GVK{Group: core.Group, ApiVersion: metaType.ApiVersion, Kind: metaType.Kind}
+		}
+		archive.Items = append(archive.Items, item)
+	}
+	sort.Slice(archive.Items, func(i, j int) bool {
+		return archive.Items[i].Key < archive.Items[j].Key
+	})
+
+	gzipWriter := gzip.NewWriter(w)
+	if err := json.NewEncoder(gzipWriter).Encode(archive); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	if err := gzipWriter.Close(); err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	log.Infof("backup %d items", len(archive.Items))
+	return archive, nil
+}
+
+// Restore 从r中读取备份归档并写回数据库，已存在的同名键值会被覆盖，备份中不存在的键值保持不变。
+// 写入完成后会重新执行数据迁移，使旧版本服务导出的备份转换为当前的存储版本。
+// 归档无法解析或格式版本不支持时返回e.InvalidBackupError，此时数据库未被修改；写入存储失败时返回e.PartialRestoreError
+func Restore(r io.Reader) (*BackupArchive, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		log.Error(err)
+		return nil, e.InvalidBackupError{Reason: err.Error()}
+	}
+	defer gzipReader.Close()
+
+	archive := new(BackupArchive)
+	if err := json.NewDecoder(gzipReader).Decode(archive); err != nil {
+		log.Error(err)
+		return nil, e.InvalidBackupError{Reason: err.Error()}
+	}
+	if archive.FormatVersion != BackupFormatVersion {
+		err := e.InvalidBackupError{Reason: "unsupported backup format version " + archive.FormatVersion}
+		log.Error(err)
+		return nil, err
+	}
+
+	restored := 0
+	for _, item := range archive.Items {
+		if !isBackupKey(item.Key) {
+			log.Warnf("skip restoring key %s", item.Key)
+			continue
+		}
+		if err := db.KV.Set(item.Key, item.Value); err != nil {
+			log.Error(err)
+			return nil, e.PartialRestoreError{Restored: restored, Total: len(archive.Items), Reason: err.Error()}
+		}
+		restored++
+	}
+	log.Infof("restored %d items from backup of version %s created at %s", len(archive.Items), archive.ServerVersion, archive.CreateTime)
+
+	// 迁移旧版本的存储路径与结构版本
+	MigrateNamespacedObjects()
+	MigrateStorageVersion()
+
+	// 重建还原后的资源对象的索引
+	if err := RebuildStorageIndexes(); err != nil {
+		return nil, e.PartialRestoreError{Restored: restored, Total: len(archive.Items), Reason: "rebuild indexes: " + err.Error()}
+	}
+
+	return archive, nil
+}
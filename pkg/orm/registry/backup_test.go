@@ -0,0 +1,63 @@
+package registry_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestRegistryBackupRestore(t *testing.T) {
+	cleanup := dbtest.NewBoltKV(t)
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	configMap := v1.NewConfigMap()
+	configMap.Metadata.Namespace = "default"
+	configMap.Metadata.Name = "test"
+	configMap.Data["key"] = "1"
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	archive, err := registry.Backup(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archive.FormatVersion != registry.BackupFormatVersion || len(archive.Items) != 1 || archive.Items[0].GVK.Kind != configMap.Kind {
+		t.Fatalf("unexpected archive: %+v", archive)
+	}
+
+	data := buf.Bytes()
+
+	// 数据库不可用时返回部分还原的错误
+	cleanup()
+	if _, err := registry.Restore(bytes.NewReader(data)); err == nil {
+		t.Fatal("restore to closed storage should fail")
+	} else if restoreErr, ok := err.(e.PartialRestoreError); !ok || restoreErr.Restored != 0 || restoreErr.Total != 1 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 还原至新的数据库
+	defer dbtest.NewBoltKV(t)()
+	for _, invalid := range [][]byte{[]byte("not gzip"), data[:len(data)/2]} {
+		if _, err := registry.Restore(bytes.NewReader(invalid)); err == nil {
+			t.Fatal("restore from invalid archive should fail")
+		} else if _, ok := err.(e.InvalidBackupError); !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := registry.Restore(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := configMapRegistry.Get(context.TODO(), "default", "test")
+	if err != nil {
+		t.Fatal(err)
+	} else if obj == nil || obj.(*v1.ConfigMap).Data["key"] != "1" {
+		t.Fatalf("unexpected restored object: %+v", obj)
+	}
+}
@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+const (
+	batchActionCreate = "create"
+	batchActionUpdate = "update"
+	batchActionDelete = "delete"
+)
+
+// BatchOp 批量写入中的单个资源对象操作，通过存储器的BatchCreate，BatchUpdate和BatchDelete方法构建
+type BatchOp struct {
+	registry  Registry
+	action    string
+	obj       core.ApiObject
+	namespace string
+	name      string
+	opts      []core.OpOpt
+}
+
+// BatchCreate 构建批量写入中的创建操作
+func (r Registry) BatchCreate(obj core.ApiObject, opts ...core.OpOpt) BatchOp {
+	return BatchOp{
+		registry: r,
+		action:   batchActionCreate,
+		obj:      obj,
+		opts:     opts,
+	}
+}
+
+// BatchUpdate 构建批量写入中的更新操作
+func (r Registry) BatchUpdate(obj core.ApiObject, opts ...core.OpOpt) BatchOp {
+	return BatchOp{
+		registry: r,
+		action:   batchActionUpdate,
+		obj:      obj,
+		opts:     opts,
+	}
+}
+
+// BatchDelete 构建批量写入中的删除操作
+func (r Registry) BatchDelete(namespace string, name string, opts ...core.OpOpt) BatchOp {
+	return BatchOp{
+		registry:  r,
+		action:    batchActionDelete,
+		namespace: namespace,
+		name:      name,
+		opts:      opts,
+	}
+}
+
+// Commit 在同一事务中提交多个资源对象的创建，更新与删除。
+// 所有对象均会进行与单独写入时相同的校验和钩子处理，任一对象在读取后被修改或已存在时全部操作均不生效，并返回资源冲突错误。
+// 返回的结果与操作一一对应，删除不存在的对象时对应结果为空
+func Commit(ctx context.Context, ops ...BatchOp) ([]core.ApiObject, error) {
+	results := make([]core.ApiObject, len(ops))
+	kvOps := []db.KVOp{}
+	committed := make([]bool, len(ops))
+	// 更新前的对象，提交成功后保存为历史修订版本
+	revisions := make([]core.ApiObject, len(ops))
+
+	// 执行校验与前置钩子并生成写入操作
+	for i, op := range ops {
+		var option core.Option
+		option.SetupOption(op.opts...)
+
+		start := len(kvOps)
+		switch op.action {
+		case batchActionCreate:
+			createOps, err := op.registry.prepareCreate(ctx, op.obj, option)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = op.obj
+			kvOps = append(kvOps, createOps...)
+			committed[i] = true
+		case batchActionUpdate:
+			result, updateOps, revision, err := op.registry.prepareUpdate(ctx, op.obj, option)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+			revisions[i] = revision
+			if updateOps != nil {
+				kvOps = append(kvOps, updateOps...)
+				committed[i] = true
+			}
+		case batchActionDelete:
+			obj, deleteOps, err := op.registry.prepareDelete(ctx, op.namespace, op.name, option)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = obj
+			if obj != nil {
+				kvOps = append(kvOps, deleteOps...)
+				committed[i] = true
+			}
+		default:
+			return nil, e.Errorf("unknown batch action %s", op.action)
+		}
+
+		// 试运行的操作只返回结果，不参与提交
+		if option.DryRun {
+			kvOps = kvOps[:start]
+			committed[i] = false
+		}
+	}
+
+	if len(kvOps) == 0 {
+		return results, nil
+	}
+
+	// 提交事务
+	ok, err := db.KV.Txn(kvOps...)
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		keys := []string{}
+		for _, kvOp := range kvOps {
+			// 索引键的写入不比较修订版本号，不会引起冲突
+			if kvOp.Compare {
+				keys = append(keys, kvOp.Key)
+			}
+		}
+		err := e.ResourceConflictError{Key: strings.Join(keys, ",")}
+		log.Error(err)
+		return nil, err
+	}
+
+	// 执行后置钩子
+	for i, op := range ops {
+		if !committed[i] {
+			continue
+		}
+		var hook HookFunc
+		switch op.action {
+		case batchActionCreate:
+			hook = op.registry.postCreateHook
+		case batchActionUpdate:
+			op.registry.saveRevision(ctx, revisions[i])
+			hook = op.registry.postUpdateHook
+		case batchActionDelete:
+			hook = op.registry.postDeleteHook
+		}
+		if hook != nil {
+			if err := hook(results[i]); err != nil {
+				return results, err
+			}
+		}
+	}
+	log.Tracef("committed %d operations", len(kvOps))
+	return results, nil
+}
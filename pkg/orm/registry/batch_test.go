@@ -0,0 +1,41 @@
+package registry_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestRegistryCommit(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	jobRegistry := v1.NewJobRegistry()
+
+	configMap := v1.NewConfigMap()
+	configMap.Metadata.Namespace = "default"
+	configMap.Metadata.Name = "test"
+	job := v1.NewJob()
+	job.Metadata.Name = "test"
+	if _, err := registry.Commit(context.TODO(), configMapRegistry.BatchCreate(configMap), jobRegistry.BatchCreate(job)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 任一对象已存在时，其余对象也不会被写入
+	configMap2 := v1.NewConfigMap()
+	configMap2.Metadata.Namespace = "default"
+	configMap2.Metadata.Name = "test2"
+	job2 := v1.NewJob()
+	job2.Metadata.Name = "test"
+	if _, err := registry.Commit(context.TODO(), configMapRegistry.BatchCreate(configMap2), jobRegistry.BatchCreate(job2)); err == nil {
+		t.Fatal("commit with existing job should fail")
+	}
+	if obj, err := configMapRegistry.Get(context.TODO(), "default", "test2"); err != nil {
+		t.Fatal(err)
+	} else if obj != nil {
+		t.Fatal("configmap should not be created")
+	}
+}
@@ -0,0 +1,26 @@
+package registry
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+// encodeContinue 根据当前页最后一个对象的存储键生成获取下一页的令牌
+func encodeContinue(lastKey string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(lastKey))
+}
+
+// decodeContinue 解析令牌并返回下一页的起始存储键，令牌为空时从前缀的第一个键开始
+func decodeContinue(prefix string, token string) (string, error) {
+	if token == "" {
+		return prefix, nil
+	}
+	lastKey, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || !strings.HasPrefix(string(lastKey), prefix) {
+		return "", e.InvalidContinueError{Continue: token}
+	}
+	// 从上一页最后一个键的下一个键开始
+	return string(lastKey) + "\x00", nil
+}
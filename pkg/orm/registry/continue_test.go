@@ -0,0 +1,71 @@
+package registry_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestRegistryListPage(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	for i := 0; i < 5; i++ {
+		configMap := v1.NewConfigMap()
+		configMap.Metadata.Namespace = "default"
+		configMap.Metadata.Name = "test" + strconv.Itoa(i)
+		configMap.Metadata.Labels["parity"] = strconv.Itoa(i % 2)
+		if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 按每页两条依次获取，直至返回的令牌为空
+	names := []string{}
+	continueToken := ""
+	for pages := 0; ; pages++ {
+		if pages > 3 {
+			t.Fatal("too many pages")
+		}
+		list, next, _, err := configMapRegistry.ListPage(context.TODO(), "default", core.WithLimit(2), core.WithContinue(continueToken))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, obj := range list {
+			names = append(names, obj.GetMetadata().Name)
+		}
+		if next == "" {
+			break
+		}
+		continueToken = next
+	}
+	if len(names) != 5 || names[0] != "test0" || names[4] != "test4" {
+		t.Fatalf("unexpected pages: %v", names)
+	}
+
+	// 选择器在分页前生效，每页均被填满
+	list, next, _, err := configMapRegistry.ListPage(context.TODO(), "default", core.WithLimit(2), core.WithLabelSelector("parity=0"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(list) != 2 || list[0].GetMetadata().Name != "test0" || list[1].GetMetadata().Name != "test2" || next == "" {
+		t.Fatalf("unexpected first page of selected items: %v, %s", list, next)
+	}
+	list, next, _, err = configMapRegistry.ListPage(context.TODO(), "default", core.WithLimit(2), core.WithLabelSelector("parity=0"), core.WithContinue(next))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(list) != 1 || list[0].GetMetadata().Name != "test4" || next != "" {
+		t.Fatalf("unexpected last page of selected items: %v, %s", list, next)
+	}
+
+	// 无效的令牌
+	if _, _, _, err := configMapRegistry.ListPage(context.TODO(), "default", core.WithContinue("invalid")); err == nil {
+		t.Fatal("invalid continue token should fail")
+	} else if _, ok := err.(e.InvalidContinueError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
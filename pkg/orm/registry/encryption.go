@@ -0,0 +1,281 @@
+package registry
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+// Encrypter 字段加密器，用于在资源对象写入数据库前加密敏感字段，并在读取后解密
+type Encrypter interface {
+	// Encrypt 使用当前主密钥加密明文，附加数据参与认证但不会被加密
+	Encrypt(plaintext string, additionalData string) (string, error)
+
+	// Decrypt 解密由Encrypt生成的密文，附加数据需要与加密时一致
+	Decrypt(ciphertext string, additionalData string) (string, error)
+
+	// Encrypted 判断值是否为Encrypt生成的密文
+	Encrypted(value string) bool
+
+	// Stale 判断密文是否由非当前主密钥加密，在密钥轮换时需要重新加密
+	Stale(ciphertext string) bool
+}
+
+// FieldPathsFunc 根据资源对象的内容返回需要加密的字段路径
+type FieldPathsFunc func(obj core.ApiObject) []string
+
+// encryptedPathsGetter 获取资源对象中需要加密的字段路径，由各结构版本的存储器实现
+type encryptedPathsGetter interface {
+	getEncryptedPaths(obj core.ApiObject) [][]string
+}
+
+var encrypter Encrypter
+
+// SetEncrypter 设置字段加密器，为空时不进行加密
+func SetEncrypter(enc Encrypter) {
+	encrypter = enc
+}
+
+// encode 序列化资源对象，并加密其中需要加密的字段。密文与资源对象的存储键绑定，无法复制到其他资源对象中使用
+func (r Registry) encode(obj core.ApiObject) (string, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	paths := r.getEncryptedPaths(obj)
+	if encrypter == nil || len(paths) == 0 {
+		return string(data), nil
+	}
+
+	content, err := unmarshalContent(string(data))
+	if err != nil {
+		return "", err
+	}
+	metadata := obj.GetMetadata()
+	key := r.getKey(metadata.Namespace, metadata.Name)
+	for _, path := range paths {
+		field := strings.Join(path, ".")
+		content, err = walkPath(content, path, func(plaintext string) (string, error) {
+			if encrypter.Encrypted(plaintext) {
+				return "", e.InvalidFieldError{Field: field, Reason: "不能写入密文"}
+			}
+			return encrypter.Encrypt(plaintext, key)
+		})
+		if err != nil {
+			log.Error(err)
+			return "", err
+		}
+	}
+	data, err = json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getEncryptedPaths 获取资源对象中需要加密的字段路径。存在敏感字段时，上次应用的配置中同样包含敏感字段，需要整体加密
+func (r Registry) getEncryptedPaths(obj core.ApiObject) [][]string {
+	fields := r.encryptedFields
+	if r.encryptedFieldsFunc != nil {
+		fields = append(append([]string{}, fields...), r.encryptedFieldsFunc(obj)...)
+	}
+	paths := [][]string{}
+	for _, field := range fields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+	if len(paths) > 0 {
+		paths = append(paths, []string{"Metadata", "Annotations", core.AnnotationLastAppliedConfiguration})
+	}
+	return paths
+}
+
+// unmarshalContent 将序列化数据解析为通用结构，数字保持原有的精度
+func unmarshalContent(value string) (interface{}, error) {
+	var content interface{}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// walkPath 使用f的返回值替换路径所指向的非空字符串字段，路径中的*匹配字典的所有键或数组的所有元素
+func walkPath(content interface{}, path []string, f func(string) (string, error)) (interface{}, error) {
+	if len(path) == 0 {
+		str, ok := content.(string)
+		if !ok || str == "" {
+			return content, nil
+		}
+		return f(str)
+	}
+
+	var err error
+	switch value := content.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if path[0] != "*" && path[0] != key {
+				continue
+			}
+			if value[key], err = walkPath(child, path[1:], f); err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for index, child := range value {
+			if path[0] != "*" && path[0] != strconv.Itoa(index) {
+				continue
+			}
+			if value[index], err = walkPath(child, path[1:], f); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return content, nil
+}
+
+// decrypt 解密序列化数据中需要加密的字段，字段路径由数据的结构版本所对应的存储器决定，其余字段保持原样
+func (r Registry) decrypt(value string) (string, error) {
+	if encrypter == nil {
+		return value, nil
+	}
+
+	metaType := new(core.MetaType)
+	if err := json.Unmarshal([]byte(value), metaType); err != nil {
+		return "", err
+	}
+	gvk := core.GVK{Group: r.gvk.Group, ApiVersion: metaType.ApiVersion, Kind: r.gvk.Kind}
+	var pathsGetter encryptedPathsGetter = r
+	if gvk != r.gvk {
+		registry, ok := storageRegistry[gvk].(encryptedPathsGetter)
+		if !ok {
+			return "", e.Errorf("storage registry of %+v not found", gvk)
+		}
+		pathsGetter = registry
+	}
+	obj, err := newByGVK(gvk)
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal([]byte(value), &obj); err != nil {
+		return "", err
+	}
+	paths := pathsGetter.getEncryptedPaths(obj)
+	if len(paths) == 0 {
+		return value, nil
+	}
+
+	content, err := unmarshalContent(value)
+	if err != nil {
+		return "", err
+	}
+	metadata := obj.GetMetadata()
+	key := r.getKey(metadata.Namespace, metadata.Name)
+	for _, path := range paths {
+		content, err = walkPath(content, path, func(str string) (string, error) {
+			// 开启加密前写入的明文无需解密
+			if !encrypter.Encrypted(str) {
+				return str, nil
+			}
+			return encrypter.Decrypt(str, key)
+		})
+		if err != nil {
+			log.Error(err)
+			return "", err
+		}
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// needEncrypt 判断存储中的序列化数据是否存在由旧密钥加密或尚未加密的敏感字段
+func (r Registry) needEncrypt(obj core.ApiObject, value string) (bool, error) {
+	content, err := unmarshalContent(value)
+	if err != nil {
+		return false, err
+	}
+	found := false
+	mark := func(str string) (string, error) {
+		if !encrypter.Encrypted(str) || encrypter.Stale(str) {
+			found = true
+		}
+		return str, nil
+	}
+	for _, path := range r.getEncryptedPaths(obj) {
+		if _, err := walkPath(content, path, mark); err != nil {
+			return false, err
+		}
+	}
+	return found, nil
+}
+
+// EncryptObjects 使用当前的主密钥重新加密存储器所对应的所有资源对象，尚未加密的敏感字段也会被加密
+func (r Registry) EncryptObjects() error {
+	if encrypter == nil {
+		return nil
+	}
+
+	kvList, err := db.KV.List(r.getKey("", ""), true)
+	if err != nil {
+		return err
+	}
+
+	for key := range kvList {
+		value, modRevision, err := db.KV.GetWithRevision(key)
+		if err != nil {
+			return err
+		} else if value == "" {
+			continue
+		}
+
+		// 只处理与当前存储器结构版本一致的对象，其他版本的对象由对应的存储器处理
+		metaType := new(core.MetaType)
+		if err := json.Unmarshal([]byte(value), metaType); err != nil {
+			return err
+		}
+		if metaType.Kind != r.gvk.Kind || metaType.ApiVersion != r.gvk.ApiVersion {
+			continue
+		}
+
+		obj, err := r.decode(value)
+		if err != nil {
+			return err
+		}
+		if ok, err := r.needEncrypt(obj, value); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		data, err := r.encode(obj)
+		if err != nil {
+			return err
+		}
+		// 保留原有的存活时间，对象已被其他写入修改时跳过，修改时已使用当前主密钥加密
+		log.Debugf("encrypt %s", key)
+		if _, err := db.KV.CompareAndSet(key, data, modRevision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncryptStorage 使用当前的主密钥重新加密数据库中的所有资源对象，用于密钥轮换或首次开启加密
+func EncryptStorage() error {
+	for _, registry := range storageRegistry {
+		if err := registry.EncryptObjects(); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package registry_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/encryption"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestRegistryEncryption(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	dir, err := ioutil.TempDir("", "waves-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	enc, err := encryption.NewAESGCMEncrypter(filepath.Join(dir, "encryption.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry.SetEncrypter(enc)
+	defer registry.SetEncrypter(nil)
+
+	hostRegistry := v1.NewHostRegistry()
+	host := v1.NewHost()
+	host.Metadata.Name = "test"
+	host.Spec.SSH.Password = "secret"
+	if _, err := hostRegistry.Create(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+
+	// 存储中的密码为密文，读取时解密
+	key := core.RegistryPrefix + "/hosts/test"
+	value, err := db.KV.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(value, "secret") {
+		t.Fatalf("password stored in plaintext: %s", value)
+	}
+	obj, err := hostRegistry.Get(context.TODO(), "", "test")
+	if err != nil {
+		t.Fatal(err)
+	} else if obj.(*v1.Host).Spec.SSH.Password != "secret" {
+		t.Fatalf("unexpected decrypted password: %s", obj.(*v1.Host).Spec.SSH.Password)
+	}
+
+	// 轮换密钥后使用新密钥重新加密
+	if _, err := enc.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := hostRegistry.EncryptObjects(); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := db.KV.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	} else if rotated == value {
+		t.Fatal("object not re-encrypted")
+	}
+	obj, err = hostRegistry.Get(context.TODO(), "", "test")
+	if err != nil {
+		t.Fatal(err)
+	} else if obj.(*v1.Host).Spec.SSH.Password != "secret" {
+		t.Fatalf("unexpected decrypted password: %s", obj.(*v1.Host).Spec.SSH.Password)
+	}
+
+	// 不允许写入密文，只有需要加密的字段会被解密
+	ciphertext, err := enc.Encrypt("secret", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := v1.NewHost()
+	other.Metadata.Name = "other"
+	other.Spec.SSH.Password = ciphertext
+	if _, err := hostRegistry.Create(context.TODO(), other); err == nil {
+		t.Fatal("expected error of writing ciphertext")
+	} else if _, ok := err.(e.InvalidFieldError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	other.Spec.SSH.Password = ""
+	other.Spec.SSH.User = ciphertext
+	if _, err := hostRegistry.Create(context.TODO(), other); err != nil {
+		t.Fatal(err)
+	}
+	obj, err = hostRegistry.Get(context.TODO(), "", "other")
+	if err != nil {
+		t.Fatal(err)
+	} else if obj.(*v1.Host).Spec.SSH.User != ciphertext {
+		t.Fatalf("unexpected user: %s", obj.(*v1.Host).Spec.SSH.User)
+	}
+
+	// 密文与存储键绑定，复制到其他资源对象后无法解密
+	if err := db.KV.Set(core.RegistryPrefix+"/hosts/other", strings.Replace(rotated, `"Name":"test"`, `"Name":"other"`, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hostRegistry.Get(context.TODO(), "", "other"); err == nil {
+		t.Fatal("expected error of decrypting ciphertext copied from other object")
+	}
+}
@@ -0,0 +1,201 @@
+package registry
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+// IndexFunc 索引计算方法，返回资源对象在索引中的取值，一个资源对象可以有多个取值，也可以没有取值。
+// 索引键只在Create、Update与Delete时维护，因此索引计算方法只应依赖Metadata与Spec
+type IndexFunc func(obj core.ApiObject) []string
+
+// SetIndexer 设置名称为name的索引，写入资源对象时会在数据库中维护对应的索引键，可通过ListByIndex按索引取值获取资源对象
+func (r *Registry) SetIndexer(name string, f IndexFunc) {
+	if r.indexers == nil {
+		r.indexers = make(map[string]IndexFunc)
+	}
+	r.indexers[name] = f
+}
+
+// Indexers 获取所有索引名称与索引计算方法
+func (r Registry) Indexers() map[string]IndexFunc {
+	return r.indexers
+}
+
+// ListByIndex 获取索引取值为value的所有资源对象，结果按存储键排序
+func (r Registry) ListByIndex(ctx context.Context, index string, value string) (core.ApiObjectList, error) {
+	if _, ok := r.indexers[index]; !ok {
+		err := e.Errorf("index %s of %s not found", index, r.gvk.Kind)
+		log.Error(err)
+		return nil, err
+	}
+
+	kvList, err := db.KV.List(r.getIndexPrefix(index, value), true)
+	if err != nil {
+		return nil, err
+	}
+	indexKeys := []string{}
+	for indexKey := range kvList {
+		indexKeys = append(indexKeys, indexKey)
+	}
+	sort.Strings(indexKeys)
+
+	list := core.ApiObjectList{}
+	for _, indexKey := range indexKeys {
+		str, err := db.KV.Get(kvList[indexKey])
+		if err != nil {
+			return nil, err
+		}
+		var obj core.ApiObject
+		if str != "" {
+			if obj, err = r.decode(str); err != nil {
+				return nil, err
+			}
+		}
+		// 资源对象已不存在或索引取值已变化时，索引键已过时，清除后跳过
+		if obj == nil || !containsString(r.indexValues(obj)[index], value) {
+			log.Debugf("remove stale index %s", indexKey)
+			if _, err := db.KV.Delete(indexKey); err != nil {
+				log.Warn(err)
+			}
+			continue
+		}
+		if err := r.decorate(obj); err != nil {
+			return nil, err
+		}
+		list = append(list, obj)
+	}
+	log.Tracef("listed %s of %s by index %s: %+v", value, r.gvk.Kind, index, list)
+	return list, nil
+}
+
+// RebuildIndexes 根据当前的资源对象重建所有索引键，清除过时的索引键并补充缺失的索引键
+func (r Registry) RebuildIndexes() error {
+	objs, err := r.List(context.TODO(), "")
+	if err != nil {
+		return err
+	}
+	indexKeys := make(map[string]string)
+	for _, obj := range objs {
+		metadata := obj.GetMetadata()
+		for indexKey, key := range r.indexKeys(r.getKey(metadata.Namespace, metadata.Name), obj) {
+			indexKeys[indexKey] = key
+		}
+	}
+
+	kvList, err := db.KV.List(r.getIndexPrefix("", ""), true)
+	if err != nil {
+		return err
+	}
+	for indexKey := range kvList {
+		if _, ok := indexKeys[indexKey]; !ok {
+			if _, err := db.KV.Delete(indexKey); err != nil {
+				return err
+			}
+		}
+	}
+	for indexKey, key := range indexKeys {
+		if kvList[indexKey] == key {
+			continue
+		}
+		if err := db.KV.Set(indexKey, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RebuildStorageIndexes 重建数据库中所有资源对象的索引键
+func RebuildStorageIndexes() error {
+	for _, registry := range ListStorageRegistries() {
+		if err := registry.RebuildIndexes(); err != nil {
+			log.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// indexOps 生成资源对象从oldObj变更为obj时维护索引键的写入操作，oldObj为空表示创建，obj为空表示删除。
+// 索引键与资源对象使用相同的存活时间
+func (r Registry) indexOps(key string, oldObj core.ApiObject, obj core.ApiObject) []db.KVOp {
+	oldIndexKeys := make(map[string]string)
+	if oldObj != nil {
+		oldIndexKeys = r.indexKeys(key, oldObj)
+	}
+	indexKeys := make(map[string]string)
+	if obj != nil {
+		indexKeys = r.indexKeys(key, obj)
+	}
+
+	ops := []db.KVOp{}
+	for indexKey := range oldIndexKeys {
+		if _, ok := indexKeys[indexKey]; !ok {
+			ops = append(ops, db.OpDelete(indexKey))
+		}
+	}
+	for indexKey := range indexKeys {
+		ops = append(ops, db.OpSet(indexKey, key).WithTTL(r.getTTL(obj)))
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		return ops[i].Key < ops[j].Key
+	})
+	return ops
+}
+
+// indexKeys 获取资源对象的所有索引键，返回索引键到资源对象存储键的映射
+func (r Registry) indexKeys(key string, obj core.ApiObject) map[string]string {
+	indexKeys := make(map[string]string)
+	name := url.PathEscape(strings.TrimPrefix(key, r.getKey("", "")))
+	for index, values := range r.indexValues(obj) {
+		for _, value := range values {
+			indexKeys[r.getIndexPrefix(index, value)+name] = key
+		}
+	}
+	return indexKeys
+}
+
+// indexValues 计算资源对象在所有索引中的取值
+func (r Registry) indexValues(obj core.ApiObject) map[string][]string {
+	values := make(map[string][]string)
+	for index, f := range r.indexers {
+		values[index] = f(obj)
+	}
+	return values
+}
+
+// getIndexPrefix 获取索引键的前缀，索引键的格式为/prophet/indexes/<kind>s/<index>/<value>/<namespace/name>，
+// 其中取值与资源对象名称均经过转义
+func (r Registry) getIndexPrefix(index string, value string) string {
+	prefix := core.RegistryPrefix + "/indexes/" + r.gvk.Kind + "s/"
+	if index != "" {
+		prefix += index + "/" + url.PathEscape(value) + "/"
+	}
+	return prefix
+}
+
+// ownerReferencesIndex 所有者引用索引，所有资源对象默认建立该索引
+func ownerReferencesIndex(obj core.ApiObject) []string {
+	values := []string{}
+	for _, ref := range obj.GetMetadata().OwnerReferences {
+		values = append(values, core.IndexValue(ref.Kind, ref.Namespace, ref.Name))
+	}
+	return values
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package registry_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestRegistryListByIndex(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	gpuRegistry := v1.NewGPURegistry()
+	for _, name := range []string{"a-slot-0", "a-slot-1", "b-slot-0"} {
+		gpu := v1.NewGPU()
+		gpu.Metadata.Name = name
+		gpu.Spec.HostRef = strings.Split(name, "-")[0]
+		if _, err := gpuRegistry.Create(context.TODO(), gpu); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listNames := func(value string) []string {
+		objs, err := gpuRegistry.ListByIndex(context.TODO(), core.IndexHostRef, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names := []string{}
+		for _, obj := range objs {
+			names = append(names, obj.GetMetadata().Name)
+		}
+		return names
+	}
+	if names := listNames("a"); strings.Join(names, ",") != "a-slot-0,a-slot-1" {
+		t.Fatalf("unexpected gpus %v of host a", names)
+	}
+
+	// 更新后索引随之变化
+	obj, err := gpuRegistry.Get(context.TODO(), "", "a-slot-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gpu := obj.(*v1.GPU)
+	gpu.Spec.HostRef = "b"
+	if _, err := gpuRegistry.Update(context.TODO(), gpu); err != nil {
+		t.Fatal(err)
+	}
+	if names := listNames("a"); strings.Join(names, ",") != "a-slot-0" {
+		t.Fatalf("unexpected gpus %v of host a after update", names)
+	}
+	if names := listNames("b"); strings.Join(names, ",") != "a-slot-1,b-slot-0" {
+		t.Fatalf("unexpected gpus %v of host b after update", names)
+	}
+
+	// 删除后索引键同时被删除
+	if _, err := gpuRegistry.Delete(context.TODO(), "", "b-slot-0"); err != nil {
+		t.Fatal(err)
+	}
+	if names := listNames("b"); strings.Join(names, ",") != "a-slot-1" {
+		t.Fatalf("unexpected gpus %v of host b after delete", names)
+	}
+	kvList, err := db.KV.List(core.RegistryPrefix+"/indexes/", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kvList) != 2 {
+		t.Fatalf("unexpected index keys %v", kvList)
+	}
+
+	// 重建索引时补充缺失的索引键
+	for key := range kvList {
+		if _, err := db.KV.Delete(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := gpuRegistry.RebuildIndexes(); err != nil {
+		t.Fatal(err)
+	}
+	if names := listNames("a"); strings.Join(names, ",") != "a-slot-0" {
+		t.Fatalf("unexpected gpus %v of host a after rebuild", names)
+	}
+
+	// 迁移修复资源内容时同时写入索引键
+	for key := range kvList {
+		if _, err := db.KV.Delete(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	key := core.RegistryPrefix + "/gpus/a-slot-0"
+	value, err := db.KV.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.KV.Set(key, strings.Replace(value, `"Finalizers":null`, `"Finalizers":[]`, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gpuRegistry.MigrateObjects(); err != nil {
+		t.Fatal(err)
+	}
+	if names := listNames("a"); strings.Join(names, ",") != "a-slot-0" {
+		t.Fatalf("unexpected gpus %v of host a after migration", names)
+	}
+
+	if _, err := gpuRegistry.ListByIndex(context.TODO(), "Unknown", "a"); err == nil {
+		t.Fatal("expected error of unknown index")
+	}
+}
@@ -0,0 +1,337 @@
+package registry
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+// ResourceEventHandler 资源对象变更的处理方法，处理方法接收到的是缓存中对象的副本，可以直接修改
+type ResourceEventHandler struct {
+	// 资源对象被创建或更新，在开始侦听时对所有已存在的对象也会触发
+	OnSet func(obj core.ApiObject)
+
+	// 资源对象被删除
+	OnDelete func(obj core.ApiObject)
+}
+
+type informerItem struct {
+	obj core.ApiObject
+	// 缓存最后一次被侦听事件修改的时间，用于重新同步时判断缓存是否过时
+	updateTime time.Time
+}
+
+// Informer 共享的资源对象缓存。每种资源只建立一个侦听，将变更同步至本地带索引的缓存，并分发给所有注册的处理方法。
+// 缓存完成首次同步前，Get与List等读取方法直接从数据库读取
+type Informer struct {
+	registry     ApiObjectRegistry
+	resyncPeriod time.Duration
+
+	mutex   sync.RWMutex
+	items   map[string]informerItem
+	indices map[string]map[string]map[string]struct{}
+	synced  bool
+
+	handlersMutex sync.RWMutex
+	handlers      []ResourceEventHandler
+
+	runOnce sync.Once
+}
+
+// AddEventHandler 注册资源对象变更的处理方法，处理方法在侦听协程中同步调用，耗时的处理需要自行异步执行。
+// 缓存已完成同步时，会将缓存中的所有对象作为OnSet补充推送给新注册的处理方法
+func (i *Informer) AddEventHandler(handler ResourceEventHandler) {
+	// 持有处理方法的写锁获取缓存快照，保证快照之后的变更都会分发给新的处理方法
+	i.handlersMutex.Lock()
+	i.mutex.RLock()
+	existing := core.ApiObjectList{}
+	if i.synced {
+		keys := []string{}
+		for key := range i.items {
+			keys = append(keys, key)
+		}
+		existing = i.copyItems(keys)
+	}
+	i.mutex.RUnlock()
+	i.handlers = append(i.handlers, handler)
+	i.handlersMutex.Unlock()
+
+	if handler.OnSet != nil {
+		for _, obj := range existing {
+			handler.OnSet(obj)
+		}
+	}
+}
+
+// Run 开始侦听并同步缓存，直至ctx结束。重复调用时只有第一次调用生效
+func (i *Informer) Run(ctx context.Context) {
+	i.runOnce.Do(func() {
+		i.run(ctx)
+	})
+}
+
+func (i *Informer) run(ctx context.Context) {
+	gvk := i.registry.GVK()
+
+	// 首次同步缓存，失败时重试
+	for {
+		if err := i.resync(); err == nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+	log.Debugf("%+v informer synced", gvk)
+
+	watcher := i.registry.ListWatch(ctx, "")
+	ticker := time.NewTicker(i.resyncPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debugf("%+v informer stopped", gvk)
+			return
+		case objAction, ok := <-watcher:
+			if !ok {
+				if ctx.Err() != nil {
+					return
+				}
+				// 侦听通道意外关闭时重新建立侦听，重新推送的全量对象保证不会遗漏期间的变更
+				log.Warnf("%+v informer watcher closed, rewatch", gvk)
+				time.Sleep(time.Second)
+				watcher = i.registry.ListWatch(ctx, "")
+				continue
+			}
+			if objAction.Obj == nil {
+				continue
+			}
+			switch objAction.Type {
+			case db.KVActionTypeSet:
+				i.set(objAction.Obj)
+				i.dispatch(objAction.Obj, false)
+			case db.KVActionTypeDelete:
+				i.delete(objAction.Obj)
+				i.dispatch(objAction.Obj, true)
+			}
+		case <-ticker.C:
+			// 定时与数据库重新同步，清除侦听重建期间遗漏删除事件的对象
+			if err := i.resync(); err != nil {
+				log.Error(err)
+			}
+		}
+	}
+}
+
+// resync 从数据库获取所有对象，移除缓存中在获取前就已存在但数据库中已不存在的对象
+func (i *Informer) resync() error {
+	start := time.Now()
+	objs, err := i.registry.List(context.TODO(), "")
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]core.ApiObject)
+	for _, obj := range objs {
+		current[i.getObjKey(obj)] = obj
+	}
+
+	i.mutex.Lock()
+	deleted := core.ApiObjectList{}
+	for key, item := range i.items {
+		if _, ok := current[key]; !ok && item.updateTime.Before(start) {
+			deleted = append(deleted, item.obj)
+			i.unindex(key, item.obj)
+			delete(i.items, key)
+		}
+	}
+	if !i.synced {
+		for key, obj := range current {
+			i.items[key] = informerItem{obj: obj, updateTime: start}
+			i.index(key, obj)
+		}
+		i.synced = true
+	}
+	i.mutex.Unlock()
+
+	for _, obj := range deleted {
+		i.dispatch(obj, true)
+	}
+	return nil
+}
+
+func (i *Informer) set(obj core.ApiObject) {
+	key := i.getObjKey(obj)
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if item, ok := i.items[key]; ok {
+		i.unindex(key, item.obj)
+	}
+	i.items[key] = informerItem{obj: obj, updateTime: time.Now()}
+	i.index(key, obj)
+}
+
+func (i *Informer) delete(obj core.ApiObject) {
+	key := i.getObjKey(obj)
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if item, ok := i.items[key]; ok {
+		i.unindex(key, item.obj)
+		delete(i.items, key)
+	}
+}
+
+func (i *Informer) index(key string, obj core.ApiObject) {
+	for index, f := range i.registry.Indexers() {
+		if i.indices[index] == nil {
+			i.indices[index] = make(map[string]map[string]struct{})
+		}
+		for _, value := range f(obj) {
+			if i.indices[index][value] == nil {
+				i.indices[index][value] = make(map[string]struct{})
+			}
+			i.indices[index][value][key] = struct{}{}
+		}
+	}
+}
+
+func (i *Informer) unindex(key string, obj core.ApiObject) {
+	for index, f := range i.registry.Indexers() {
+		for _, value := range f(obj) {
+			delete(i.indices[index][value], key)
+			if len(i.indices[index][value]) == 0 {
+				delete(i.indices[index], value)
+			}
+		}
+	}
+}
+
+// dispatch 将变更分发给所有处理方法，每个处理方法接收独立的对象副本
+func (i *Informer) dispatch(obj core.ApiObject, deleted bool) {
+	i.handlersMutex.RLock()
+	handlers := i.handlers
+	i.handlersMutex.RUnlock()
+	for _, handler := range handlers {
+		if deleted && handler.OnDelete != nil {
+			handler.OnDelete(obj.DeepCopyApiObject())
+		} else if !deleted && handler.OnSet != nil {
+			handler.OnSet(obj.DeepCopyApiObject())
+		}
+	}
+}
+
+// HasSynced 返回缓存是否已完成首次同步
+func (i *Informer) HasSynced() bool {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return i.synced
+}
+
+// Get 从缓存中获取单个资源对象，对象不存在时返回空
+func (i *Informer) Get(ctx context.Context, namespace string, name string) (core.ApiObject, error) {
+	if !i.HasSynced() {
+		return i.registry.Get(ctx, namespace, name)
+	}
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	item, ok := i.items[i.getKey(namespace, name)]
+	if !ok {
+		return nil, nil
+	}
+	return item.obj.DeepCopyApiObject(), nil
+}
+
+// List 从缓存中列举单个命名空间下的所有资源对象，结果按命名空间与名称排序。
+// 支持core.WithLabelSelector与core.WithFieldSelector，指定了分页选项时直接从数据库读取
+func (i *Informer) List(ctx context.Context, namespace string, opts ...core.OpOpt) (core.ApiObjectList, error) {
+	var option core.Option
+	option.SetupOption(opts...)
+	if !i.HasSynced() || option.Limit > 0 || option.Continue != "" {
+		return i.registry.List(ctx, namespace, opts...)
+	}
+
+	labelSelector, err := core.ParseLabelSelector(option.LabelSelector)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	fieldSelector, err := core.ParseFieldSelector(option.FieldSelector)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	i.mutex.RLock()
+	keys := []string{}
+	for key, item := range i.items {
+		if namespace != "" && i.registry.Namespaced() && item.obj.GetMetadata().Namespace != namespace {
+			continue
+		}
+		if !labelSelector.MatchesLabels(item.obj) || !fieldSelector.MatchesFields(item.obj) {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	list := i.copyItems(keys)
+	i.mutex.RUnlock()
+	return list, nil
+}
+
+// ListByIndex 从缓存中获取索引取值为value的所有资源对象，结果按命名空间与名称排序
+func (i *Informer) ListByIndex(ctx context.Context, index string, value string) (core.ApiObjectList, error) {
+	if !i.HasSynced() {
+		return i.registry.ListByIndex(ctx, index, value)
+	}
+	if _, ok := i.registry.Indexers()[index]; !ok {
+		return i.registry.ListByIndex(ctx, index, value)
+	}
+
+	i.mutex.RLock()
+	keys := []string{}
+	for key := range i.indices[index][value] {
+		keys = append(keys, key)
+	}
+	list := i.copyItems(keys)
+	i.mutex.RUnlock()
+	return list, nil
+}
+
+// copyItems 按顺序复制缓存中的对象，调用方需持有读锁
+func (i *Informer) copyItems(keys []string) core.ApiObjectList {
+	sort.Strings(keys)
+	list := core.ApiObjectList{}
+	for _, key := range keys {
+		list = append(list, i.items[key].obj.DeepCopyApiObject())
+	}
+	return list
+}
+
+func (i *Informer) getObjKey(obj core.ApiObject) string {
+	metadata := obj.GetMetadata()
+	return i.getKey(metadata.Namespace, metadata.Name)
+}
+
+func (i *Informer) getKey(namespace string, name string) string {
+	if !i.registry.Namespaced() {
+		namespace = ""
+	}
+	return namespace + "/" + name
+}
+
+// NewInformer 创建资源对象缓存，resyncPeriod为与数据库重新同步的周期
+func NewInformer(r ApiObjectRegistry, resyncPeriod time.Duration) *Informer {
+	return &Informer{
+		registry:     r,
+		resyncPeriod: resyncPeriod,
+		items:        make(map[string]informerItem),
+		indices:      make(map[string]map[string]map[string]struct{}),
+	}
+}
@@ -0,0 +1,95 @@
+package registry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestRegistryInformer(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	gpuRegistry := v1.NewGPURegistry()
+	gpu := v1.NewGPU()
+	gpu.Metadata.Name = "a-slot-0"
+	gpu.Spec.HostRef = "a"
+	if _, err := gpuRegistry.Create(context.TODO(), gpu); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	informer := registry.NewInformer(gpuRegistry, time.Minute)
+	sets := make(chan string, 10)
+	deletes := make(chan string, 10)
+	informer.AddEventHandler(registry.ResourceEventHandler{
+		OnSet: func(obj core.ApiObject) {
+			sets <- obj.GetMetadata().Name
+		},
+		OnDelete: func(obj core.ApiObject) {
+			deletes <- obj.GetMetadata().Name
+		},
+	})
+	go informer.Run(ctx)
+
+	waitFor := func(ch chan string, name string) {
+		select {
+		case got := <-ch:
+			if got != name {
+				t.Fatalf("unexpected event of %s, expected %s", got, name)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for event of %s", name)
+		}
+	}
+	// 开始侦听时已存在的对象同样触发OnSet
+	waitFor(sets, "a-slot-0")
+	if !informer.HasSynced() {
+		t.Fatal("expected informer synced")
+	}
+
+	gpu = v1.NewGPU()
+	gpu.Metadata.Name = "b-slot-0"
+	gpu.Spec.HostRef = "b"
+	if _, err := gpuRegistry.Create(context.TODO(), gpu); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(sets, "b-slot-0")
+
+	obj, err := informer.Get(context.TODO(), "", "b-slot-0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj == nil || obj.(*v1.GPU).Spec.HostRef != "b" {
+		t.Fatalf("unexpected cached gpu %+v", obj)
+	}
+	// 读取到的是副本，修改不影响缓存
+	obj.(*v1.GPU).Spec.HostRef = "c"
+	objs, err := informer.ListByIndex(context.TODO(), core.IndexHostRef, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0].GetMetadata().Name != "b-slot-0" {
+		t.Fatalf("unexpected gpus %v of host b", objs)
+	}
+
+	if _, err := gpuRegistry.Delete(context.TODO(), "", "a-slot-0"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(deletes, "a-slot-0")
+	objs, err = informer.List(context.TODO(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 || objs[0].GetMetadata().Name != "b-slot-0" {
+		t.Fatalf("unexpected cached gpus %v", objs)
+	}
+	if objs, err := informer.ListByIndex(context.TODO(), core.IndexHostRef, "a"); err != nil || len(objs) != 0 {
+		t.Fatalf("unexpected gpus %v of host a, err: %v", objs, err)
+	}
+}
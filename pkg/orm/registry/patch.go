@@ -0,0 +1,206 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/patch"
+)
+
+// Patch 使用补丁更新单个资源对象，补丁类型为patch.TypeMergePatch、patch.TypeJSONPatch或patch.TypeApplyPatch。
+// 补丁类型为patch.TypeApplyPatch时，data为完整的资源配置，资源对象不存在时直接创建，否则与上次应用的配置及当前对象进行三方合并。
+// 补丁中未指定资源版本号时，资源冲突会自动重试，否则直接返回资源冲突错误
+func (r Registry) Patch(ctx context.Context, namespace string, name string, patchType string, data []byte, opts ...core.OpOpt) (core.ApiObject, error) {
+	var result core.ApiObject
+	patchFunc := func() error {
+		// 补丁基于存储中的原始内容计算，避免装饰后的内容被写回存储
+		current, err := r.Get(ctx, namespace, name, core.WithoutDecorate())
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			if patchType == patch.TypeApplyPatch {
+				result, err = r.applyCreate(ctx, namespace, name, data, opts...)
+			}
+			return err
+		}
+
+		obj, err := r.applyPatch(current, patchType, data)
+		if err != nil {
+			return err
+		}
+		metadata := obj.GetMetadata()
+		metadata.Namespace = namespace
+		metadata.Name = name
+		if metadata.ResourceVersion == 0 {
+			metadata.ResourceVersion = current.GetMetadata().ResourceVersion
+		}
+		obj.SetMetadata(metadata)
+
+		// 补丁生成的是完整的Spec，需要替换而非合并原有的Spec，使补丁中删除的字段生效
+		result, err = r.Update(ctx, obj, append(opts, core.WithReplaceSpec())...)
+		return err
+	}
+
+	var err error
+	if hasResourceVersion(patchType, data) {
+		err = patchFunc()
+	} else {
+		err = RetryOnConflict(patchFunc)
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// hasResourceVersion 判断补丁中是否指定了资源版本号
+func hasResourceVersion(patchType string, data []byte) bool {
+	if patchType == patch.TypeJSONPatch {
+		ops := []struct {
+			Op   string
+			Path string
+		}{}
+		if err := json.Unmarshal(data, &ops); err != nil {
+			return false
+		}
+		for _, op := range ops {
+			if op.Op != "remove" && strings.EqualFold(op.Path, "/Metadata/ResourceVersion") {
+				return true
+			}
+		}
+		return false
+	}
+
+	content := struct {
+		Metadata struct {
+			ResourceVersion int
+		}
+	}{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return false
+	}
+	return content.Metadata.ResourceVersion != 0
+}
+
+// applyPatch 将补丁应用于当前对象，返回修改后的新对象
+func (r Registry) applyPatch(current core.ApiObject, patchType string, data []byte) (core.ApiObject, error) {
+	currentData, err := current.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	var lastApplied []byte
+	switch patchType {
+	case patch.TypeMergePatch:
+		patched, err = patch.MergePatch(currentData, data)
+	case patch.TypeJSONPatch:
+		patched, err = patch.JSONPatch(currentData, data)
+	case patch.TypeApplyPatch:
+		if lastApplied, err = lastAppliedConfiguration(data); err != nil {
+			break
+		}
+		original := current.GetMetadata().Annotations[core.AnnotationLastAppliedConfiguration]
+		// 旧版本在该注解中记录的是Spec的哈希值，不是JSON对象时视为没有上次应用的配置
+		if content, err := unmarshalContent(original); err != nil {
+			original = ""
+		} else if _, ok := content.(map[string]interface{}); !ok {
+			original = ""
+		}
+		var mergePatch []byte
+		if mergePatch, err = patch.CreateThreeWayMergePatch([]byte(original), lastApplied, currentData); err != nil {
+			break
+		}
+		patched, err = patch.MergePatch(currentData, mergePatch)
+	default:
+		return nil, e.UnsupportedPatchTypeError{PatchType: patchType}
+	}
+	if err != nil {
+		return nil, e.InvalidPatchError{Reason: err.Error()}
+	}
+
+	obj, err := r.NewObject(patched)
+	if err != nil {
+		return nil, e.InvalidPatchError{Reason: err.Error()}
+	}
+	if lastApplied != nil {
+		setLastAppliedConfiguration(obj, lastApplied)
+	}
+	return obj, nil
+}
+
+// applyCreate 使用完整的资源配置创建资源对象，并记录为上次应用的配置
+func (r Registry) applyCreate(ctx context.Context, namespace string, name string, data []byte, opts ...core.OpOpt) (core.ApiObject, error) {
+	lastApplied, err := lastAppliedConfiguration(data)
+	if err != nil {
+		return nil, e.InvalidPatchError{Reason: err.Error()}
+	}
+	obj, err := r.NewObject(data)
+	if err != nil {
+		return nil, e.InvalidPatchError{Reason: err.Error()}
+	}
+	metadata := obj.GetMetadata()
+	metadata.Namespace = namespace
+	metadata.Name = name
+	obj.SetMetadata(metadata)
+	setLastAppliedConfiguration(obj, lastApplied)
+	return r.Create(ctx, obj, opts...)
+}
+
+// NewObject 将序列化数据解析为当前存储器所对应结构版本的资源对象，数据为其他结构版本时进行结构转换
+func (r Registry) NewObject(data []byte) (core.ApiObject, error) {
+	metaType := new(core.MetaType)
+	if err := json.Unmarshal(data, metaType); err != nil {
+		return nil, err
+	}
+	if metaType.ApiVersion != "" && metaType.ApiVersion != r.gvk.ApiVersion {
+		return convertByBytes(data, r.gvk)
+	}
+	obj, err := newByGVK(r.gvk)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	obj.SetGVK(r.gvk)
+	return obj, nil
+}
+
+// lastAppliedConfiguration 去除资源配置中由服务端维护的字段，得到用于记录的上次应用的配置
+func lastAppliedConfiguration(data []byte) ([]byte, error) {
+	value, err := unmarshalContent(string(data))
+	if err != nil {
+		return nil, err
+	}
+	content, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, e.Errorf("apply configuration must be an object")
+	}
+	delete(content, "Status")
+	if metadata, ok := content["Metadata"].(map[string]interface{}); ok {
+		for _, field := range []string{"Uid", "ResourceVersion", "CreateTime", "UpdateTime", "Finalizers"} {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["Annotations"].(map[string]interface{}); ok {
+			delete(annotations, core.AnnotationLastAppliedConfiguration)
+		}
+	}
+	return json.Marshal(content)
+}
+
+func setLastAppliedConfiguration(obj core.ApiObject, lastApplied []byte) {
+	metadata := obj.GetMetadata()
+	if metadata.Annotations == nil {
+		metadata.Annotations = make(map[string]string)
+	}
+	metadata.Annotations[core.AnnotationLastAppliedConfiguration] = string(lastApplied)
+	obj.SetMetadata(metadata)
+}
@@ -0,0 +1,156 @@
+package registry_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/patch"
+)
+
+func TestRegistryPatch(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	getData := func() map[string]string {
+		obj, err := configMapRegistry.Get(context.TODO(), "default", "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return obj.(*v1.ConfigMap).Data
+	}
+
+	// 应用的资源不存在时创建
+	applied := `{"Metadata":{"Namespace":"default","Name":"test"},"Data":{"a":"1","b":"2"}}`
+	if _, err := configMapRegistry.Patch(context.TODO(), "default", "test", patch.TypeApplyPatch, []byte(applied)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 其他方式写入的字段在再次应用时保留，上次应用的配置中被移除的字段被删除
+	if _, err := configMapRegistry.Patch(context.TODO(), "default", "test", patch.TypeMergePatch, []byte(`{"Data":{"c":"3"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	applied = `{"Metadata":{"Namespace":"default","Name":"test"},"Data":{"a":"1"}}`
+	if _, err := configMapRegistry.Patch(context.TODO(), "default", "test", patch.TypeApplyPatch, []byte(applied)); err != nil {
+		t.Fatal(err)
+	}
+	if data := getData(); len(data) != 2 || data["a"] != "1" || data["c"] != "3" {
+		t.Fatalf("unexpected data after apply: %v", data)
+	}
+
+	if _, err := configMapRegistry.Patch(context.TODO(), "default", "test", patch.TypeJSONPatch, []byte(`[{"op":"replace","path":"/Data/a","value":"9"},{"op":"remove","path":"/Data/c"}]`)); err != nil {
+		t.Fatal(err)
+	}
+	if data := getData(); len(data) != 1 || data["a"] != "9" {
+		t.Fatalf("unexpected data after json patch: %v", data)
+	}
+
+	// 测试操作失败时不修改资源
+	if _, err := configMapRegistry.Patch(context.TODO(), "default", "test", patch.TypeJSONPatch, []byte(`[{"op":"test","path":"/Data/a","value":"1"}]`)); err == nil {
+		t.Fatal("failed test operation should fail")
+	} else if _, ok := err.(e.InvalidPatchError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 补丁中指定了过期的资源版本号时直接返回冲突，不进行重试
+	for _, body := range []struct {
+		patchType string
+		data      string
+	}{
+		{patch.TypeMergePatch, `{"Metadata":{"ResourceVersion":1},"Data":{"a":"2"}}`},
+		{patch.TypeJSONPatch, `[{"op":"replace","path":"/Metadata/ResourceVersion","value":1},{"op":"replace","path":"/Data/a","value":"2"}]`},
+	} {
+		start := time.Now()
+		if _, err := configMapRegistry.Patch(context.TODO(), "default", "test", body.patchType, []byte(body.data)); err == nil {
+			t.Fatal("patch with stale resource version should fail")
+		} else if _, ok := err.(e.ResourceConflictError); !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Fatalf("conflict should not be retried, took %s", elapsed)
+		}
+	}
+	if data := getData(); data["a"] != "9" {
+		t.Fatalf("unexpected data after conflict: %v", data)
+	}
+
+	// 不存在的资源无法使用补丁修改
+	if obj, err := configMapRegistry.Patch(context.TODO(), "default", "notfound", patch.TypeMergePatch, []byte(`{}`)); err != nil || obj != nil {
+		t.Fatalf("unexpected result: %v, %v", obj, err)
+	}
+}
+
+func TestRegistryApplyLegacyAnnotation(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	// 旧版本在上次应用的配置注解中记录的是Spec的哈希值，应用时视为没有上次应用的配置
+	configMapRegistry := v1.NewConfigMapRegistry()
+	for index, hash := range []string{
+		"a3f1c2e4b5d6a7f8e9d0c1b2a3f4e5d6c7b8a9f0e1d2c3b4a5f6e7d8c9b0a1f2",
+		"3af1c2e4b5d6a7f8e9d0c1b2a3f4e5d6c7b8a9f0e1d2c3b4a5f6e7d8c9b0a1f2",
+	} {
+		name := fmt.Sprintf("legacy%d", index)
+		configMap := v1.NewConfigMap()
+		configMap.Metadata.Namespace = "default"
+		configMap.Metadata.Name = name
+		configMap.Metadata.Annotations = map[string]string{core.AnnotationLastAppliedConfiguration: hash}
+		configMap.Data = map[string]string{"a": "1", "b": "2"}
+		if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+			t.Fatal(err)
+		}
+
+		applied := `{"Metadata":{"Namespace":"default"},"Data":{"a":"1","b":"2"}}`
+		if _, err := configMapRegistry.Patch(context.TODO(), "default", name, patch.TypeApplyPatch, []byte(applied)); err != nil {
+			t.Fatalf("apply to object with legacy annotation %s: %v", hash, err)
+		}
+		// 再次应用时根据新记录的配置删除被移除的字段
+		applied = `{"Metadata":{"Namespace":"default"},"Data":{"a":"1"}}`
+		obj, err := configMapRegistry.Patch(context.TODO(), "default", name, patch.TypeApplyPatch, []byte(applied))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data := obj.(*v1.ConfigMap).Data; len(data) != 1 || data["a"] != "1" {
+			t.Fatalf("unexpected data after apply: %v", data)
+		}
+	}
+}
+
+func TestRegistryUpdateMergesSpec(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	configMap := v1.NewConfigMap()
+	configMap.Metadata.Namespace = "default"
+	configMap.Metadata.Name = "test"
+	configMap.Data = map[string]string{"a": "1", "b": "2"}
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	// 更新时新的Spec合并至原有的Spec中，未指定的字段保持不变
+	update := v1.NewConfigMap()
+	update.Metadata.Namespace = "default"
+	update.Metadata.Name = "test"
+	update.Data = map[string]string{"a": "3"}
+	obj, err := configMapRegistry.Update(context.TODO(), update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := obj.(*v1.ConfigMap).Data; len(data) != 2 || data["a"] != "3" || data["b"] != "2" {
+		t.Fatalf("unexpected data after update: %v", data)
+	}
+
+	// 补丁替换原有的Spec，补丁中删除的字段被移除
+	obj, err = configMapRegistry.Patch(context.TODO(), "default", "test", patch.TypeMergePatch, []byte(`{"Data":{"b":null}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data := obj.(*v1.ConfigMap).Data; len(data) != 1 || data["a"] != "3" {
+		t.Fatalf("unexpected data after patch: %v", data)
+	}
+}
@@ -0,0 +1,113 @@
+package registry
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+// redactedValue 敏感字段被隐藏后的取值
+const redactedValue = "******"
+
+// Redact 隐藏序列化数据中的敏感字段，包括需要加密存储的字段与额外设置的需要隐藏的字段，用于审计等需要记录请求与响应内容的场景。
+// 序列化数据可以是资源对象、合并补丁或JSON补丁，obj用于计算敏感字段的路径，为空时只使用固定的字段路径
+func (r Registry) Redact(obj core.ApiObject, data []byte) (string, error) {
+	fields := append(append([]string{}, r.encryptedFields...), r.redactedFields...)
+	if r.encryptedFieldsFunc != nil && obj != nil {
+		fields = append(fields, r.encryptedFieldsFunc(obj)...)
+	}
+	if len(fields) == 0 {
+		return string(data), nil
+	}
+	paths := [][]string{{"Metadata", "Annotations", core.AnnotationLastAppliedConfiguration}}
+	for _, field := range fields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+
+	content, err := unmarshalContent(string(data))
+	if err != nil {
+		return "", err
+	}
+	if ops, ok := content.([]interface{}); ok {
+		// JSON补丁中隐藏写入到敏感字段的值
+		for _, op := range ops {
+			if op, ok := op.(map[string]interface{}); ok {
+				redactPatchOp(op, paths)
+			}
+		}
+	} else {
+		for _, path := range paths {
+			content = redactPath(content, path)
+		}
+	}
+	result, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// redactPatchOp 隐藏JSON补丁操作中写入到敏感字段的值
+func redactPatchOp(op map[string]interface{}, paths [][]string) {
+	value, ok := op["value"]
+	if !ok {
+		return
+	}
+	opPath, _ := op["path"].(string)
+	segments := []string{}
+	if opPath != "" {
+		for _, segment := range strings.Split(strings.TrimPrefix(opPath, "/"), "/") {
+			segments = append(segments, strings.NewReplacer("~1", "/", "~0", "~").Replace(segment))
+		}
+	}
+	for _, path := range paths {
+		if !matchPath(segments, path) {
+			continue
+		}
+		if len(segments) >= len(path) {
+			// 操作的目标位于敏感字段内部
+			op["value"] = redactedValue
+			return
+		}
+		value = redactPath(value, path[len(segments):])
+	}
+	op["value"] = value
+}
+
+// matchPath 判断两个路径在公共长度内是否一致，*匹配任意的键或数组下标
+func matchPath(segments []string, path []string) bool {
+	for i := 0; i < len(segments) && i < len(path); i++ {
+		if path[i] != "*" && !strings.EqualFold(path[i], segments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// redactPath 隐藏路径所指向的非空字段，字段名不区分大小写，路径中的*匹配字典的所有键或数组的所有元素
+func redactPath(content interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		if content == nil || content == "" {
+			return content
+		}
+		return redactedValue
+	}
+
+	switch value := content.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if path[0] == "*" || strings.EqualFold(path[0], key) {
+				value[key] = redactPath(child, path[1:])
+			}
+		}
+	case []interface{}:
+		for index, child := range value {
+			if matchPath([]string{strconv.Itoa(index)}, path[:1]) {
+				value[index] = redactPath(child, path[1:])
+			}
+		}
+	}
+	return content
+}
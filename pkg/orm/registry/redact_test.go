@@ -0,0 +1,57 @@
+package registry_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+func TestRegistryRedact(t *testing.T) {
+	hostRegistry := v1.NewHostRegistry()
+	host := v1.NewHost()
+	host.Metadata.Name = "test"
+	host.Spec.SSH.User = "root"
+	host.Spec.SSH.Password = "secret"
+	data, err := host.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range [][]byte{
+		data,
+		[]byte(`{"spec":{"ssh":{"password":"secret"}}}`),
+		[]byte(`[{"op":"replace","path":"/Spec/SSH/Password","value":"secret"}]`),
+		[]byte(`[{"op":"replace","path":"/Spec/SSH","value":{"User":"root","Password":"secret"}}]`),
+	} {
+		redacted, err := hostRegistry.Redact(host, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(redacted, "secret") || !strings.Contains(redacted, "******") {
+			t.Fatalf("password not redacted: %s", redacted)
+		}
+	}
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	redacted, err := configMapRegistry.Redact(nil, []byte(`[{"op":"add","path":"/Data/password","value":"secret"},{"op":"add","path":"/Metadata/Labels/app","value":"test"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(redacted, "secret") || !strings.Contains(redacted, `"test"`) {
+		t.Fatalf("unexpected redacted patch: %s", redacted)
+	}
+
+	userRegistry := v1.NewUserRegistry()
+	user := v1.NewUser()
+	user.Spec.Password = "secret"
+	data, err = user.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redacted, err := userRegistry.Redact(user, data); err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(redacted, "secret") {
+		t.Fatalf("password not redacted: %s", redacted)
+	}
+}
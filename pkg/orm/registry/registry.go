@@ -18,6 +18,12 @@ import (
 // HookFunc 钩子方法定义
 type HookFunc func(obj core.ApiObject) error
 
+// MergeHookFunc 更新合并钩子方法定义，oldObj为存储中的原始对象
+type MergeHookFunc func(obj core.ApiObject, oldObj core.ApiObject) error
+
+// TTLFunc 资源存活时间计算方法，返回值大于0时资源会在写入后经过该时长自动删除
+type TTLFunc func(obj core.ApiObject) time.Duration
+
 // ApiObjectRegistry 资源对象存储器接口，实现了该接口的对象可对资源对象进行数据库读写
 type ApiObjectRegistry interface {
 	// 写入一条新记录
@@ -26,6 +32,9 @@ type ApiObjectRegistry interface {
 	// 更新一条已存在的记录
 	Update(ctx context.Context, obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error)
 
+	// 使用补丁更新一条已存在的记录，应用完整配置时记录不存在则创建
+	Patch(ctx context.Context, namespace string, name string, patchType string, data []byte, opts ...core.OpOpt) (core.ApiObject, error)
+
 	// 删除一条已存在的记录
 	Delete(ctx context.Context, namespace string, name string, opts ...core.OpOpt) (core.ApiObject, error)
 
@@ -35,12 +44,45 @@ type ApiObjectRegistry interface {
 	// 获取所有记录
 	List(ctx context.Context, namespace string, opts ...core.OpOpt) (core.ApiObjectList, error)
 
+	// 分页获取记录，并返回用于获取下一页的令牌与读取时存储的修订版本号
+	ListPage(ctx context.Context, namespace string, opts ...core.OpOpt) (core.ApiObjectList, string, int64, error)
+
 	// 获取并监听所有记录的变更
 	ListWatch(ctx context.Context, namespace string) <-chan core.ApiObjectAction
 
+	// 获取并监听一条已存在的记录的变更，记录不存在时返回空
+	GetWatch(ctx context.Context, namespace string, name string) <-chan core.ApiObjectAction
+
+	// 从指定修订版本之后开始监听记录的变更，名称为空时监听所有记录
+	WatchFrom(ctx context.Context, namespace string, name string, revision int64) (<-chan core.ApiObjectAction, error)
+
 	// 将其他结构版本的记录转换成当前版本的结构并写入数据库
 	MigrateObjects() error
 
+	// 使用当前的主密钥重新加密所有记录
+	EncryptObjects() error
+
+	// 获取索引取值与value一致的所有记录
+	ListByIndex(ctx context.Context, index string, value string) (core.ApiObjectList, error)
+
+	// 根据当前的记录重建所有索引
+	RebuildIndexes() error
+
+	// 获取所有索引名称与索引计算方法
+	Indexers() map[string]IndexFunc
+
+	// 只更新一条已存在的记录的状态
+	UpdateStatus(namespace string, name string, status core.Status, opts ...core.OpOpt) (core.ApiObject, error)
+
+	// 获取修订历史记录器，未启用修订历史时返回空
+	Revisioner() Revisioner
+
+	// 将序列化数据解析为当前结构版本的记录
+	NewObject(data []byte) (core.ApiObject, error)
+
+	// 隐藏资源对象或补丁的序列化数据中的敏感字段
+	Redact(obj core.ApiObject, data []byte) (string, error)
+
 	// 返回当前存储器对应的资源是否属于命名空间资源
 	Namespaced() bool
 
@@ -71,6 +113,9 @@ type Registry struct {
 	// 更新前置钩子
 	preUpdateHook HookFunc
 
+	// 更新合并钩子
+	mergeHook MergeHookFunc
+
 	// 删除前置钩子
 	preDeleteHook HookFunc
 
@@ -88,6 +133,21 @@ type Registry struct {
 
 	// 默认finalizers
 	defaultFinalizers []string
+
+	// 资源默认存活时间的计算方法
+	ttlFunc TTLFunc
+
+	// 需要加密存储的字段路径
+	encryptedFields []string
+
+	// 根据资源内容计算需要加密存储的字段路径的方法
+	encryptedFieldsFunc FieldPathsFunc
+
+	// 审计时需要额外隐藏的字段路径
+	redactedFields []string
+
+	// 索引名称与索引计算方法
+	indexers map[string]IndexFunc
 }
 
 // Create 创建单个资源对象
@@ -99,6 +159,33 @@ func (r Registry) createWithOpts(ctx context.Context, obj core.ApiObject, opts .
 	var option core.Option
 	option.SetupOption(opts...)
 
+	ops, err := r.prepareCreate(ctx, obj, option)
+	if err != nil {
+		return nil, err
+	}
+	if option.DryRun {
+		return obj, nil
+	}
+
+	// 创建对象，写入时确保对象仍不存在
+	if ok, err := db.KV.Txn(ops...); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, e.ResourceExistsError{Key: obj.GetKey()}
+	}
+
+	// 执行后置钩子
+	if r.postCreateHook != nil {
+		if err := r.postCreateHook(obj); err != nil {
+			return nil, err
+		}
+	}
+	log.Tracef("created %s: %s", obj.GetKey(), ops[0].Value)
+	return obj, nil
+}
+
+// prepareCreate 执行创建前的校验与前置钩子，返回待提交的写入操作，第一项为资源对象的写入，其余为索引键的写入
+func (r Registry) prepareCreate(ctx context.Context, obj core.ApiObject, option core.Option) ([]db.KVOp, error) {
 	// 通用校验
 	if err := r.commonValidate(obj); err != nil {
 		return nil, err
@@ -137,10 +224,17 @@ func (r Registry) createWithOpts(ctx context.Context, obj core.ApiObject, opts .
 	metadata.Uid = uuid.New().String()
 	metadata.CreateTime = time.Now()
 	metadata.UpdateTime = metadata.CreateTime
+	// 资源版本号从1开始，0保留用于表示更新时不校验资源版本号
+	metadata.ResourceVersion = 1
 	metadata.Finalizers = r.defaultFinalizers
 	obj.SetMetadata(metadata)
 	obj.SetStatus(core.NewStatus())
 
+	// 执行准入控制
+	if err := r.admit(ctx, core.AdmissionOperationCreate, obj, nil); err != nil {
+		return nil, err
+	}
+
 	// 执行前置钩子
 	if r.preCreateHook != nil {
 		if err := r.preCreateHook(obj); err != nil {
@@ -148,24 +242,12 @@ func (r Registry) createWithOpts(ctx context.Context, obj core.ApiObject, opts .
 		}
 	}
 
-	data, err := json.Marshal(obj)
+	data, err := r.encode(obj)
 	if err != nil {
 		return nil, err
 	}
-
-	// 创建对象
-	if err := db.KV.Set(key, string(data)); err != nil {
-		return nil, err
-	}
-
-	// 执行后置钩子
-	if r.postCreateHook != nil {
-		if err := r.postCreateHook(obj); err != nil {
-			return nil, err
-		}
-	}
-	log.Tracef("created %s: %s", obj.GetKey(), string(data))
-	return obj, nil
+	ops := []db.KVOp{db.OpCompareAndSet(key, data, 0).WithTTL(r.getTTL(obj))}
+	return append(ops, r.indexOps(key, nil, obj)...), nil
 }
 
 // Update 更新单个资源对象
@@ -177,29 +259,62 @@ func (r Registry) updateWithOpts(ctx context.Context, obj core.ApiObject, opts .
 	var option core.Option
 	option.SetupOption(opts...)
 
+	result, ops, revision, err := r.prepareUpdate(ctx, obj, option)
+	if err != nil {
+		return nil, err
+	}
+	if ops == nil || option.DryRun {
+		return result, nil
+	}
+
+	// 更新对象，写入时确保对象在读取后未被修改
+	if ok, err := db.KV.Txn(ops...); err != nil {
+		return nil, err
+	} else if !ok {
+		err := e.ResourceConflictError{Key: ops[0].Key}
+		log.Error(err)
+		return nil, err
+	}
+
+	// 更新提交成功后才生成历史修订版本
+	r.saveRevision(ctx, revision)
+
+	// 执行后置钩子
+	if r.postUpdateHook != nil {
+		if err := r.postUpdateHook(obj); err != nil {
+			return nil, err
+		}
+	}
+	log.Tracef("updated %s: %s", ops[0].Key, ops[0].Value)
+	return obj, nil
+}
+
+// prepareUpdate 执行更新前的校验与前置钩子，返回待提交的写入操作，第一项为资源对象的写入，其余为索引键的写入。当资源无需更新时，写入操作为空。
+// Spec发生变化时同时返回更新前的对象，由调用方在提交成功后保存为历史修订版本
+func (r Registry) prepareUpdate(ctx context.Context, obj core.ApiObject, option core.Option) (core.ApiObject, []db.KVOp, core.ApiObject, error) {
 	// 通用校验
 	if err := r.commonValidate(obj); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	// 执行自定义内容校验钩子
 	if r.validateHook != nil {
 		if err := r.validateHook(obj); err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	// 执行自定义内容填充钩子
 	if r.mutateHook != nil {
 		if err := r.mutateHook(obj); err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	// 执行更新前置钩子
 	if r.preUpdateHook != nil {
 		if err := r.preUpdateHook(obj); err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
@@ -209,16 +324,23 @@ func (r Registry) updateWithOpts(ctx context.Context, obj core.ApiObject, opts .
 	key := r.getKey(metadata.Namespace, metadata.Name)
 
 	// 获取并判断对象是否存在
-	oldObj, err := r.Get(context.TODO(), metadata.Namespace, metadata.Name)
+	oldObj, modRevision, err := r.getWithRevision(metadata.Namespace, metadata.Name)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	if oldObj == nil {
-		return nil, e.Errorf("update failed: %s not found", key)
+		return nil, nil, nil, e.Errorf("update failed: %s not found", key)
 	}
 
-	// 更新或重置元数据
+	// 指定了资源版本号时，要求与当前存储的资源版本号一致，避免覆盖他人的修改
 	oldMetadata := oldObj.GetMetadata()
+	if metadata.ResourceVersion != 0 && metadata.ResourceVersion != oldMetadata.ResourceVersion {
+		err := e.ResourceConflictError{Key: key}
+		log.Error(err)
+		return nil, nil, nil, err
+	}
+
+	// 更新或重置元数据
 	if !r.namespaced {
 		metadata.Namespace = ""
 	}
@@ -232,36 +354,48 @@ func (r Registry) updateWithOpts(ctx context.Context, obj core.ApiObject, opts .
 		// 仅更新Spec
 		spec, err := obj.SpecEncode()
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 		if err := core.DeepCopy(oldObj, obj); err != nil {
-			return nil, err
+			return nil, nil, nil, err
+		}
+		// 启用ReplaceSpec选项时先清空原有的Spec，使新的Spec中不存在的字段被移除
+		if resetter, ok := obj.(core.SpecResetter); ok && option.ReplaceSpec {
+			resetter.SpecReset()
 		}
 		if err := obj.SpecDecode(spec); err != nil {
-			return nil, err
+			return nil, nil, nil, err
+		}
+	}
+
+	// 执行更新合并钩子
+	if r.mergeHook != nil {
+		if err := r.mergeHook(obj, oldObj); err != nil {
+			return nil, nil, nil, err
 		}
 	}
 
+	// 执行准入控制，准入控制器可以修改Spec、标签与注解
+	obj.SetMetadata(metadata)
+	if err := r.admit(ctx, core.AdmissionOperationUpdate, obj, oldObj); err != nil {
+		return nil, nil, nil, err
+	}
+	metadata = core.Metadata{}
+	obj.GetMetadata().CopyTo(&metadata)
+
+	var revision core.ApiObject
 	oldSpec := oldObj.SpecHash()
 	if obj.SpecHash() != oldSpec {
-		// 资源内容体发生更新时累加资源版本号, 并将资源状态置为等待中
-		metadata.Annotations[core.AnnotationLastAppliedConfiguration] = string(oldSpec)
-		metadata.ResourceVersion++
-
-		// 启用WithAllFields选项时才可以更新Status.Phase，否则会被重置
+		// 资源内容体发生更新时将资源状态置为等待中，启用WithAllFields选项时才可以更新Status.Phase，否则会被重置
 		if !option.WithAllFields {
 			obj.SetStatusPhase(core.PhaseWaiting)
 		}
-
-		// 生成历史修订版本
-		if r.revisioner != nil {
-			if err := r.revisioner.SetRevision(ctx, oldObj); err != nil {
-				return nil, err
-			}
-		}
+		revision = oldObj
 	} else if option.WhenSpecChanged {
-		return oldObj, nil
+		return oldObj, nil, nil, nil
 	}
+	// 每次写入均累加资源版本号，使基于过期内容的并发写入（包括状态，标签与注解的写入）能够被发现
+	metadata.ResourceVersion++
 
 	// 当没有指定WithFinalizer和WithAllFields中的其中一个选项时，重置Metadata.Finalizer
 	if !option.WithFinalizer && !option.WithAllFields {
@@ -275,24 +409,23 @@ func (r Registry) updateWithOpts(ctx context.Context, obj core.ApiObject, opts .
 
 	obj.SetMetadata(metadata)
 
-	data, err := json.Marshal(obj)
+	data, err := r.encode(obj)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	// 更新对象
-	if err := db.KV.Set(key, string(data)); err != nil {
-		return nil, err
-	}
+	ops := []db.KVOp{db.OpCompareAndSet(key, data, modRevision).WithTTL(r.getTTL(obj))}
+	return obj, append(ops, r.indexOps(key, oldObj, obj)...), revision, nil
+}
 
-	// 执行后置钩子
-	if r.postUpdateHook != nil {
-		if err := r.postUpdateHook(obj); err != nil {
-			return nil, err
-		}
+// saveRevision 将更新前的对象保存为历史修订版本，对象已写入存储，因此保存失败时只记录错误
+func (r Registry) saveRevision(ctx context.Context, obj core.ApiObject) {
+	if r.revisioner == nil || obj == nil {
+		return
+	}
+	if err := r.revisioner.SetRevision(ctx, obj); err != nil {
+		log.Error(err)
 	}
-	log.Tracef("updated %s: %s", key, string(data))
-	return obj, nil
 }
 
 // Delete 删除单个资源对象
@@ -304,48 +437,21 @@ func (r Registry) deleteWithOpts(ctx context.Context, namespace string, name str
 	var option core.Option
 	option.SetupOption(opts...)
 
-	// 字段校验
-	re := regexp.MustCompile(core.ValidNameRegex)
-	if r.namespaced && !re.MatchString(namespace) {
-		err := e.InvalidNamespaceError{Namespace: namespace}
-		log.Error(err)
-		return nil, err
-	}
-	if !re.MatchString(name) {
-		err := e.InvalidNameError{Name: name}
-		log.Error(err)
-		return nil, err
-	}
-
-	// 获取存储键
-	key := r.getKey(namespace, name)
-
-	// 获取并判断对象是否存在
-	obj, err := r.Get(context.TODO(), namespace, name)
+	obj, ops, err := r.prepareDelete(ctx, namespace, name, option)
 	if err != nil {
 		return nil, err
 	}
-	if obj == nil {
-		return nil, nil
+	if obj == nil || option.DryRun {
+		return obj, nil
 	}
 
-	// 执行前置钩子
-	if r.preDeleteHook != nil {
-		if err := r.preDeleteHook(obj); err != nil {
-			return nil, err
-		}
-	}
-
-	if len(obj.GetMetadata().Finalizers) > 0 {
-		// 将对象置为删除中状态
-		if _, err := r.UpdateStatusPhase(namespace, name, core.PhaseDeleting); err != nil {
-			return nil, err
-		}
-	} else {
-		// 删除对象
-		if _, err := db.KV.Delete(key); err != nil {
-			return nil, err
-		}
+	// 删除对象或将对象置为删除中状态
+	if ok, err := db.KV.Txn(ops...); err != nil {
+		return nil, err
+	} else if !ok {
+		err := e.ResourceConflictError{Key: ops[0].Key}
+		log.Error(err)
+		return nil, err
 	}
 
 	// 执行后置钩子
@@ -379,6 +485,90 @@ func (r Registry) deleteWithOpts(ctx context.Context, namespace string, name str
 	return obj, nil
 }
 
+// prepareDelete 执行删除前的校验与前置钩子，返回待提交的写入操作，第一项为资源对象的写入，其余为索引键的写入。当对象不存在时返回的对象为空
+func (r Registry) prepareDelete(ctx context.Context, namespace string, name string, option core.Option) (core.ApiObject, []db.KVOp, error) {
+	// 字段校验
+	re := regexp.MustCompile(core.ValidNameRegex)
+	if r.namespaced && !re.MatchString(namespace) {
+		err := e.InvalidNamespaceError{Namespace: namespace}
+		log.Error(err)
+		return nil, nil, err
+	}
+	if !re.MatchString(name) {
+		err := e.InvalidNameError{Name: name}
+		log.Error(err)
+		return nil, nil, err
+	}
+
+	// 获取存储键
+	key := r.getKey(namespace, name)
+
+	// 获取并判断对象是否存在
+	obj, modRevision, err := r.getWithRevision(namespace, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if obj == nil {
+		return nil, nil, nil
+	}
+
+	// 执行准入控制
+	if err := r.admit(ctx, core.AdmissionOperationDelete, nil, obj); err != nil {
+		return nil, nil, err
+	}
+
+	// 执行前置钩子
+	if r.preDeleteHook != nil {
+		if err := r.preDeleteHook(obj); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// 前台删除与保留依赖对象时，添加由垃圾回收器处理的finalizer，在处理完依赖对象后才继续删除
+	finalizers := obj.GetMetadata().Finalizers
+	var gcFinalizer string
+	switch option.PropagationPolicy {
+	case "", core.PropagationPolicyBackground:
+	case core.PropagationPolicyForeground:
+		gcFinalizer = core.FinalizerForegroundDeletion
+	case core.PropagationPolicyOrphan:
+		gcFinalizer = core.FinalizerOrphanDependents
+	default:
+		err := e.InvalidPropagationPolicyError{Policy: option.PropagationPolicy}
+		log.Error(err)
+		return nil, nil, err
+	}
+	if gcFinalizer != "" && (len(finalizers) == 0 || finalizers[0] != gcFinalizer) {
+		finalizers = append([]string{gcFinalizer}, finalizers...)
+	}
+
+	if len(finalizers) == 0 {
+		ops := []db.KVOp{db.OpCompareAndDelete(key, modRevision)}
+		return obj, append(ops, r.indexOps(key, obj, nil)...), nil
+	}
+
+	// 存在finalizers时将对象置为删除中状态，由对应的控制器完成清理后再删除
+	deleting, err := newByGVK(r.gvk)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := core.DeepCopy(obj, deleting); err != nil {
+		return nil, nil, err
+	}
+	metadata := deleting.GetMetadata()
+	metadata.Finalizers = finalizers
+	metadata.ResourceVersion++
+	deleting.SetMetadata(metadata)
+	deleting.SetUpdateTime(time.Now())
+	deleting.SetStatusPhase(core.PhaseDeleting)
+	data, err := r.encode(deleting)
+	if err != nil {
+		return nil, nil, err
+	}
+	ops := []db.KVOp{db.OpCompareAndSet(key, data, modRevision)}
+	return obj, append(ops, r.indexOps(key, obj, deleting)...), nil
+}
+
 // Get 获取单个资源对象
 func (r Registry) Get(ctx context.Context, namespace string, name string, opts ...core.OpOpt) (core.ApiObject, error) {
 	return r.getWithOpts(ctx, namespace, name, opts...)
@@ -401,59 +591,84 @@ func (r Registry) getWithOpts(ctx context.Context, namespace string, name string
 		return nil, err
 	}
 
+	obj, _, err := r.getWithRevision(namespace, name)
+	if err != nil || obj == nil || option.WithoutDecorate {
+		return obj, err
+	}
+	if err := r.decorate(obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// getWithRevision 获取单个资源对象及其在存储中的修订版本号
+func (r Registry) getWithRevision(namespace string, name string) (core.ApiObject, int64, error) {
 	// 获取存储键
 	key := r.getKey(namespace, name)
 
 	// 获取对象
-	str, err := db.KV.Get(key)
+	str, modRevision, err := db.KV.GetWithRevision(key)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if str == "" {
-		return nil, nil
+		return nil, 0, nil
 	}
 
-	// 判断对象版本
-	metaType := new(core.MetaType)
-	if err := json.Unmarshal([]byte(str), metaType); err != nil {
-		return nil, err
-	}
-	var obj core.ApiObject
 	// 解析对象
-	if metaType.ApiVersion != r.gvk.ApiVersion {
-		// 进行版本转换
-		obj, err = convertByBytes([]byte(str), r.gvk)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		// 直接解析对象
-		obj, err = newByGVK(r.gvk)
-		if err != nil {
-			return nil, err
-		}
-		if err := json.Unmarshal([]byte(str), &obj); err != nil {
-			return nil, err
-		}
+	obj, err := r.decode(str)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// 执行装饰钩子
-	if r.decorateHook != nil {
-		if err := r.decorateHook(obj); err != nil {
-			return nil, err
-		}
+	log.Tracef("got %s: %s", obj.GetKey(), str)
+	return obj, modRevision, nil
+}
+
+// compareAndSet 仅当存储中的修订版本号未发生变化时写入，否则返回冲突错误。存活时间可能随状态变化，因此同时刷新索引键的存活时间
+func (r Registry) compareAndSet(obj core.ApiObject, key string, value string, modRevision int64) error {
+	ops := []db.KVOp{db.OpCompareAndSet(key, value, modRevision).WithTTL(r.getTTL(obj))}
+	ok, err := db.KV.Txn(append(ops, r.indexOps(key, obj, obj)...)...)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err := e.ResourceConflictError{Key: key}
+		log.Error(err)
+		return err
 	}
+	return nil
+}
 
-	log.Tracef("got %s: %s", obj.GetKey(), str)
-	return obj, nil
+// getTTL 获取资源的存活时间，注解中指定的存活时间优先于默认存活时间
+func (r Registry) getTTL(obj core.ApiObject) time.Duration {
+	if ttlStr, ok := obj.GetMetadata().Annotations[core.AnnotationTTL]; ok {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil {
+			return ttl
+		}
+		log.Warnf("invalid annotation %s of %s: %s", core.AnnotationTTL, obj.GetKey(), ttlStr)
+	}
+	if r.ttlFunc != nil {
+		return r.ttlFunc(obj)
+	}
+	return 0
 }
 
-// List 列举单个命名空间下的所有资源对象
+// List 列举单个命名空间下的所有资源对象，指定了core.WithLimit时只返回第一页，
+// 可通过core.WithLabelSelector与core.WithFieldSelector过滤资源对象
 func (r Registry) List(ctx context.Context, namespace string, opts ...core.OpOpt) (core.ApiObjectList, error) {
+	list, _, _, err := r.listWithOpts(ctx, namespace, opts...)
+	return list, err
+}
+
+// ListPage 按名称顺序分页列举单个命名空间下的资源对象，通过core.WithLimit指定每页的记录数，通过core.WithContinue传入上一页返回的令牌。
+// 选择器在分页前生效，只有最后一页的记录数可能少于每页的记录数。返回的令牌为空时表示已到达最后一页。
+// 同时返回读取时存储的全局修订版本号，可通过WatchFrom从该修订版本之后开始侦听变更，分页获取时应使用第一页返回的修订版本号
+func (r Registry) ListPage(ctx context.Context, namespace string, opts ...core.OpOpt) (core.ApiObjectList, string, int64, error) {
 	return r.listWithOpts(ctx, namespace, opts...)
 }
 
-func (r Registry) listWithOpts(ctx context.Context, namespace string, opts ...core.OpOpt) (core.ApiObjectList, error) {
+func (r Registry) listWithOpts(ctx context.Context, namespace string, opts ...core.OpOpt) (core.ApiObjectList, string, int64, error) {
 	var option core.Option
 	option.SetupOption(opts...)
 
@@ -463,55 +678,90 @@ func (r Registry) listWithOpts(ctx context.Context, namespace string, opts ...co
 		if r.namespaced && !re.MatchString(namespace) {
 			err := e.InvalidNamespaceError{Namespace: namespace}
 			log.Error(err)
-			return nil, err
+			return nil, "", 0, err
 		}
 	}
 
-	// 获取存储键
-	key := r.getKey(namespace, "")
-
-	// 获取对象
-	kvList, err := db.KV.List(key, true)
+	labelSelector, err := core.ParseLabelSelector(option.LabelSelector)
 	if err != nil {
-		return nil, err
+		log.Error(err)
+		return nil, "", 0, err
+	}
+	fieldSelector, err := core.ParseFieldSelector(option.FieldSelector)
+	if err != nil {
+		log.Error(err)
+		return nil, "", 0, err
 	}
 
-	// 解析已获取的对象
+	// 获取存储键
+	key := r.getKey(namespace, "")
+
+	// 获取对象，分页获取时先使用选择器过滤，再读取下一批记录直到填满一页
 	list := []core.ApiObject{}
-	for _, value := range kvList {
-		// 判断对象版本
-		metaType := new(core.MetaType)
-		if err := json.Unmarshal([]byte(value), metaType); err != nil {
-			return nil, err
+	var continueToken string
+	var revision int64
+	if option.Limit > 0 || option.Continue != "" {
+		startKey, err := decodeContinue(key, option.Continue)
+		if err != nil {
+			log.Error(err)
+			return nil, "", 0, err
 		}
-		var obj core.ApiObject
-		if metaType.ApiVersion != r.gvk.ApiVersion {
-			// 存储版本与获取版本不一致，进行结构转换
-			obj, err = convertByBytes([]byte(value), r.gvk)
+		for {
+			kvPairs, more, pageRevision, err := db.KV.ListPage(key, startKey, option.Limit-int64(len(list)))
 			if err != nil {
-				return nil, err
+				return nil, "", 0, err
 			}
-		} else {
-			obj, err = newByGVK(r.gvk)
-			if err != nil {
-				return nil, err
+			// 使用首次读取时的修订版本号，从该版本开始侦听不会遗漏之后读取的记录的变更
+			if revision == 0 {
+				revision = pageRevision
+			}
+			for index, kvPair := range kvPairs {
+				obj, err := r.decode(kvPair.Value)
+				if err != nil {
+					return nil, "", 0, err
+				}
+				if err := r.decorate(obj); err != nil {
+					return nil, "", 0, err
+				}
+				if !labelSelector.MatchesLabels(obj) || !fieldSelector.MatchesFields(obj) {
+					continue
+				}
+				list = append(list, obj)
+				// 页已填满，且当前记录之后仍有剩余的记录
+				if option.Limit > 0 && int64(len(list)) >= option.Limit {
+					if more || index < len(kvPairs)-1 {
+						continueToken = encodeContinue(kvPair.Key)
+					}
+					break
+				}
 			}
-			if err := json.Unmarshal([]byte(value), &obj); err != nil {
-				return nil, err
+			if !more || len(kvPairs) == 0 || (option.Limit > 0 && int64(len(list)) >= option.Limit) {
+				break
 			}
+			startKey = kvPairs[len(kvPairs)-1].Key + "\x00"
 		}
-
-		// 执行装饰钩子
-		if r.decorateHook != nil {
-			if err := r.decorateHook(obj); err != nil {
-				return nil, err
+	} else {
+		var kvList map[string]string
+		kvList, revision, err = db.KV.ListWithRevision(key, true)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		for _, value := range kvList {
+			obj, err := r.decode(value)
+			if err != nil {
+				return nil, "", 0, err
+			}
+			if err := r.decorate(obj); err != nil {
+				return nil, "", 0, err
 			}
+			if !labelSelector.MatchesLabels(obj) || !fieldSelector.MatchesFields(obj) {
+				continue
+			}
+			list = append(list, obj)
 		}
-
-		list = append(list, obj)
 	}
 	log.Tracef("listed %s: %+v", key, list)
-	return list, nil
+	return list, continueToken, revision, nil
 }
 
 // Watch 侦听资源对象的变动, 当name为空时，表示侦听命名空间下的所有资源对象
@@ -547,41 +797,11 @@ func (r Registry) Watch(ctx context.Context, namespace string, name string) <-ch
 					return
 				}
 
-				// 转换对象版本
-				metaType := new(core.MetaType)
-				if err := json.Unmarshal([]byte(kvAction.Value), metaType); err != nil {
-					log.Error(err)
-				}
-				var obj core.ApiObject
-				var err error
-				if metaType.ApiVersion != r.gvk.ApiVersion {
-					// 存储版本与获取版本不一致，进行结构转换
-					obj, err = convertByBytes([]byte(kvAction.Value), r.gvk)
-					if err != nil {
-						log.Error(err)
-					}
-				} else {
-					// 解析已侦听到的对象
-					obj, err = newByGVK(r.gvk)
-					if err != nil {
-						log.Error(err)
-					}
-					if err := json.Unmarshal([]byte(kvAction.Value), &obj); err != nil {
-						log.Error(err)
-					}
-				}
-
-				// 执行装饰钩子
-				if r.decorateHook != nil {
-					if err := r.decorateHook(obj); err != nil {
-						log.Error(err)
-					}
-				}
-
-				// 将侦听到的对象推入响应通道
-				objActionChan <- core.ApiObjectAction{
-					Type: kvAction.ActionType,
-					Obj:  obj,
+				// 将侦听到的对象推入响应通道，调用方停止接收时结束侦听
+				select {
+				case objActionChan <- r.decodeAction(kvAction):
+				case <-ctx.Done():
+					return
 				}
 			case <-ctx.Done():
 				return
@@ -636,41 +856,11 @@ func (r Registry) GetWatch(ctx context.Context, namespace string, name string) <
 					return
 				}
 
-				// 转换对象版本
-				metaType := new(core.MetaType)
-				if err := json.Unmarshal([]byte(kvAction.Value), metaType); err != nil {
-					log.Error(err)
-				}
-				var obj core.ApiObject
-				var err error
-				if metaType.ApiVersion != r.gvk.ApiVersion {
-					// 存储版本与获取版本不一致，进行结构转换
-					obj, err = convertByBytes([]byte(kvAction.Value), r.gvk)
-					if err != nil {
-						log.Error(err)
-					}
-				} else {
-					// 解析已侦听到的对象
-					obj, err = newByGVK(r.gvk)
-					if err != nil {
-						log.Error(err)
-					}
-					if err := json.Unmarshal([]byte(kvAction.Value), &obj); err != nil {
-						log.Error(err)
-					}
-				}
-
-				// 执行装饰钩子
-				if r.decorateHook != nil {
-					if err := r.decorateHook(obj); err != nil {
-						log.Error(err)
-					}
-				}
-
-				// 将侦听到的对象推入响应通道
-				objActionChan <- core.ApiObjectAction{
-					Type: kvAction.ActionType,
-					Obj:  obj,
+				// 将侦听到的对象推入响应通道，调用方停止接收时结束侦听
+				select {
+				case objActionChan <- r.decodeAction(kvAction):
+				case <-ctx.Done():
+					return
 				}
 			case <-ctx.Done():
 				return
@@ -696,23 +886,14 @@ func (r Registry) ListWatch(ctx context.Context, namespace string) <-chan core.A
 	// 获取存储键
 	key := r.getKey(namespace, "")
 
-	// 根据是否传递名称判断是侦听单个还是所有对象
-	var kvActionWatcher <-chan db.KVAction
-	kvActionWatcher = db.KV.Watch(ctx, key, true)
+	// 先推送当前所有对象，再从快照的修订版本开始侦听变更
+	kvActionWatcher := db.KV.ListWatch(ctx, key, true)
 
 	objActionChan := make(chan core.ApiObjectAction, 1000)
 
 	go func() {
 		defer close(objActionChan)
 
-		list, _ := r.List(context.TODO(), namespace)
-		for _, obj := range list {
-			objActionChan <- core.ApiObjectAction{
-				Type: db.KVActionTypeSet,
-				Obj:  obj,
-			}
-		}
-
 		for {
 			select {
 			case kvAction, ok := <-kvActionWatcher:
@@ -720,53 +901,131 @@ func (r Registry) ListWatch(ctx context.Context, namespace string) <-chan core.A
 					return
 				}
 
-				// 转换对象版本
-				metaType := new(core.MetaType)
-				if err := json.Unmarshal([]byte(kvAction.Value), metaType); err != nil {
-					log.Error(err)
-				}
-				var obj core.ApiObject
-				var err error
-				if metaType.ApiVersion != r.gvk.ApiVersion {
-					// 存储版本与获取版本不一致，进行结构转换
-					obj, err = convertByBytes([]byte(kvAction.Value), r.gvk)
-					if err != nil {
-						log.Error(err)
-					}
-				} else {
-					// 解析已侦听到的对象
-					obj, err = newByGVK(r.gvk)
-					if err != nil {
-						log.Error(err)
-					}
-					if err := json.Unmarshal([]byte(kvAction.Value), &obj); err != nil {
-						log.Error(err)
-					}
+				// 将侦听到的对象推入响应通道，调用方停止接收时结束侦听
+				select {
+				case objActionChan <- r.decodeAction(kvAction):
+				case <-ctx.Done():
+					return
 				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	log.Tracef("watched %s", key)
+	return objActionChan
+}
 
-				// 执行装饰钩子
-				if r.decorateHook != nil {
-					if err := r.decorateHook(obj); err != nil {
-						log.Error(err)
-					}
+// WatchFrom 从指定修订版本之后开始侦听资源对象的变动，名称为空时侦听命名空间下的所有对象。
+// 期间的变更会先被补发，修订版本之后的变更已无法找回时返回e.ExpiredRevisionError
+func (r Registry) WatchFrom(ctx context.Context, namespace string, name string, revision int64) (<-chan core.ApiObjectAction, error) {
+	// 字段校验
+	re := regexp.MustCompile(core.ValidNameRegex)
+	if namespace != "" && r.namespaced && !re.MatchString(namespace) {
+		err := e.InvalidNamespaceError{Namespace: namespace}
+		log.Error(err)
+		return nil, err
+	}
+	if name != "" && !re.MatchString(name) {
+		err := e.InvalidNameError{Name: name}
+		log.Error(err)
+		return nil, err
+	}
+
+	// 获取存储键
+	key := r.getKey(namespace, name)
+
+	kvActionWatcher, err := db.KV.WatchFrom(ctx, key, name == "", revision)
+	if err != nil {
+		return nil, err
+	}
+
+	objActionChan := make(chan core.ApiObjectAction, 1000)
+	go func() {
+		defer close(objActionChan)
+
+		for {
+			select {
+			case kvAction, ok := <-kvActionWatcher:
+				if !ok {
+					return
 				}
 
-				// 将侦听到的对象推入响应通道
-				objActionChan <- core.ApiObjectAction{
-					Type: kvAction.ActionType,
-					Obj:  obj,
+				// 将侦听到的对象推入响应通道，调用方停止接收时结束侦听
+				select {
+				case objActionChan <- r.decodeAction(kvAction):
+				case <-ctx.Done():
+					return
 				}
 			case <-ctx.Done():
 				return
 			}
 		}
 	}()
-	log.Tracef("watched %s", key)
-	return objActionChan
+	log.Tracef("watched %s from revision %d", key, revision)
+	return objActionChan, nil
 }
 
-// UpdateStatus 更新单个资源对象的Status
-func (r Registry) UpdateStatus(namespace string, name string, status core.Status) (core.ApiObject, error) {
+// decodeAction 将键值变更解析为资源对象变更
+func (r Registry) decodeAction(kvAction db.KVAction) core.ApiObjectAction {
+	obj, err := r.decode(kvAction.Value)
+	if err != nil {
+		log.Error(err)
+	} else if err := r.decorate(obj); err != nil {
+		log.Error(err)
+	}
+	return core.ApiObjectAction{
+		Type:     kvAction.ActionType,
+		Obj:      obj,
+		Revision: kvAction.Revision,
+	}
+}
+
+// decode 解密存储中的序列化数据，并解析为当前存储器所对应结构版本的资源对象
+func (r Registry) decode(value string) (core.ApiObject, error) {
+	value, err := r.decrypt(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// 判断对象版本
+	metaType := new(core.MetaType)
+	if err := json.Unmarshal([]byte(value), metaType); err != nil {
+		return nil, err
+	}
+	var obj core.ApiObject
+	if metaType.ApiVersion != r.gvk.ApiVersion {
+		// 存储版本与获取版本不一致，进行结构转换
+		obj, err = convertByBytes([]byte(value), r.gvk)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		obj, err = newByGVK(r.gvk)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(value), &obj); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// decorate 对返回给调用方的对象执行装饰钩子，写入流程中读取的对象不进行装饰，避免装饰后的内容被写回存储
+func (r Registry) decorate(obj core.ApiObject) error {
+	if r.decorateHook == nil {
+		return nil
+	}
+	return r.decorateHook(obj)
+}
+
+// UpdateStatus 更新单个资源对象的Status，不会修改Spec，资源版本号同样会累加。
+// 支持core.WithResourceVersion校验状态所依据的资源版本是否仍为最新，以及core.WithDryRun
+func (r Registry) UpdateStatus(namespace string, name string, status core.Status, opts ...core.OpOpt) (core.ApiObject, error) {
+	var option core.Option
+	option.SetupOption(opts...)
+
 	// 字段校验
 	re := regexp.MustCompile(core.ValidNameRegex)
 	if r.namespaced && !re.MatchString(namespace) {
@@ -784,27 +1043,36 @@ func (r Registry) UpdateStatus(namespace string, name string, status core.Status
 	key := r.getKey(namespace, name)
 
 	// 获取并判断对象是否存在
-	obj, err := r.Get(context.TODO(), namespace, name)
+	obj, modRevision, err := r.getWithRevision(namespace, name)
 	if err != nil {
 		return nil, err
 	}
 	if obj == nil {
-		return nil, e.Errorf("update failed: %s not found", key)
+		return nil, e.ResourceNotFoundError{Key: key}
+	}
+	if option.ResourceVersion != 0 && option.ResourceVersion != obj.GetMetadata().ResourceVersion {
+		err := e.ResourceConflictError{Key: key}
+		log.Error(err)
+		return nil, err
 	}
 
 	obj.SetUpdateTime(time.Now())
 	obj.SetStatus(status)
+	obj.RaiseVersion()
+	if option.DryRun {
+		return obj, nil
+	}
 
-	data, err := json.Marshal(obj)
+	data, err := r.encode(obj)
 	if err != nil {
 		return nil, err
 	}
 
 	// 更新对象
-	if err := db.KV.Set(key, string(data)); err != nil {
+	if err := r.compareAndSet(obj, key, data, modRevision); err != nil {
 		return nil, err
 	}
-	log.Tracef("updated %s: %s", obj.GetKey(), string(data))
+	log.Tracef("updated %s: %s", obj.GetKey(), data)
 	return obj, nil
 }
 
@@ -827,7 +1095,7 @@ func (r Registry) UpdateStatusPhase(namespace string, name string, phase string)
 	key := r.getKey(namespace, name)
 
 	// 获取并判断对象是否存在
-	obj, err := r.Get(context.TODO(), namespace, name)
+	obj, modRevision, err := r.getWithRevision(namespace, name)
 	if err != nil {
 		return nil, err
 	}
@@ -837,17 +1105,18 @@ func (r Registry) UpdateStatusPhase(namespace string, name string, phase string)
 
 	obj.SetUpdateTime(time.Now())
 	obj.SetStatusPhase(phase)
+	obj.RaiseVersion()
 
-	data, err := json.Marshal(obj)
+	data, err := r.encode(obj)
 	if err != nil {
 		return nil, err
 	}
 
 	// 更新对象
-	if err := db.KV.Set(key, string(data)); err != nil {
+	if err := r.compareAndSet(obj, key, data, modRevision); err != nil {
 		return nil, err
 	}
-	log.Tracef("updated %s: %s", obj.GetKey(), string(data))
+	log.Tracef("updated %s: %s", obj.GetKey(), data)
 	return obj, nil
 }
 
@@ -879,12 +1148,19 @@ func (r Registry) MigrateObjects() error {
 			continue
 		}
 
+		// 解密敏感字段
+		value, err := r.decrypt(value)
+		if err != nil {
+			return err
+		}
+
 		// 存储版本与获取版本不一致，进行结构转换
 		obj, err := convertByBytes([]byte(value), r.gvk)
 		if err != nil {
 			return err
 		}
 
+		oldObj := obj.DeepCopyApiObject()
 		metadata := obj.GetMetadata()
 		hash := obj.Sha256()
 
@@ -914,12 +1190,15 @@ func (r Registry) MigrateObjects() error {
 			log.Debugf("update %s", obj.GetKey())
 		}
 
-		data, err := json.Marshal(obj)
+		data, err := r.encode(obj)
 		if err != nil {
 			return err
 		}
 
-		if err := db.KV.Set(r.getKey(metadata.Namespace, metadata.Name), string(data)); err != nil {
+		// 与资源的写入保持一致，同时维护存活时间与索引键
+		objKey := r.getKey(metadata.Namespace, metadata.Name)
+		ops := append([]db.KVOp{db.OpSet(objKey, data).WithTTL(r.getTTL(obj))}, r.indexOps(objKey, oldObj, obj)...)
+		if _, err := db.KV.Txn(ops...); err != nil {
 			return err
 		}
 	}
@@ -940,7 +1219,7 @@ func (r *Registry) SetMutateHook(hook HookFunc) {
 	r.mutateHook = hook
 }
 
-// SetDecorateHook 注入获取对象后的内容填充钩子，该钩子会在Get和Watch获取到对象后执行
+// SetDecorateHook 注入获取对象后的内容填充钩子，该钩子会在Get、List与Watch返回对象前执行，不会影响写入存储的内容
 func (r *Registry) SetDecorateHook(hook HookFunc) {
 	r.decorateHook = hook
 }
@@ -955,6 +1234,11 @@ func (r *Registry) SetPreUpdateHook(hook HookFunc) {
 	r.preUpdateHook = hook
 }
 
+// SetMergeHook 注入更新合并钩子，该钩子会在更新内容与存储中的对象合并后执行，可以根据新旧对象的差异修改Spec以外的内容
+func (r *Registry) SetMergeHook(hook MergeHookFunc) {
+	r.mergeHook = hook
+}
+
 // SetPreCreateHook 注入前置删除钩子
 func (r *Registry) SetPreDeleteHook(hook HookFunc) {
 	r.preDeleteHook = hook
@@ -980,6 +1264,31 @@ func (r *Registry) SetRevisioner(revisioner Revisioner) {
 	r.revisioner = revisioner
 }
 
+// Revisioner 获取修订历史记录器，未启用修订历史时返回空
+func (r Registry) Revisioner() Revisioner {
+	return r.revisioner
+}
+
+// SetTTLFunc 设置资源默认存活时间的计算方法，资源也可通过注解单独指定存活时间
+func (r *Registry) SetTTLFunc(f TTLFunc) {
+	r.ttlFunc = f
+}
+
+// SetEncryptedFields 设置需要加密存储的字段路径，路径以.分隔字段名，*匹配字典的所有键或数组的所有元素，如Spec.SSH.Password
+func (r *Registry) SetEncryptedFields(paths ...string) {
+	r.encryptedFields = paths
+}
+
+// SetEncryptedFieldsFunc 设置根据资源内容计算需要加密存储的字段路径的方法
+func (r *Registry) SetEncryptedFieldsFunc(f FieldPathsFunc) {
+	r.encryptedFieldsFunc = f
+}
+
+// SetRedactedFields 设置审计时需要额外隐藏的字段路径，需要加密存储的字段无需重复设置
+func (r *Registry) SetRedactedFields(paths ...string) {
+	r.redactedFields = paths
+}
+
 // SetDefaultFinalizers 设置默认finalizers
 func (r *Registry) SetDefaultFinalizers(finalizers []string) {
 	r.defaultFinalizers = finalizers
@@ -1024,6 +1333,11 @@ func (r Registry) commonValidate(obj core.ApiObject) error {
 	if !re.MatchString(metadata.Name) {
 		return e.InvalidNameError{Name: metadata.Name}
 	}
+	if ttlStr, ok := metadata.Annotations[core.AnnotationTTL]; ok {
+		if _, err := time.ParseDuration(ttlStr); err != nil {
+			return e.Errorf("invalid annotation %s: %s", core.AnnotationTTL, ttlStr)
+		}
+	}
 	return nil
 }
 
@@ -1032,5 +1346,8 @@ func NewRegistry(gvk core.GVK, namespaced bool) Registry {
 	return Registry{
 		gvk:        gvk,
 		namespaced: namespaced,
+		indexers: map[string]IndexFunc{
+			core.IndexOwnerReferences: ownerReferencesIndex,
+		},
 	}
 }
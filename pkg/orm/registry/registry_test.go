@@ -0,0 +1,341 @@
+package registry_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	// 注册资源对象的实例化与转换方法
+	_ "github.com/wujie1993/waves/pkg/orm"
+)
+
+func TestRegistryUpdateConflict(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+
+	configMap := v1.NewConfigMap()
+	configMap.Metadata.Namespace = "default"
+	configMap.Metadata.Name = "test"
+	configMap.Data["key"] = "1"
+	if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := configMapRegistry.Get(context.TODO(), "default", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := obj.(*v1.ConfigMap)
+
+	// 基于最新版本的更新会累加资源版本号
+	obj, err = configMapRegistry.Get(context.TODO(), "default", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := obj.(*v1.ConfigMap)
+	latest.Data["key"] = "2"
+	if _, err := configMapRegistry.Update(context.TODO(), latest); err != nil {
+		t.Fatal(err)
+	}
+
+	// 基于过期版本的更新返回冲突错误
+	stale.Data["key"] = "3"
+	if _, err := configMapRegistry.Update(context.TODO(), stale); err == nil {
+		t.Fatal("stale update should fail")
+	} else if _, ok := err.(e.ResourceConflictError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 只修改标签或状态的写入同样累加资源版本号，基于过期版本的写入返回冲突错误
+	obj, err = configMapRegistry.Get(context.TODO(), "default", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest = obj.(*v1.ConfigMap)
+	stale = latest.DeepCopy()
+	latest.Metadata.Labels["owner"] = "a"
+	if _, err := configMapRegistry.Update(context.TODO(), latest, core.WithAllFields()); err != nil {
+		t.Fatal(err)
+	}
+	stale.Metadata.Labels["owner"] = "b"
+	if _, err := configMapRegistry.Update(context.TODO(), stale, core.WithAllFields()); err == nil {
+		t.Fatal("stale label update should fail")
+	} else if _, ok := err.(e.ResourceConflictError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRegistryUpdateRevision(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	hostRegistry := v1.NewHostRegistry()
+	host := v1.NewHost()
+	host.Metadata.Name = "host-1"
+	host.Spec.SSH.Host = "192.168.0.1"
+	if _, err := hostRegistry.Create(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hostRegistry.UpdateStatusPhase("", "host-1", core.PhaseReady); err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入冲突时不生成修订版本
+	stale := host.DeepCopy()
+	stale.Spec.SSH.Host = "192.168.0.2"
+	if _, err := hostRegistry.Update(context.TODO(), stale); err == nil {
+		t.Fatal("stale update should fail")
+	}
+	revisioner := hostRegistry.Revisioner()
+	if revisions, err := revisioner.ListRevisions(context.TODO(), "", "host-1"); err != nil || len(revisions) != 0 {
+		t.Fatalf("unexpected revisions %v after conflict, err: %v", revisions, err)
+	}
+
+	// 修订版本可以通过其内容生效期间的任一资源版本号获取
+	stale.Metadata.ResourceVersion = 2
+	if _, err := hostRegistry.Update(context.TODO(), stale); err != nil {
+		t.Fatal(err)
+	}
+	for _, revision := range []int{1, 2} {
+		obj, err := revisioner.GetRevision(context.TODO(), "", "host-1", revision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if obj == nil || obj.(*v1.Host).Spec.SSH.Host != "192.168.0.1" {
+			t.Fatalf("unexpected revision %d: %+v", revision, obj)
+		}
+	}
+}
+
+func TestRegistryListSelector(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	configMapRegistry := v1.NewConfigMapRegistry()
+	tiers := []string{"web", "db", ""}
+	for i, tier := range tiers {
+		configMap := v1.NewConfigMap()
+		configMap.Metadata.Namespace = "default"
+		configMap.Metadata.Name = "test" + strconv.Itoa(i)
+		if tier != "" {
+			configMap.Metadata.Labels["tier"] = tier
+		}
+		if _, err := configMapRegistry.Create(context.TODO(), configMap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		labelSelector string
+		fieldSelector string
+		count         int
+	}{
+		{labelSelector: "tier=web", count: 1},
+		{labelSelector: "tier!=web", count: 2},
+		{labelSelector: "tier in (web,db)", count: 2},
+		{labelSelector: "tier notin (web)", count: 2},
+		{labelSelector: "tier", count: 2},
+		{labelSelector: "!tier", count: 1},
+		{fieldSelector: "metadata.name=test1", count: 1},
+		{labelSelector: "tier", fieldSelector: "metadata.name!=test1", count: 1},
+	}
+	for _, c := range cases {
+		list, err := configMapRegistry.List(context.TODO(), "default", core.WithLabelSelector(c.labelSelector), core.WithFieldSelector(c.fieldSelector))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != c.count {
+			t.Fatalf("labelSelector %q fieldSelector %q: expected %d objects, got %d", c.labelSelector, c.fieldSelector, c.count, len(list))
+		}
+	}
+
+	// 无效的选择器
+	if _, err := configMapRegistry.List(context.TODO(), "default", core.WithLabelSelector("tier in (web")); err == nil {
+		t.Fatal("invalid selector should fail")
+	} else if _, ok := err.(e.InvalidSelectorError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRegistryPropagationPolicy(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	gpuRegistry := v1.NewGPURegistry()
+	for _, name := range []string{"foreground", "background", "invalid"} {
+		gpu := v1.NewGPU()
+		gpu.Metadata.Name = name
+		gpu.Metadata.OwnerReferences = []core.OwnerReference{{Kind: core.KindHost, Name: "host"}}
+		if _, err := gpuRegistry.Create(context.TODO(), gpu); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 前台删除时添加由垃圾回收器处理的finalizer，对象保留为删除中状态
+	if _, err := gpuRegistry.Delete(context.TODO(), "", "foreground", core.WithPropagationPolicy(core.PropagationPolicyForeground)); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := gpuRegistry.Get(context.TODO(), "", "foreground")
+	if err != nil {
+		t.Fatal(err)
+	} else if obj == nil {
+		t.Fatal("object deleted before dependents are collected")
+	}
+	if finalizers := obj.GetMetadata().Finalizers; len(finalizers) != 1 || finalizers[0] != core.FinalizerForegroundDeletion {
+		t.Fatalf("unexpected finalizers %v", finalizers)
+	}
+	if obj.GetStatusPhase() != core.PhaseDeleting {
+		t.Fatalf("unexpected phase %s", obj.GetStatusPhase())
+	}
+	owner := obj.GetMetadata().OwnerReferences[0]
+	if !owner.Refers(core.KindHost, core.Metadata{Name: "host", Uid: "any"}) || owner.Refers(core.KindHost, core.Metadata{Name: "other"}) {
+		t.Fatalf("unexpected owner reference %+v", owner)
+	}
+
+	// 后台删除时没有finalizer的对象直接删除
+	if _, err := gpuRegistry.Delete(context.TODO(), "", "background", core.WithPropagationPolicy(core.PropagationPolicyBackground)); err != nil {
+		t.Fatal(err)
+	}
+	if obj, err := gpuRegistry.Get(context.TODO(), "", "background"); err != nil {
+		t.Fatal(err)
+	} else if obj != nil {
+		t.Fatal("object not deleted")
+	}
+
+	if _, err := gpuRegistry.Delete(context.TODO(), "", "invalid", core.WithPropagationPolicy("Unknown")); err == nil {
+		t.Fatal("expected invalid propagation policy error")
+	} else if _, ok := err.(e.InvalidPropagationPolicyError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestRegistryDryRun(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	hostRegistry := v1.NewHostRegistry()
+	host := v1.NewHost()
+	host.Metadata.Name = "host-1"
+	host.Spec.SSH.Host = "192.168.0.1"
+
+	// 试运行创建返回填充后的对象但不写入
+	result, err := hostRegistry.Create(context.TODO(), host, core.WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GetMetadata().Uid == "" || result.GetMetadata().ResourceVersion != 1 {
+		t.Fatalf("unexpected dry run result %+v", result.GetMetadata())
+	}
+	if obj, err := hostRegistry.Get(context.TODO(), "", "host-1"); err != nil || obj != nil {
+		t.Fatalf("unexpected stored host %+v after dry run, err: %v", obj, err)
+	}
+
+	if _, err := hostRegistry.Create(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+
+	// 试运行更新不写入，也不生成修订版本
+	update := host.DeepCopy()
+	update.Spec.SSH.Host = "192.168.0.2"
+	result, err = hostRegistry.Update(context.TODO(), update, core.WithDryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.(*v1.Host).Spec.SSH.Host != "192.168.0.2" || result.GetMetadata().ResourceVersion != 2 {
+		t.Fatalf("unexpected dry run result %+v", result)
+	}
+	obj, err := hostRegistry.Get(context.TODO(), "", "host-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.(*v1.Host).Spec.SSH.Host != "192.168.0.1" || obj.GetMetadata().ResourceVersion != 1 {
+		t.Fatalf("unexpected stored host %+v after dry run", obj)
+	}
+	if revisions, err := hostRegistry.Revisioner().ListRevisions(context.TODO(), "", "host-1"); err != nil || len(revisions) != 0 {
+		t.Fatalf("unexpected revisions %v after dry run, err: %v", revisions, err)
+	}
+
+	// 试运行删除返回将被删除的对象但不删除
+	if result, err := hostRegistry.Delete(context.TODO(), "", "host-1", core.WithDryRun()); err != nil || result == nil {
+		t.Fatalf("unexpected dry run delete result %+v, err: %v", result, err)
+	}
+	if obj, err := hostRegistry.Get(context.TODO(), "", "host-1"); err != nil || obj == nil {
+		t.Fatalf("host deleted by dry run, err: %v", err)
+	}
+}
+
+func TestRegistryUpdateStatus(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	hostRegistry := v1.NewHostRegistry()
+	host := v1.NewHost()
+	host.Metadata.Name = "host-1"
+	host.Spec.SSH.Host = "192.168.0.1"
+	if _, err := hostRegistry.Create(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+
+	// 只更新状态，不修改Spec，资源版本号同样累加
+	status := core.NewStatus()
+	status.Phase = core.PhaseReady
+	obj, err := hostRegistry.UpdateStatus("", "host-1", status, core.WithResourceVersion(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetStatusPhase() != core.PhaseReady || obj.GetMetadata().ResourceVersion != 2 {
+		t.Fatalf("unexpected host %+v after status update", obj)
+	}
+
+	// 通过Spec更新时忽略请求中的状态
+	update := host.DeepCopy()
+	update.Metadata.ResourceVersion = 0
+	update.Spec.SSH.Host = "192.168.0.2"
+	update.Status.Phase = core.PhaseFailed
+	obj, err = hostRegistry.Update(context.TODO(), update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetStatusPhase() == core.PhaseFailed || obj.GetMetadata().ResourceVersion != 3 {
+		t.Fatalf("unexpected host %+v after spec update", obj)
+	}
+
+	// 状态所依据的资源版本已过时
+	if _, err := hostRegistry.UpdateStatus("", "host-1", status, core.WithResourceVersion(1)); err == nil {
+		t.Fatal("expected conflict with outdated resource version")
+	} else if _, ok := err.(e.ResourceConflictError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// 并发上报依据同一资源版本的状态时，只有一个写入成功，其余均返回冲突
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := hostRegistry.UpdateStatus("", "host-1", status, core.WithResourceVersion(3))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		} else if _, ok := err.(e.ResourceConflictError); !ok {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("expected exactly one status write to succeed, got %d", succeeded)
+	}
+
+	if _, err := hostRegistry.UpdateStatus("", "host-2", status); err == nil {
+		t.Fatal("expected not found error")
+	} else if _, ok := err.(e.ResourceNotFoundError); !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
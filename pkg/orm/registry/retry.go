@@ -0,0 +1,33 @@
+package registry
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+const (
+	// 冲突重试的最大次数
+	conflictRetryTimes = 5
+	// 冲突重试的初始间隔，每次重试后翻倍
+	conflictRetryInterval = 10 * time.Millisecond
+)
+
+// RetryOnConflict 执行fn，当fn返回资源冲突错误时重新执行，直至成功、返回其他错误或超出重试次数。
+// fn内部应当重新获取最新的资源对象后再进行修改与更新
+func RetryOnConflict(fn func() error) error {
+	interval := conflictRetryInterval
+	var err error
+	for retry := 0; retry < conflictRetryTimes; retry++ {
+		err = fn()
+		if _, ok := err.(e.ResourceConflictError); !ok {
+			return err
+		}
+		log.Warnf("%s, retry after %s", err, interval)
+		time.Sleep(interval)
+		interval *= 2
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package registry_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db"
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/orm/v2"
+	"github.com/wujie1993/waves/pkg/setting"
+)
+
+func TestRegistryRevision(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+	defer func(limit int) {
+		setting.RevisionSetting.Host = limit
+	}(setting.RevisionSetting.Host)
+	setting.RevisionSetting.Host = 2
+
+	hostRegistry := v1.NewHostRegistry()
+	host := v1.NewHost()
+	host.Metadata.Name = "host-1"
+	host.Spec.SSH.Host = "192.168.0.1"
+	if _, err := hostRegistry.Create(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+	// 每次更新前的内容被记录为修订版本，超出保留数量上限时删除最早的修订版本
+	for _, ip := range []string{"192.168.0.2", "192.168.0.3", "192.168.0.4"} {
+		obj, err := hostRegistry.Get(context.TODO(), "", "host-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		host := obj.(*v1.Host)
+		host.Spec.SSH.Host = ip
+		if _, err := hostRegistry.Update(context.TODO(), host); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	revisioner := hostRegistry.Revisioner()
+	revisions, err := revisioner.ListRevisions(context.TODO(), "", "host-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts := []string{}
+	for _, obj := range revisions {
+		hosts = append(hosts, obj.(*v1.Host).Spec.SSH.Host)
+	}
+	if strings.Join(hosts, ",") != "192.168.0.3,192.168.0.2" {
+		t.Fatalf("unexpected revisions %v", hosts)
+	}
+
+	// 其他结构版本的存储器读取到的修订版本会转换为对应的结构版本
+	v2Revisions, err := v2.NewHostRegistry().Revisioner().ListRevisions(context.TODO(), "", "host-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v2Revisions) != 2 || v2Revisions[0].(*v2.Host).Spec.SSH.Host != "192.168.0.3" {
+		t.Fatalf("unexpected v2 revisions %v", v2Revisions)
+	}
+
+	// 比较两个修订版本的Spec
+	diff, err := core.NewRevisionDiff(revisions[1], revisions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Patch) != 1 || diff.Patch[0].Op != "replace" || !strings.Contains(diff.Diff, "-Host: 192.168.0.2\n+Host: 192.168.0.3\n") {
+		t.Fatalf("unexpected revision diff %+v", diff)
+	}
+
+	// 回滚到指定的修订版本
+	revision := revisions[1].GetMetadata().ResourceVersion
+	obj, err := revisioner.RevertRevision(context.TODO(), "", "host-1", revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj == nil || obj.(*v1.Host).Spec.SSH.Host != "192.168.0.2" {
+		t.Fatalf("unexpected reverted host %+v", obj)
+	}
+
+	// 回滚同样记录回滚前的内容
+	revisions, err = revisioner.ListRevisions(context.TODO(), "", "host-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[0].(*v1.Host).Spec.SSH.Host != "192.168.0.4" {
+		t.Fatalf("unexpected revisions %v after revert", revisions)
+	}
+
+	revision = revisions[0].GetMetadata().ResourceVersion
+	if obj, err := revisioner.DeleteRevision(context.TODO(), "", "host-1", revision); err != nil || obj == nil {
+		t.Fatalf("failed to delete revision %d, err: %v", revision, err)
+	}
+	if obj, err := revisioner.GetRevision(context.TODO(), "", "host-1", revision); err != nil || obj != nil {
+		t.Fatalf("unexpected deleted revision %+v, err: %v", obj, err)
+	}
+	if err := revisioner.DeleteAllRevisions(context.TODO(), "", "host-1"); err != nil {
+		t.Fatal(err)
+	}
+	if kvList, err := db.KV.List(core.RegistryPrefix+"/revisions/", true); err != nil || len(kvList) != 0 {
+		t.Fatalf("unexpected revisions %v, err: %v", kvList, err)
+	}
+}
@@ -38,6 +38,27 @@ func RegisterStorageRegistry(registry ApiObjectRegistry) error {
 	return nil
 }
 
+// GetStorageRegistry 获取资源类型在数据库中实际存储的结构版本所对应的存储器，未注册时返回空
+func GetStorageRegistry(kind string) ApiObjectRegistry {
+	gk := core.GK{Group: core.Group, Kind: kind}
+	apiVersion, ok := storageVersion[gk]
+	if !ok {
+		apiVersion = core.ApiVersionV1
+	}
+	return storageRegistry[core.GVK{Group: gk.Group, ApiVersion: apiVersion, Kind: kind}]
+}
+
+// ListStorageRegistries 获取所有已注册资源类型的存储版本所对应的存储器
+func ListStorageRegistries() []ApiObjectRegistry {
+	registries := []ApiObjectRegistry{}
+	for gvk, registry := range storageRegistry {
+		if GetStorageRegistry(gvk.Kind) == registry {
+			registries = append(registries, registry)
+		}
+	}
+	return registries
+}
+
 // MigrateNamespacedObjects 将归属于命名空间下的资源存储路径进行迁移，从/registry/namespaces/<namespace>/<kind>/<name>迁移至/registry/<kind>/<namespace>/<name>
 func MigrateNamespacedObjects() {
 	keyPrefix := core.RegistryPrefix + "/namespaces/"
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+// appInstanceEncryptedFields 根据所引用的应用版本，获取应用实例中格式为密码的参数字段路径
+func appInstanceEncryptedFields(obj core.ApiObject) []string {
+	appInstance := obj.(*AppInstance)
+
+	appObj, err := NewAppRegistry().Get(context.TODO(), core.DefaultNamespace, appInstance.Spec.AppRef.Name)
+	if err != nil {
+		log.Error(err)
+		return nil
+	} else if appObj == nil {
+		return nil
+	}
+	app := appObj.(*App)
+
+	paths := []string{}
+	if versionApp, ok := app.GetVersion(appInstance.Spec.AppRef.Version); ok {
+		for argIndex, arg := range appInstance.Spec.Global.Args {
+			if IsPasswordArg(versionApp.Global.Args, arg.Name) {
+				paths = append(paths, fmt.Sprintf("Spec.Global.Args.%d.Value", argIndex))
+			}
+		}
+	}
+	for moduleIndex, module := range appInstance.Spec.Modules {
+		version := module.AppVersion
+		if version == "" {
+			version = appInstance.Spec.AppRef.Version
+		}
+		appModule, ok := app.GetVersionModule(version, module.Name)
+		if !ok {
+			continue
+		}
+		for argIndex, arg := range module.Args {
+			if IsPasswordArg(appModule.Args, arg.Name) {
+				paths = append(paths, fmt.Sprintf("Spec.Modules.%d.Args.%d.Value", moduleIndex, argIndex))
+			}
+		}
+	}
+	return paths
+}
+
+// IsPasswordArg 判断应用参数定义中的指定参数是否为密码格式
+func IsPasswordArg(appArgs []AppArgs, name string) bool {
+	for _, appArg := range appArgs {
+		if appArg.Name == name {
+			return appArg.Format == core.ArgFormatPassword
+		}
+	}
+	return false
+}
@@ -6,18 +6,80 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"path"
+	"regexp"
 	"text/template"
 	"time"
 
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/wujie1993/waves/pkg/e"
 	"github.com/wujie1993/waves/pkg/orm/core"
 	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/setting"
 	"github.com/wujie1993/waves/pkg/util"
 )
 
+// AdmissionConfigRegistry 准入控制配置存储器
+type AdmissionConfigRegistry struct {
+	registry.Registry
+}
+
+// admissionConfigValidate 校验准入控制钩子的配置
+func admissionConfigValidate(obj core.ApiObject) error {
+	admissionConfig := obj.(*AdmissionConfig)
+	for _, webhook := range admissionConfig.Spec.Webhooks {
+		if webhook.Name == "" {
+			return e.Errorf("name of admission webhook is required")
+		}
+		if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return e.Errorf("invalid url %s of admission webhook %s", webhook.URL, webhook.Name)
+		}
+		for _, operation := range webhook.Operations {
+			switch operation {
+			case "*", core.AdmissionOperationCreate, core.AdmissionOperationUpdate, core.AdmissionOperationDelete:
+			default:
+				return e.Errorf("unsupported operation %s of admission webhook %s", operation, webhook.Name)
+			}
+		}
+		switch webhook.FailurePolicy {
+		case "", core.AdmissionFailurePolicyFail, core.AdmissionFailurePolicyIgnore:
+		default:
+			return e.Errorf("unsupported failure policy %s of admission webhook %s", webhook.FailurePolicy, webhook.Name)
+		}
+		if webhook.TimeoutSeconds < 0 || webhook.TimeoutSeconds > core.AdmissionMaxTimeoutSeconds {
+			return e.Errorf("timeout seconds of admission webhook %s must be between 0 and %d", webhook.Name, core.AdmissionMaxTimeoutSeconds)
+		}
+	}
+	return nil
+}
+
+// admissionConfigMutate 填充准入控制钩子的默认配置
+func admissionConfigMutate(obj core.ApiObject) error {
+	admissionConfig := obj.(*AdmissionConfig)
+	for index, webhook := range admissionConfig.Spec.Webhooks {
+		if webhook.FailurePolicy == "" {
+			admissionConfig.Spec.Webhooks[index].FailurePolicy = core.AdmissionFailurePolicyFail
+		}
+		if webhook.TimeoutSeconds == 0 {
+			admissionConfig.Spec.Webhooks[index].TimeoutSeconds = core.AdmissionDefaultTimeoutSeconds
+		}
+	}
+	return nil
+}
+
+// NewAdmissionConfigRegistry 实例化准入控制配置存储器
+func NewAdmissionConfigRegistry() *AdmissionConfigRegistry {
+	r := &AdmissionConfigRegistry{
+		Registry: registry.NewRegistry(newGVK(core.KindAdmissionConfig), false),
+	}
+	r.SetValidateHook(admissionConfigValidate)
+	r.SetMutateHook(admissionConfigMutate)
+	return r
+}
+
 // AppRegistry 应用存储器
 // +namespaced=true
 type AppRegistry struct {
@@ -53,6 +115,9 @@ func NewAppRegistry() *AppRegistry {
 		core.FinalizerCleanRefConfigMap,
 	})
 	app.SetMutateHook(appMutate)
+	app.SetRevisioner(NewResourceRevision(app, func() int {
+		return setting.RevisionSetting.App
+	}))
 	return app
 }
 
@@ -330,7 +395,8 @@ func appInstanceMutate(obj core.ApiObject) error {
 				return err
 			}
 
-			if cm != nil {
+			// 配置文件内容未变化时保留原有的版本号，资源版本号会随配置文件的任意写入累加
+			if cm != nil && (module.ConfigMapRef.Hash != cm.SpecHash() || module.ConfigMapRef.Revision == 0) {
 				appInstance.Spec.Modules[moduleIndex].ConfigMapRef.Hash = cm.SpecHash()
 				appInstance.Spec.Modules[moduleIndex].ConfigMapRef.Revision = cm.GetMetadata().ResourceVersion
 			}
@@ -440,7 +506,6 @@ func NewAppInstanceRegistry() *AppInstanceRegistry {
 		Registry: registry.NewRegistry(newGVK(core.KindAppInstance), true),
 	}
 	r.SetDefaultFinalizers([]string{
-		core.FinalizerCleanRefEvent,
 		core.FinalizerReleaseRefGPU,
 		core.FinalizerCleanRefConfigMap,
 	})
@@ -448,6 +513,7 @@ func NewAppInstanceRegistry() *AppInstanceRegistry {
 	r.SetMutateHook(appInstanceMutate)
 	r.SetDecorateHook(appInstanceDecorate)
 	r.SetPreUpdateHook(appInstancePreUpdate)
+	r.SetEncryptedFieldsFunc(appInstanceEncryptedFields)
 	r.SetPostCreateHook(appInstancePostCreate)
 	return r
 }
@@ -496,9 +562,13 @@ func (r AuditRegistry) Record(audit *Audit) error {
 
 // NewAuditRegistry 实例化审计日志存储器
 func NewAuditRegistry() *AuditRegistry {
-	return &AuditRegistry{
+	r := &AuditRegistry{
 		Registry: registry.NewRegistry(newGVK(core.KindAudit), false),
 	}
+	r.SetTTLFunc(func(obj core.ApiObject) time.Duration {
+		return setting.RetentionSetting.Audit
+	})
+	return r
 }
 
 // ConfigMapRegistry 配置字典存储器
@@ -515,7 +585,10 @@ func NewConfigMapRegistry() *ConfigMapRegistry {
 	r.SetDefaultFinalizers([]string{
 		core.FinalizerCleanRevision,
 	})
-	r.SetRevisioner(NewConfigMapRevision())
+	r.SetRevisioner(NewResourceRevision(r, func() int {
+		return setting.RevisionSetting.ConfigMap
+	}))
+	r.SetEncryptedFields("Data.*")
 	return r
 }
 
@@ -570,9 +643,26 @@ func NewEventRegistry() *EventRegistry {
 	r.SetDefaultFinalizers([]string{
 		core.FinalizerCleanRefJob,
 	})
+	r.SetTTLFunc(eventTTL)
+	r.SetIndexer(core.IndexResourceRef, eventResourceRefIndex)
 	return r
 }
 
+// eventResourceRefIndex 按事件所关联的资源建立索引
+func eventResourceRefIndex(obj core.ApiObject) []string {
+	ref := obj.(*Event).Spec.ResourceRef
+	return []string{core.IndexValue(ref.Kind, ref.Namespace, ref.Name)}
+}
+
+// eventTTL 健康检查事件与其他事件分别使用各自的保留时长
+func eventTTL(obj core.ApiObject) time.Duration {
+	event := obj.(*Event)
+	if event.Spec.Action == core.EventActionHealthCheck {
+		return setting.RetentionSetting.HealthCheckEvent
+	}
+	return setting.RetentionSetting.Event
+}
+
 // GPURegistry 显卡存储器
 type GPURegistry struct {
 	registry.Registry
@@ -585,9 +675,30 @@ func (r GPURegistry) GetGPUName(hostRef string, slot int) string {
 
 // NewGPURegistry 实例化显卡存储器
 func NewGPURegistry() *GPURegistry {
-	return &GPURegistry{
+	r := &GPURegistry{
 		Registry: registry.NewRegistry(newGVK(core.KindGPU), false),
 	}
+	r.SetIndexer(core.IndexHostRef, gpuHostRefIndex)
+	r.SetIndexer(core.IndexAppInstanceRef, gpuAppInstanceRefIndex)
+	return r
+}
+
+// gpuHostRefIndex 按显卡所在的主机建立索引
+func gpuHostRefIndex(obj core.ApiObject) []string {
+	gpu := obj.(*GPU)
+	if gpu.Spec.HostRef == "" {
+		return nil
+	}
+	return []string{gpu.Spec.HostRef}
+}
+
+// gpuAppInstanceRefIndex 按显卡所绑定的应用实例建立索引
+func gpuAppInstanceRefIndex(obj core.ApiObject) []string {
+	ref := obj.(*GPU).Spec.AppInstanceModuleRef
+	if ref.Name == "" {
+		return nil
+	}
+	return []string{core.IndexValue(ref.Namespace, ref.Name)}
 }
 
 // HostRegistry 主机存储器
@@ -600,10 +711,10 @@ func NewHostRegistry() *HostRegistry {
 	r := &HostRegistry{
 		Registry: registry.NewRegistry(newGVK(core.KindHost), false),
 	}
-	r.SetDefaultFinalizers([]string{
-		core.FinalizerCleanRefGPU,
-		core.FinalizerCleanRefEvent,
-	})
+	r.SetEncryptedFields("Spec.SSH.Password")
+	r.SetRevisioner(NewResourceRevision(r, func() int {
+		return setting.RevisionSetting.Host
+	}))
 	return r
 }
 
@@ -649,12 +760,22 @@ func NewJobRegistry() *JobRegistry {
 		Registry: registry.NewRegistry(newGVK(core.KindJob), false),
 	}
 	r.SetDefaultFinalizers([]string{
-		core.FinalizerCleanRefConfigMap,
 		core.FinalizerCleanJobWorkDir,
 	})
+	r.SetTTLFunc(jobTTL)
+	r.SetEncryptedFields("Spec.Exec.Ansible.Inventories.*.Value")
 	return r
 }
 
+// jobTTL 仅为已完成或失败的任务设置保留时长
+func jobTTL(obj core.ApiObject) time.Duration {
+	switch obj.GetStatusPhase() {
+	case core.PhaseCompleted, core.PhaseFailed:
+		return setting.RetentionSetting.Job
+	}
+	return 0
+}
+
 // K8sConfigRegistry K8S集群存储器
 // +namespaced=true
 type K8sConfigRegistry struct {
@@ -691,9 +812,9 @@ func NewK8sConfigRegistry() *K8sConfigRegistry {
 	r := &K8sConfigRegistry{
 		Registry: registry.NewRegistry(newGVK(core.KindK8sConfig), true),
 	}
-	r.SetDefaultFinalizers([]string{
-		core.FinalizerCleanRefEvent,
-	})
+	r.SetRevisioner(NewResourceRevision(r, func() int {
+		return setting.RevisionSetting.K8sConfig
+	}))
 	return r
 }
 
@@ -765,6 +886,9 @@ func NewProjectRegistry() *ProjectRegistry {
 		Registry: registry.NewRegistry(newGVK(core.KindProject), false),
 	}
 	r.SetMutateHook(projectMutate)
+	r.SetRevisioner(NewResourceRevision(r, func() int {
+		return setting.RevisionSetting.Project
+	}))
 	return r
 }
 
@@ -778,5 +902,214 @@ func NewRevisionRegistry() *RevisionRegistry {
 	r := &RevisionRegistry{
 		Registry: registry.NewRegistry(newGVK(core.KindRevision), false),
 	}
+	// 修订版本中保存了资源的完整内容，可能包含敏感字段
+	r.SetEncryptedFields("Data")
+	r.SetIndexer(core.IndexResourceRef, revisionResourceRefIndex)
+	return r
+}
+
+// revisionResourceRefIndex 按修订版本所属的资源建立索引
+func revisionResourceRefIndex(obj core.ApiObject) []string {
+	ref := obj.(*Revision).ResourceRef
+	return []string{core.IndexValue(ref.Kind, ref.Namespace, ref.Name)}
+}
+
+// RoleRegistry 角色存储器
+type RoleRegistry struct {
+	registry.Registry
+}
+
+// roleValidate 校验角色的授权规则
+func roleValidate(obj core.ApiObject) error {
+	return validatePolicyRules("Spec.Rules", obj.(*Role).Spec.Rules)
+}
+
+// validatePolicyRules 校验授权规则的操作与资源类型
+func validatePolicyRules(field string, rules []PolicyRule) error {
+	for index, rule := range rules {
+		if len(rule.Verbs) == 0 || len(rule.Kinds) == 0 {
+			return e.InvalidFieldError{Field: fmt.Sprintf("%s[%d]", field, index), Reason: "授权规则的Verbs与Kinds不能为空"}
+		}
+		for _, verb := range rule.Verbs {
+			switch verb {
+			case "*", core.VerbGet, core.VerbList, core.VerbCreate, core.VerbUpdate, core.VerbDelete, core.VerbAction:
+			default:
+				return e.InvalidFieldError{Field: fmt.Sprintf("%s[%d].Verbs", field, index), Reason: fmt.Sprintf("不支持的操作 %s", verb)}
+			}
+		}
+	}
+	return nil
+}
+
+// NewRoleRegistry 实例化角色存储器
+func NewRoleRegistry() *RoleRegistry {
+	r := &RoleRegistry{
+		Registry: registry.NewRegistry(newGVK(core.KindRole), false),
+	}
+	r.SetValidateHook(roleValidate)
+	return r
+}
+
+// RoleBindingRegistry 角色绑定存储器
+type RoleBindingRegistry struct {
+	registry.Registry
+}
+
+// roleBindingValidate 校验角色绑定引用的角色与主体
+func roleBindingValidate(obj core.ApiObject) error {
+	roleBinding := obj.(*RoleBinding)
+	if roleBinding.Spec.RoleRef == "" {
+		return e.InvalidFieldError{Field: "Spec.RoleRef", Reason: "绑定的角色不能为空"}
+	}
+	for index, subject := range roleBinding.Spec.Subjects {
+		switch subject.Kind {
+		case core.SubjectKindUser, core.SubjectKindGroup, core.SubjectKindServiceAccount:
+		default:
+			return e.InvalidFieldError{Field: fmt.Sprintf("Spec.Subjects[%d].Kind", index), Reason: fmt.Sprintf("主体类型只能为%s、%s或%s", core.SubjectKindUser, core.SubjectKindGroup, core.SubjectKindServiceAccount)}
+		}
+		if subject.Name == "" {
+			return e.InvalidFieldError{Field: fmt.Sprintf("Spec.Subjects[%d].Name", index), Reason: "主体名称不能为空"}
+		}
+	}
+	return nil
+}
+
+// roleBindingSubjectIndex 按角色绑定的主体建立索引，索引取值为<主体类型>/<主体名称>
+func roleBindingSubjectIndex(obj core.ApiObject) []string {
+	var values []string
+	for _, subject := range obj.(*RoleBinding).Spec.Subjects {
+		values = append(values, core.IndexValue(subject.Kind, subject.Name))
+	}
+	return values
+}
+
+// NewRoleBindingRegistry 实例化角色绑定存储器
+func NewRoleBindingRegistry() *RoleBindingRegistry {
+	r := &RoleBindingRegistry{
+		Registry: registry.NewRegistry(newGVK(core.KindRoleBinding), false),
+	}
+	r.SetValidateHook(roleBindingValidate)
+	r.SetIndexer(core.IndexSubject, roleBindingSubjectIndex)
+	return r
+}
+
+// ServiceAccountRegistry 服务账号存储器
+type ServiceAccountRegistry struct {
+	registry.Registry
+}
+
+// serviceAccountValidate 校验服务账号的令牌
+func serviceAccountValidate(obj core.ApiObject) error {
+	serviceAccount := obj.(*ServiceAccount)
+	re := regexp.MustCompile(core.ValidNameRegex)
+	names := make(map[string]bool)
+	for index, token := range serviceAccount.Spec.Tokens {
+		field := fmt.Sprintf("Spec.Tokens[%d]", index)
+		if !re.MatchString(token.Name) {
+			return e.InvalidFieldError{Field: field + ".Name", Reason: fmt.Sprintf("令牌名称 %s 不合法", token.Name)}
+		}
+		if names[token.Name] {
+			return e.InvalidFieldError{Field: field + ".Name", Reason: fmt.Sprintf("令牌名称 %s 重复", token.Name)}
+		}
+		names[token.Name] = true
+		if token.Hash == "" {
+			return e.InvalidFieldError{Field: field + ".Hash", Reason: "令牌哈希值不能为空"}
+		}
+		if err := validatePolicyRules(field+".Scopes", token.Scopes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewServiceAccountRegistry 实例化服务账号存储器
+func NewServiceAccountRegistry() *ServiceAccountRegistry {
+	r := &ServiceAccountRegistry{
+		Registry: registry.NewRegistry(newGVK(core.KindServiceAccount), false),
+	}
+	r.SetValidateHook(serviceAccountValidate)
+	return r
+}
+
+// UserRegistry 用户存储器
+type UserRegistry struct {
+	registry.Registry
+}
+
+// userValidate 校验用户密码，客户端不能直接写入bcrypt哈希值，只允许原样写回存储中的哈希值
+func userValidate(obj core.ApiObject) error {
+	user := obj.(*User)
+	if user.Spec.Password == "" {
+		return nil
+	}
+	if _, err := bcrypt.Cost([]byte(user.Spec.Password)); err != nil {
+		return nil
+	}
+	oldObj, err := NewUserRegistry().Get(context.TODO(), "", user.Metadata.Name, core.WithoutDecorate())
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	if oldObj == nil || oldObj.(*User).Spec.Password != user.Spec.Password {
+		return e.InvalidFieldError{Field: "Spec.Password", Reason: "密码不能为哈希值"}
+	}
+	return nil
+}
+
+// userMutate 将明文密码替换为bcrypt哈希值，已经是哈希值的密码保持不变
+func userMutate(obj core.ApiObject) error {
+	user := obj.(*User)
+	if user.Spec.Password == "" {
+		return nil
+	}
+	if _, err := bcrypt.Cost([]byte(user.Spec.Password)); err == nil {
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(user.Spec.Password), bcrypt.DefaultCost)
+	if err != nil {
+		log.Error(err)
+		return err
+	}
+	user.Spec.Password = string(hash)
+	return nil
+}
+
+// userDecorate 获取用户时隐藏密码哈希值
+func userDecorate(obj core.ApiObject) error {
+	obj.(*User).Spec.Password = ""
+	return nil
+}
+
+// userPreCreate 创建用户时必须设置密码
+func userPreCreate(obj core.ApiObject) error {
+	if obj.(*User).Spec.Password == "" {
+		return e.InvalidFieldError{Field: "Spec.Password", Reason: "密码不能为空"}
+	}
+	return nil
+}
+
+// userMerge 更新用户时未设置密码则保留原有密码，密码变更时使已签发的令牌失效
+func userMerge(obj core.ApiObject, oldObj core.ApiObject) error {
+	user := obj.(*User)
+	oldUser := oldObj.(*User)
+	if user.Spec.Password == "" {
+		user.Spec.Password = oldUser.Spec.Password
+	} else if user.Spec.Password != oldUser.Spec.Password {
+		user.Info.TokenGeneration = oldUser.Info.TokenGeneration + 1
+	}
+	return nil
+}
+
+// NewUserRegistry 实例化用户存储器
+func NewUserRegistry() *UserRegistry {
+	r := &UserRegistry{
+		Registry: registry.NewRegistry(newGVK(core.KindUser), false),
+	}
+	r.SetValidateHook(userValidate)
+	r.SetMutateHook(userMutate)
+	r.SetDecorateHook(userDecorate)
+	r.SetPreCreateHook(userPreCreate)
+	r.SetMergeHook(userMerge)
+	r.SetRedactedFields("Spec.Password")
 	return r
 }
@@ -4,22 +4,27 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
 )
 
-// ConfigMapRevision 配置字典修订版本记录器，实现了Revisioner接口
-type ConfigMapRevision struct {
-	kind string
+// ResourceRevision 通用的修订版本记录器，实现了Revisioner接口。
+// 资源的历史快照以Revision资源保存，按资源类型、命名空间、名称与修订版本号查找，资源被删除时由垃圾回收器一同删除
+type ResourceRevision struct {
+	registry  registry.ApiObjectRegistry
+	limitFunc func() int
 }
 
-func (r ConfigMapRevision) SetRevision(ctx context.Context, obj core.ApiObject) error {
-	configMap := obj.(*ConfigMap)
+// SetRevision 将资源的当前内容保存为修订版本，与上个修订版本无差异时不保存，超出保留数量上限时删除最早的修订版本
+func (r ResourceRevision) SetRevision(ctx context.Context, obj core.ApiObject) error {
+	metadata := obj.GetMetadata()
 
 	// 如果与上个版本无差异，则不再创建新的历史版本
-	lastRevision, err := r.GetLastRevision(ctx, configMap.Metadata.Namespace, configMap.Metadata.Name)
+	lastRevision, err := r.GetLastRevision(ctx, metadata.Namespace, metadata.Name)
 	if err != nil {
 		return err
 	}
@@ -27,59 +32,57 @@ func (r ConfigMapRevision) SetRevision(ctx context.Context, obj core.ApiObject)
 		return nil
 	}
 
+	kind := r.registry.GVK().Kind
 	revision := NewRevision()
-	revision.Metadata.Name = fmt.Sprintf("%s-%d-%s", configMap.Metadata.Name, configMap.Metadata.ResourceVersion, configMap.SpecHash())
+	revision.Metadata.Name = revisionName(kind, metadata, obj.SpecHash())
+	revision.Metadata.OwnerReferences = []core.OwnerReference{core.NewOwnerReference(kind, metadata)}
 	revision.ResourceRef = ResourceRef{
-		Kind:      core.KindConfigMap,
-		Namespace: configMap.Metadata.Namespace,
-		Name:      configMap.Metadata.Name,
+		Kind:      kind,
+		Namespace: metadata.Namespace,
+		Name:      metadata.Name,
 	}
-	revision.Revision = configMap.Metadata.ResourceVersion
-	data, err := configMap.ToJSON()
+	revision.Revision = metadata.ResourceVersion
+	// 资源版本号在每次写入时都会累加，上个修订版本之后的版本号均对应当前记录的内容
+	revision.FromRevision = 1
+	if lastRevision != nil {
+		revision.FromRevision = lastRevision.GetMetadata().ResourceVersion + 1
+	}
+	data, err := obj.ToJSON()
 	if err != nil {
 		return err
 	}
 	revision.Data = string(data)
 
 	revisionRegistry := NewRevisionRegistry()
-	if _, err := revisionRegistry.Create(context.TODO(), revision); err != nil {
+	if _, err := revisionRegistry.Create(ctx, revision); err != nil {
 		return err
 	}
 
-	return nil
+	return r.prune(ctx, metadata.Namespace, metadata.Name)
 }
 
-func (r ConfigMapRevision) ListRevisions(ctx context.Context, namespace string, name string) (core.ApiObjectList, error) {
-	configMapRegistry := NewConfigMapRegistry()
-
-	obj, err := configMapRegistry.Get(ctx, namespace, name)
+// ListRevisions 列举资源的所有修订版本，按修订版本号从新到旧排序，资源不存在时返回空
+func (r ResourceRevision) ListRevisions(ctx context.Context, namespace string, name string) (core.ApiObjectList, error) {
+	obj, err := r.registry.Get(ctx, namespace, name)
 	if err != nil {
 		return nil, err
 	} else if obj == nil {
 		return nil, nil
 	}
 
-	revisionRegistry := NewRevisionRegistry()
-
-	revisionList, err := revisionRegistry.List(context.TODO(), "")
+	revisions, err := r.listRevisions(ctx, namespace, name)
 	if err != nil {
 		return nil, err
 	}
 
-	result := []core.ApiObject{}
-	for _, revisionObj := range revisionList {
-		revision := revisionObj.(*Revision)
-		if revision.ResourceRef.Kind == r.kind && revision.ResourceRef.Namespace == namespace && revision.ResourceRef.Name == name {
-			item, err := New(r.kind)
-			if err != nil {
-				return nil, err
-			}
-			if err := item.FromJSON([]byte(revision.Data)); err != nil {
-				return nil, err
-			}
-
-			result = append(result, item)
+	result := core.ApiObjectList{}
+	for _, revision := range revisions {
+		item, err := r.registry.NewObject([]byte(revision.Data))
+		if err != nil {
+			log.Error(err)
+			return nil, err
 		}
+		result = append(result, item)
 	}
 
 	sort.Sort(sort.Reverse(core.SortByRevision(result)))
@@ -87,47 +90,19 @@ func (r ConfigMapRevision) ListRevisions(ctx context.Context, namespace string,
 	return result, nil
 }
 
-func (r ConfigMapRevision) GetRevision(ctx context.Context, namespace string, name string, revision int) (core.ApiObject, error) {
-	configMapRegistry := NewConfigMapRegistry()
-
-	obj, err := configMapRegistry.Get(ctx, namespace, name)
+// GetRevision 获取资源指定编号的修订版本，修订版本不存在时返回空
+func (r ResourceRevision) GetRevision(ctx context.Context, namespace string, name string, revision int) (core.ApiObject, error) {
+	rev, err := r.getRevision(ctx, namespace, name, revision)
 	if err != nil {
 		return nil, err
-	} else if obj == nil {
-		return nil, nil
-	}
-	configMap := obj.(*ConfigMap)
-	if revision >= configMap.Metadata.ResourceVersion {
+	} else if rev == nil {
 		return nil, nil
 	}
-
-	revisionRegistry := NewRevisionRegistry()
-
-	revisionList, err := revisionRegistry.List(ctx, "")
-	if err != nil {
-		return nil, err
-	}
-
-	for _, revisionObj := range revisionList {
-		rev := revisionObj.(*Revision)
-		if rev.ResourceRef.Kind == r.kind && rev.ResourceRef.Namespace == namespace && rev.ResourceRef.Name == name && rev.Revision == revision {
-			result, err := New(r.kind)
-			if err != nil {
-				return nil, err
-			}
-			if err := result.FromJSON([]byte(rev.Data)); err != nil {
-				return nil, err
-			}
-
-			return result, nil
-		}
-	}
-	return nil, nil
+	return r.registry.NewObject([]byte(rev.Data))
 }
 
-func (r ConfigMapRevision) RevertRevision(ctx context.Context, namespace string, name string, revision int) (core.ApiObject, error) {
-	configMapRegistry := NewConfigMapRegistry()
-
+// RevertRevision 将资源的内容回退到指定的修订版本
+func (r ResourceRevision) RevertRevision(ctx context.Context, namespace string, name string, revision int) (core.ApiObject, error) {
 	obj, err := r.GetRevision(ctx, namespace, name, revision)
 	if err != nil {
 		log.Error(err)
@@ -136,10 +111,15 @@ func (r ConfigMapRevision) RevertRevision(ctx context.Context, namespace string,
 		return nil, nil
 	}
 
-	return configMapRegistry.Update(ctx, obj)
+	// 历史版本中记录的资源版本号已过期，回滚时不进行资源版本号校验
+	metadata := obj.GetMetadata()
+	metadata.ResourceVersion = 0
+	obj.SetMetadata(metadata)
+	return r.registry.Update(ctx, obj)
 }
 
-func (r ConfigMapRevision) GetLastRevision(ctx context.Context, namespace string, name string) (core.ApiObject, error) {
+// GetLastRevision 获取资源最新的修订版本
+func (r ResourceRevision) GetLastRevision(ctx context.Context, namespace string, name string) (core.ApiObject, error) {
 	objs, err := r.ListRevisions(ctx, namespace, name)
 	if err != nil {
 		return nil, err
@@ -151,85 +131,129 @@ func (r ConfigMapRevision) GetLastRevision(ctx context.Context, namespace string
 	return nil, nil
 }
 
-func (r ConfigMapRevision) DeleteRevision(ctx context.Context, namespace string, name string, revision int) (core.ApiObject, error) {
-	configMapRegistry := NewConfigMapRegistry()
-
-	obj, err := configMapRegistry.Get(ctx, namespace, name)
+// DeleteRevision 删除资源指定编号的修订版本，并返回被删除的修订版本
+func (r ResourceRevision) DeleteRevision(ctx context.Context, namespace string, name string, revision int) (core.ApiObject, error) {
+	rev, err := r.getRevision(ctx, namespace, name, revision)
 	if err != nil {
 		return nil, err
-	} else if obj == nil {
+	} else if rev == nil {
 		return nil, nil
 	}
 
-	resourceVersion := obj.GetMetadata().ResourceVersion
-	if revision >= resourceVersion || resourceVersion <= 0 {
-		return nil, nil
+	result, err := r.registry.NewObject([]byte(rev.Data))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := NewRevisionRegistry().Delete(ctx, "", rev.Metadata.Name); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteAllRevisions 删除资源的所有修订版本
+func (r ResourceRevision) DeleteAllRevisions(ctx context.Context, namespace string, name string) error {
+	revisions, err := r.listRevisions(ctx, namespace, name)
+	if err != nil {
+		return err
 	}
 
 	revisionRegistry := NewRevisionRegistry()
+	for _, rev := range revisions {
+		if _, err := revisionRegistry.Delete(ctx, "", rev.Metadata.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	revisionList, err := revisionRegistry.List(ctx, "")
+// getRevision 获取资源指定编号的修订版本记录，编号也可以是修订版本内容生效期间的任一资源版本号。资源不存在或修订版本号不早于资源当前版本号时返回空
+func (r ResourceRevision) getRevision(ctx context.Context, namespace string, name string, revision int) (*Revision, error) {
+	obj, err := r.registry.Get(ctx, namespace, name)
 	if err != nil {
 		return nil, err
+	} else if obj == nil {
+		return nil, nil
+	}
+	if revision >= obj.GetMetadata().ResourceVersion {
+		return nil, nil
 	}
 
-	for _, revisionObj := range revisionList {
-		rev := revisionObj.(*Revision)
-		if rev.ResourceRef.Kind == r.kind && rev.ResourceRef.Namespace == namespace && rev.ResourceRef.Name == name && rev.Revision == revision {
-			result, err := New(r.kind)
-			if err != nil {
-				return nil, err
-			}
-			if err := result.FromJSON([]byte(rev.Data)); err != nil {
-				return nil, err
-			}
-
-			if _, err := revisionRegistry.Delete(ctx, "", rev.Metadata.Name); err != nil {
-				return nil, err
-			}
-
-			return result, nil
+	revisions, err := r.listRevisions(ctx, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, rev := range revisions {
+		if rev.Revision == revision || (rev.FromRevision != 0 && rev.FromRevision <= revision && revision < rev.Revision) {
+			return rev, nil
 		}
 	}
 	return nil, nil
 }
 
-func (r ConfigMapRevision) DeleteAllRevisions(ctx context.Context, namespace string, name string) error {
-	configMapRegistry := NewConfigMapRegistry()
-
-	obj, err := configMapRegistry.Get(ctx, namespace, name)
+// listRevisions 通过资源引用索引获取资源的所有修订版本记录，按修订版本号从旧到新排序
+func (r ResourceRevision) listRevisions(ctx context.Context, namespace string, name string) ([]*Revision, error) {
+	if !r.registry.Namespaced() {
+		namespace = ""
+	}
+	objs, err := NewRevisionRegistry().ListByIndex(ctx, core.IndexResourceRef, core.IndexValue(r.registry.GVK().Kind, namespace, name))
 	if err != nil {
-		return err
-	} else if obj == nil {
-		return nil
+		return nil, err
 	}
 
-	resourceVersion := obj.GetMetadata().ResourceVersion
-	if resourceVersion <= 0 {
-		return nil
+	revisions := []*Revision{}
+	for _, obj := range objs {
+		revisions = append(revisions, obj.(*Revision))
 	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
 
-	revisionRegistry := NewRevisionRegistry()
+// prune 删除超出保留数量上限的最早的修订版本
+func (r ResourceRevision) prune(ctx context.Context, namespace string, name string) error {
+	if r.limitFunc == nil {
+		return nil
+	}
+	limit := r.limitFunc()
+	if limit <= 0 {
+		return nil
+	}
 
-	revisionList, err := revisionRegistry.List(ctx, "")
+	revisions, err := r.listRevisions(ctx, namespace, name)
 	if err != nil {
 		return err
 	}
-
-	for _, revisionObj := range revisionList {
-		rev := revisionObj.(*Revision)
-		if rev.ResourceRef.Kind == r.kind && rev.ResourceRef.Namespace == namespace && rev.ResourceRef.Name == name {
-			if _, err := revisionRegistry.Delete(ctx, "", rev.Metadata.Name); err != nil {
-				return err
-			}
+	revisionRegistry := NewRevisionRegistry()
+	for i := 0; i < len(revisions)-limit; i++ {
+		log.Debugf("prune revision %s", revisions[i].Metadata.Name)
+		if _, err := revisionRegistry.Delete(ctx, "", revisions[i].Metadata.Name); err != nil {
+			log.Error(err)
+			return err
 		}
 	}
 	return nil
 }
 
-// NewConfigMapRevision 实例化配置字典修订历史记录器
-func NewConfigMapRevision() *ConfigMapRevision {
-	return &ConfigMapRevision{
-		kind: core.KindConfigMap,
+// revisionName 生成修订版本的名称，格式为<kind>-[<namespace>-]<name>-<revision>-<hash>
+func revisionName(kind string, metadata core.Metadata, hash string) string {
+	parts := []string{kind}
+	if metadata.Namespace != "" {
+		parts = append(parts, metadata.Namespace)
+	}
+	parts = append(parts, metadata.Name, fmt.Sprint(metadata.ResourceVersion), hash)
+	return strings.Join(parts, "-")
+}
+
+// NewResourceRevision 实例化通用的修订版本记录器，limitFunc返回修订版本的保留数量上限，为空或返回0时不限制
+func NewResourceRevision(r registry.ApiObjectRegistry, limitFunc func() int) *ResourceRevision {
+	return &ResourceRevision{
+		registry:  r,
+		limitFunc: limitFunc,
 	}
 }
+
+// NewConfigMapRevision 实例化配置字典修订历史记录器
+func NewConfigMapRevision() registry.Revisioner {
+	return NewConfigMapRegistry().Revisioner()
+}
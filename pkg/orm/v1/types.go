@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"time"
 
+	"golang.org/x/crypto/bcrypt"
+
 	"github.com/wujie1993/waves/pkg/orm/core"
 )
 
@@ -13,6 +15,32 @@ const (
 	ApiVersion = "v1"
 )
 
+type AdmissionConfig struct {
+	core.BaseApiObj `json:",inline" yaml:",inline"`
+	Spec            AdmissionConfigSpec
+}
+
+type AdmissionConfigSpec struct {
+	Webhooks []AdmissionWebhook
+}
+
+type AdmissionWebhook struct {
+	// 准入控制钩子名称，用于在拒绝信息中标识钩子
+	Name string
+	// 接收准入审查请求的HTTP地址
+	URL string
+	// 匹配的资源类型，为空或包含*时匹配所有资源类型
+	Kinds []string
+	// 匹配的操作，可选值为create、update与delete，为空或包含*时匹配所有操作
+	Operations []string
+	// 匹配的命名空间，为空或包含*时匹配所有命名空间
+	Namespaces []string
+	// 调用超时时间，默认为10秒，最大为30秒
+	TimeoutSeconds int
+	// 调用失败时的处理策略，Fail为拒绝请求，Ignore为忽略该钩子，默认为Fail
+	FailurePolicy string
+}
+
 type App struct {
 	core.BaseApiObj `json:",inline" yaml:",inline"`
 	Spec            AppSpec
@@ -149,9 +177,11 @@ type AuditSpec struct {
 	Action      string
 	Msg         string
 	SourceIP    string
-	ReqBody     string
-	RespBody    string
-	StatusCode  int
+	// 发起请求的用户，未启用认证时为空
+	User       string
+	ReqBody    string
+	RespBody   string
+	StatusCode int
 }
 
 type ValueFrom struct {
@@ -390,7 +420,117 @@ type Revision struct {
 	core.BaseApiObj `json:",inline" yaml:",inline"`
 	ResourceRef     ResourceRef
 	Revision        int
-	Data            string
+	// 修订版本内容开始生效时的资源版本号，修订版本的内容对应资源版本号FromRevision至Revision之间的所有版本
+	FromRevision int
+	Data         string
+}
+
+type Role struct {
+	core.BaseApiObj `json:",inline" yaml:",inline"`
+	Spec            RoleSpec
+}
+
+type RoleSpec struct {
+	Rules []PolicyRule
+}
+
+// PolicyRule 授权规则，Verbs、Kinds与Namespaces中包含*时匹配所有取值
+type PolicyRule struct {
+	// 允许的操作，可选值为get、list、create、update、delete与action
+	Verbs []string
+	// 允许操作的资源类型
+	Kinds []string
+	// 允许操作的命名空间，为空时不限制，不为空时不匹配跨命名空间或不属于命名空间的请求
+	Namespaces []string
+}
+
+type RoleBinding struct {
+	core.BaseApiObj `json:",inline" yaml:",inline"`
+	Spec            RoleBindingSpec
+}
+
+type RoleBindingSpec struct {
+	// 绑定的角色名称
+	RoleRef string
+	// 被授予角色的用户或用户组
+	Subjects []Subject
+	// 授权范围所属的项目，为空时授权所有资源，否则只授权项目所引用的命名空间中的资源以及项目本身
+	ProjectRef string
+}
+
+type Subject struct {
+	// 主体类型，可选值为User、Group与ServiceAccount
+	Kind string
+	Name string
+}
+
+type ServiceAccount struct {
+	core.BaseApiObj `json:",inline" yaml:",inline"`
+	Spec            ServiceAccountSpec
+	Info            ServiceAccountInfo
+}
+
+type ServiceAccountSpec struct {
+	// 禁用的服务账号的所有令牌均无法使用
+	Disabled bool
+	Tokens   []ServiceAccountToken
+}
+
+type ServiceAccountToken struct {
+	// 令牌名称，在服务账号内唯一
+	Name string
+	// 令牌的SHA-256哈希值，令牌明文仅在创建时返回
+	Hash string
+	// 过期时间，为零值时永不过期
+	ExpireTime time.Time
+	// 令牌的授权范围，为空时拥有服务账号的全部权限，否则只能执行同时被服务账号的角色与该范围授权的操作
+	Scopes []PolicyRule
+}
+
+type ServiceAccountInfo struct {
+	// 各令牌的最近使用时间，以令牌名称为键
+	TokensLastUsed map[string]time.Time
+}
+
+type User struct {
+	core.BaseApiObj `json:",inline" yaml:",inline"`
+	Spec            UserSpec
+	Info            UserInfo
+}
+
+type UserSpec struct {
+	// 登录密码，写入时传入明文，存储为bcrypt哈希值，更新时为空则保留原有密码
+	Password string
+	// 禁用的用户无法登录，已签发的令牌也会失效
+	Disabled bool
+	// 用户所属的用户组，用于角色绑定
+	Groups []string
+}
+
+type UserInfo struct {
+	// 令牌代数，签发的令牌中记录了签发时的代数，登出时累加以使已签发的令牌全部失效
+	TokenGeneration int
+}
+
+// SpecEncode 序列化Spec字段的内容
+func (obj AdmissionConfig) SpecEncode() ([]byte, error) {
+	return json.Marshal(&obj.Spec)
+}
+
+// SpecDecode 反序列化Spec字段的内容
+func (obj *AdmissionConfig) SpecDecode(data []byte) error {
+	return json.Unmarshal(data, &obj.Spec)
+}
+
+// SpecReset 清空Spec字段的内容
+func (obj *AdmissionConfig) SpecReset() {
+	obj.Spec = AdmissionConfigSpec{}
+}
+
+// SpecHash 计算Spec字段中的"有效"内容哈希值
+func (obj AdmissionConfig) SpecHash() string {
+	data, _ := json.Marshal(&obj.Spec)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
 }
 
 // SpecEncode 序列化Spec字段的内容
@@ -403,6 +543,11 @@ func (obj *AppInstance) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *AppInstance) SpecReset() {
+	obj.Spec = AppInstanceSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj AppInstance) SpecHash() string {
 	for moduleIndex := range obj.Spec.Modules {
@@ -423,6 +568,11 @@ func (obj *App) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *App) SpecReset() {
+	obj.Spec = AppSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj App) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -439,6 +589,11 @@ func (obj *Audit) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *Audit) SpecReset() {
+	obj.Spec = AuditSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj Audit) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -455,6 +610,11 @@ func (obj *ConfigMap) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Data)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *ConfigMap) SpecReset() {
+	obj.Data = nil
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj ConfigMap) SpecHash() string {
 	data, _ := json.Marshal(&obj.Data)
@@ -471,6 +631,11 @@ func (obj *Event) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *Event) SpecReset() {
+	obj.Spec = EventSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj Event) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -487,6 +652,11 @@ func (obj *GPU) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *GPU) SpecReset() {
+	obj.Spec = GPUSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj GPU) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -503,6 +673,11 @@ func (obj *Host) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec.SSH)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *Host) SpecReset() {
+	obj.Spec.SSH = HostSSH{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj Host) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -519,6 +694,11 @@ func (obj *Job) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *Job) SpecReset() {
+	obj.Spec = JobSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj Job) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -535,6 +715,11 @@ func (obj *K8sConfig) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *K8sConfig) SpecReset() {
+	obj.Spec = K8sYaml{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj K8sConfig) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -566,6 +751,11 @@ func (obj *Pkg) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *Pkg) SpecReset() {
+	obj.Spec = PkgSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj Pkg) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -603,6 +793,90 @@ func (obj Revision) SpecHash() string {
 	return ""
 }
 
+// SpecEncode 序列化Spec字段的内容
+func (obj Role) SpecEncode() ([]byte, error) {
+	return json.Marshal(&obj.Spec)
+}
+
+// SpecDecode 反序列化Spec字段的内容
+func (obj *Role) SpecDecode(data []byte) error {
+	return json.Unmarshal(data, &obj.Spec)
+}
+
+// SpecReset 清空Spec字段的内容
+func (obj *Role) SpecReset() {
+	obj.Spec = RoleSpec{}
+}
+
+// SpecHash 计算Spec字段中的"有效"内容哈希值
+func (obj Role) SpecHash() string {
+	data, _ := json.Marshal(&obj.Spec)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// SpecEncode 序列化Spec字段的内容
+func (obj RoleBinding) SpecEncode() ([]byte, error) {
+	return json.Marshal(&obj.Spec)
+}
+
+// SpecDecode 反序列化Spec字段的内容
+func (obj *RoleBinding) SpecDecode(data []byte) error {
+	return json.Unmarshal(data, &obj.Spec)
+}
+
+// SpecReset 清空Spec字段的内容
+func (obj *RoleBinding) SpecReset() {
+	obj.Spec = RoleBindingSpec{}
+}
+
+// SpecHash 计算Spec字段中的"有效"内容哈希值
+func (obj RoleBinding) SpecHash() string {
+	data, _ := json.Marshal(&obj.Spec)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// SpecEncode 序列化Spec字段的内容
+func (obj ServiceAccount) SpecEncode() ([]byte, error) {
+	return json.Marshal(&obj.Spec)
+}
+
+// SpecDecode 反序列化Spec字段的内容
+func (obj *ServiceAccount) SpecDecode(data []byte) error {
+	return json.Unmarshal(data, &obj.Spec)
+}
+
+// SpecReset 清空Spec字段的内容
+func (obj *ServiceAccount) SpecReset() {
+	obj.Spec = ServiceAccountSpec{}
+}
+
+// SpecHash 计算Spec字段中的"有效"内容哈希值
+func (obj ServiceAccount) SpecHash() string {
+	data, _ := json.Marshal(&obj.Spec)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// SpecEncode 序列化Spec字段的内容
+func (obj User) SpecEncode() ([]byte, error) {
+	return json.Marshal(&obj.Spec)
+}
+
+// SpecDecode 反序列化Spec字段的内容
+func (obj *User) SpecDecode(data []byte) error {
+	return json.Unmarshal(data, &obj.Spec)
+}
+
+// SpecReset 清空Spec字段的内容
+func (obj *User) SpecReset() {
+	obj.Spec = UserSpec{}
+}
+
+// SpecHash 计算Spec字段中的"有效"内容哈希值
+func (obj User) SpecHash() string {
+	data, _ := json.Marshal(&obj.Spec)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
 func (s AppInstanceSpec) GetModuleArgValue(moduleName, argName string) (interface{}, bool) {
 	for _, module := range s.Modules {
 		if module.Name == moduleName {
@@ -684,6 +958,14 @@ func (obj AppVersion) GetModule(moduleName string) (AppModule, bool) {
 	return AppModule{}, false
 }
 
+// NewAdmissionConfig 实例化准入控制配置
+func NewAdmissionConfig() *AdmissionConfig {
+	admissionConfig := new(AdmissionConfig)
+	admissionConfig.Init(ApiVersion, core.KindAdmissionConfig)
+	admissionConfig.Spec.Webhooks = []AdmissionWebhook{}
+	return admissionConfig
+}
+
 // NewApp 实例化应用
 func NewApp() *App {
 	app := new(App)
@@ -784,3 +1066,55 @@ func NewRevision() *Revision {
 	revision.Init(ApiVersion, core.KindRevision)
 	return revision
 }
+
+// CheckPassword 校验明文密码是否与用户的密码哈希值一致
+func (obj User) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(obj.Spec.Password), []byte(password)) == nil
+}
+
+// NewRole 实例化角色
+func NewRole() *Role {
+	role := new(Role)
+	role.Init(ApiVersion, core.KindRole)
+	role.Spec.Rules = []PolicyRule{}
+	return role
+}
+
+// NewRoleBinding 实例化角色绑定
+func NewRoleBinding() *RoleBinding {
+	roleBinding := new(RoleBinding)
+	roleBinding.Init(ApiVersion, core.KindRoleBinding)
+	roleBinding.Spec.Subjects = []Subject{}
+	return roleBinding
+}
+
+// HashServiceAccountToken 计算令牌明文的SHA-256哈希值
+func HashServiceAccountToken(token string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// GetToken 获取指定名称的令牌，不存在时返回nil
+func (obj ServiceAccount) GetToken(name string) *ServiceAccountToken {
+	for i := range obj.Spec.Tokens {
+		if obj.Spec.Tokens[i].Name == name {
+			return &obj.Spec.Tokens[i]
+		}
+	}
+	return nil
+}
+
+// NewServiceAccount 实例化服务账号
+func NewServiceAccount() *ServiceAccount {
+	serviceAccount := new(ServiceAccount)
+	serviceAccount.Init(ApiVersion, core.KindServiceAccount)
+	serviceAccount.Spec.Tokens = []ServiceAccountToken{}
+	serviceAccount.Info.TokensLastUsed = make(map[string]time.Time)
+	return serviceAccount
+}
+
+// NewUser 实例化用户
+func NewUser() *User {
+	user := new(User)
+	user.Init(ApiVersion, core.KindUser)
+	return user
+}
@@ -6,6 +6,23 @@ import (
 	"github.com/wujie1993/waves/pkg/orm/core"
 )
 
+// DeepCopyInto is auto generated by codegen, copy public fields into the *AdmissionConfig
+func (src AdmissionConfig) DeepCopyInto(dst *AdmissionConfig) error {
+	return core.DeepCopy(src, dst)
+}
+
+// DeepCopy is auto generated by codegen, create and copy public fields into the new *AdmissionConfig
+func (src AdmissionConfig) DeepCopy() *AdmissionConfig {
+	dst := new(AdmissionConfig)
+	src.DeepCopyInto(dst)
+	return dst
+}
+
+// DeepCopyApiObject is auto generated by codegen, deep copy and return as ApiObject
+func (src AdmissionConfig) DeepCopyApiObject() core.ApiObject {
+	return src.DeepCopy()
+}
+
 // DeepCopyInto is auto generated by codegen, copy public fields into the *App
 func (src App) DeepCopyInto(dst *App) error {
 	return core.DeepCopy(src, dst)
@@ -226,3 +243,71 @@ func (src Revision) DeepCopy() *Revision {
 func (src Revision) DeepCopyApiObject() core.ApiObject {
 	return src.DeepCopy()
 }
+
+// DeepCopyInto is auto generated by codegen, copy public fields into the *Role
+func (src Role) DeepCopyInto(dst *Role) error {
+	return core.DeepCopy(src, dst)
+}
+
+// DeepCopy is auto generated by codegen, create and copy public fields into the new *Role
+func (src Role) DeepCopy() *Role {
+	dst := new(Role)
+	src.DeepCopyInto(dst)
+	return dst
+}
+
+// DeepCopyApiObject is auto generated by codegen, deep copy and return as ApiObject
+func (src Role) DeepCopyApiObject() core.ApiObject {
+	return src.DeepCopy()
+}
+
+// DeepCopyInto is auto generated by codegen, copy public fields into the *RoleBinding
+func (src RoleBinding) DeepCopyInto(dst *RoleBinding) error {
+	return core.DeepCopy(src, dst)
+}
+
+// DeepCopy is auto generated by codegen, create and copy public fields into the new *RoleBinding
+func (src RoleBinding) DeepCopy() *RoleBinding {
+	dst := new(RoleBinding)
+	src.DeepCopyInto(dst)
+	return dst
+}
+
+// DeepCopyApiObject is auto generated by codegen, deep copy and return as ApiObject
+func (src RoleBinding) DeepCopyApiObject() core.ApiObject {
+	return src.DeepCopy()
+}
+
+// DeepCopyInto is auto generated by codegen, copy public fields into the *ServiceAccount
+func (src ServiceAccount) DeepCopyInto(dst *ServiceAccount) error {
+	return core.DeepCopy(src, dst)
+}
+
+// DeepCopy is auto generated by codegen, create and copy public fields into the new *ServiceAccount
+func (src ServiceAccount) DeepCopy() *ServiceAccount {
+	dst := new(ServiceAccount)
+	src.DeepCopyInto(dst)
+	return dst
+}
+
+// DeepCopyApiObject is auto generated by codegen, deep copy and return as ApiObject
+func (src ServiceAccount) DeepCopyApiObject() core.ApiObject {
+	return src.DeepCopy()
+}
+
+// DeepCopyInto is auto generated by codegen, copy public fields into the *User
+func (src User) DeepCopyInto(dst *User) error {
+	return core.DeepCopy(src, dst)
+}
+
+// DeepCopy is auto generated by codegen, create and copy public fields into the new *User
+func (src User) DeepCopy() *User {
+	dst := new(User)
+	src.DeepCopyInto(dst)
+	return dst
+}
+
+// DeepCopyApiObject is auto generated by codegen, deep copy and return as ApiObject
+func (src User) DeepCopyApiObject() core.ApiObject {
+	return src.DeepCopy()
+}
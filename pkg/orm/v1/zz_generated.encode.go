@@ -8,6 +8,31 @@ import (
 	"github.com/ghodss/yaml"
 )
 
+// ToJSON is auto generated by codegen, marshal to json bytes
+func (obj AdmissionConfig) ToJSON() ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// ToJSONPretty is auto generated by codegen, marshal to json bytes with pretty format
+func (obj AdmissionConfig) ToJSONPretty() ([]byte, error) {
+	return json.MarshalIndent(obj, "", "\t")
+}
+
+// FromJSON is auto generated by codegen, unmarshal from json bytes
+func (obj *AdmissionConfig) FromJSON(data []byte) error {
+	return json.Unmarshal(data, obj)
+}
+
+// ToYAML is auto generated by codegen, marshal to yaml bytes
+func (obj AdmissionConfig) ToYAML() ([]byte, error) {
+	return yaml.Marshal(obj)
+}
+
+// FromYAML is auto generated by codegen, unmarshal from yaml bytes
+func (obj *AdmissionConfig) FromYAML(data []byte) error {
+	return yaml.Unmarshal(data, obj)
+}
+
 // ToJSON is auto generated by codegen, marshal to json bytes
 func (obj App) ToJSON() ([]byte, error) {
 	return json.Marshal(obj)
@@ -332,3 +357,103 @@ func (obj Revision) ToYAML() ([]byte, error) {
 func (obj *Revision) FromYAML(data []byte) error {
 	return yaml.Unmarshal(data, obj)
 }
+
+// ToJSON is auto generated by codegen, marshal to json bytes
+func (obj Role) ToJSON() ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// ToJSONPretty is auto generated by codegen, marshal to json bytes with pretty format
+func (obj Role) ToJSONPretty() ([]byte, error) {
+	return json.MarshalIndent(obj, "", "\t")
+}
+
+// FromJSON is auto generated by codegen, unmarshal from json bytes
+func (obj *Role) FromJSON(data []byte) error {
+	return json.Unmarshal(data, obj)
+}
+
+// ToYAML is auto generated by codegen, marshal to yaml bytes
+func (obj Role) ToYAML() ([]byte, error) {
+	return yaml.Marshal(obj)
+}
+
+// FromYAML is auto generated by codegen, unmarshal from yaml bytes
+func (obj *Role) FromYAML(data []byte) error {
+	return yaml.Unmarshal(data, obj)
+}
+
+// ToJSON is auto generated by codegen, marshal to json bytes
+func (obj RoleBinding) ToJSON() ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// ToJSONPretty is auto generated by codegen, marshal to json bytes with pretty format
+func (obj RoleBinding) ToJSONPretty() ([]byte, error) {
+	return json.MarshalIndent(obj, "", "\t")
+}
+
+// FromJSON is auto generated by codegen, unmarshal from json bytes
+func (obj *RoleBinding) FromJSON(data []byte) error {
+	return json.Unmarshal(data, obj)
+}
+
+// ToYAML is auto generated by codegen, marshal to yaml bytes
+func (obj RoleBinding) ToYAML() ([]byte, error) {
+	return yaml.Marshal(obj)
+}
+
+// FromYAML is auto generated by codegen, unmarshal from yaml bytes
+func (obj *RoleBinding) FromYAML(data []byte) error {
+	return yaml.Unmarshal(data, obj)
+}
+
+// ToJSON is auto generated by codegen, marshal to json bytes
+func (obj ServiceAccount) ToJSON() ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// ToJSONPretty is auto generated by codegen, marshal to json bytes with pretty format
+func (obj ServiceAccount) ToJSONPretty() ([]byte, error) {
+	return json.MarshalIndent(obj, "", "\t")
+}
+
+// FromJSON is auto generated by codegen, unmarshal from json bytes
+func (obj *ServiceAccount) FromJSON(data []byte) error {
+	return json.Unmarshal(data, obj)
+}
+
+// ToYAML is auto generated by codegen, marshal to yaml bytes
+func (obj ServiceAccount) ToYAML() ([]byte, error) {
+	return yaml.Marshal(obj)
+}
+
+// FromYAML is auto generated by codegen, unmarshal from yaml bytes
+func (obj *ServiceAccount) FromYAML(data []byte) error {
+	return yaml.Unmarshal(data, obj)
+}
+
+// ToJSON is auto generated by codegen, marshal to json bytes
+func (obj User) ToJSON() ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// ToJSONPretty is auto generated by codegen, marshal to json bytes with pretty format
+func (obj User) ToJSONPretty() ([]byte, error) {
+	return json.MarshalIndent(obj, "", "\t")
+}
+
+// FromJSON is auto generated by codegen, unmarshal from json bytes
+func (obj *User) FromJSON(data []byte) error {
+	return json.Unmarshal(data, obj)
+}
+
+// ToYAML is auto generated by codegen, marshal to yaml bytes
+func (obj User) ToYAML() ([]byte, error) {
+	return yaml.Marshal(obj)
+}
+
+// FromYAML is auto generated by codegen, unmarshal from yaml bytes
+func (obj *User) FromYAML(data []byte) error {
+	return yaml.Unmarshal(data, obj)
+}
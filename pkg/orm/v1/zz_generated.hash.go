@@ -8,6 +8,11 @@ import (
 	"fmt"
 )
 
+func (obj AdmissionConfig) Sha256() string {
+	data, _ := json.Marshal(obj)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
 func (obj App) Sha256() string {
 	data, _ := json.Marshal(obj)
 	return fmt.Sprintf("%x", sha256.Sum256(data))
@@ -72,3 +77,23 @@ func (obj Revision) Sha256() string {
 	data, _ := json.Marshal(obj)
 	return fmt.Sprintf("%x", sha256.Sum256(data))
 }
+
+func (obj Role) Sha256() string {
+	data, _ := json.Marshal(obj)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func (obj RoleBinding) Sha256() string {
+	data, _ := json.Marshal(obj)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func (obj ServiceAccount) Sha256() string {
+	data, _ := json.Marshal(obj)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+func (obj User) Sha256() string {
+	data, _ := json.Marshal(obj)
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
@@ -11,37 +11,47 @@ var helper Helper
 
 func init() {
 	helper = Helper{
-		App:         NewAppRegistry(),
-		AppInstance: NewAppInstanceRegistry(),
-		Audit:       NewAuditRegistry(),
-		ConfigMap:   NewConfigMapRegistry(),
-		Event:       NewEventRegistry(),
-		GPU:         NewGPURegistry(),
-		Host:        NewHostRegistry(),
-		Job:         NewJobRegistry(),
-		K8sConfig:   NewK8sConfigRegistry(),
-		Namespace:   NewNamespaceRegistry(),
-		Pkg:         NewPkgRegistry(),
-		Project:     NewProjectRegistry(),
-		Revision:    NewRevisionRegistry(),
+		AdmissionConfig: NewAdmissionConfigRegistry(),
+		App:             NewAppRegistry(),
+		AppInstance:     NewAppInstanceRegistry(),
+		Audit:           NewAuditRegistry(),
+		ConfigMap:       NewConfigMapRegistry(),
+		Event:           NewEventRegistry(),
+		GPU:             NewGPURegistry(),
+		Host:            NewHostRegistry(),
+		Job:             NewJobRegistry(),
+		K8sConfig:       NewK8sConfigRegistry(),
+		Namespace:       NewNamespaceRegistry(),
+		Pkg:             NewPkgRegistry(),
+		Project:         NewProjectRegistry(),
+		Revision:        NewRevisionRegistry(),
+		Role:            NewRoleRegistry(),
+		RoleBinding:     NewRoleBindingRegistry(),
+		ServiceAccount:  NewServiceAccountRegistry(),
+		User:            NewUserRegistry(),
 	}
 }
 
 // Helper 对v1版本所有实体对象的操作封装
 type Helper struct {
-	App         *AppRegistry
-	AppInstance *AppInstanceRegistry
-	Audit       *AuditRegistry
-	ConfigMap   *ConfigMapRegistry
-	Event       *EventRegistry
-	GPU         *GPURegistry
-	Host        *HostRegistry
-	Job         *JobRegistry
-	K8sConfig   *K8sConfigRegistry
-	Namespace   *NamespaceRegistry
-	Pkg         *PkgRegistry
-	Project     *ProjectRegistry
-	Revision    *RevisionRegistry
+	AdmissionConfig *AdmissionConfigRegistry
+	App             *AppRegistry
+	AppInstance     *AppInstanceRegistry
+	Audit           *AuditRegistry
+	ConfigMap       *ConfigMapRegistry
+	Event           *EventRegistry
+	GPU             *GPURegistry
+	Host            *HostRegistry
+	Job             *JobRegistry
+	K8sConfig       *K8sConfigRegistry
+	Namespace       *NamespaceRegistry
+	Pkg             *PkgRegistry
+	Project         *ProjectRegistry
+	Revision        *RevisionRegistry
+	Role            *RoleRegistry
+	RoleBinding     *RoleBindingRegistry
+	ServiceAccount  *ServiceAccountRegistry
+	User            *UserRegistry
 }
 
 func GetHelper() Helper {
@@ -51,6 +61,8 @@ func GetHelper() Helper {
 // New 创建一个新的实体对象
 func New(kind string) (core.ApiObject, error) {
 	switch kind {
+	case core.KindAdmissionConfig:
+		return NewAdmissionConfig(), nil
 	case core.KindApp:
 		return NewApp(), nil
 	case core.KindAppInstance:
@@ -77,6 +89,14 @@ func New(kind string) (core.ApiObject, error) {
 		return NewProject(), nil
 	case core.KindRevision:
 		return NewRevision(), nil
+	case core.KindRole:
+		return NewRole(), nil
+	case core.KindRoleBinding:
+		return NewRoleBinding(), nil
+	case core.KindServiceAccount:
+		return NewServiceAccount(), nil
+	case core.KindUser:
+		return NewUser(), nil
 	default:
 		return nil, e.Errorf("unknown kind of %s within v1", kind)
 	}
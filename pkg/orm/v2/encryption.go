@@ -0,0 +1,57 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+// appInstanceEncryptedFields 根据所引用的应用版本，获取应用实例中格式为密码的参数字段路径
+func appInstanceEncryptedFields(obj core.ApiObject) []string {
+	appInstance := obj.(*AppInstance)
+
+	appObj, err := v1.NewAppRegistry().Get(context.TODO(), core.DefaultNamespace, appInstance.Spec.AppRef.Name)
+	if err != nil {
+		log.Error(err)
+		return nil
+	} else if appObj == nil {
+		return nil
+	}
+	app := appObj.(*v1.App)
+
+	paths := []string{}
+	if versionApp, ok := app.GetVersion(appInstance.Spec.AppRef.Version); ok {
+		for argIndex, arg := range appInstance.Spec.Global.Args {
+			if v1.IsPasswordArg(versionApp.Global.Args, arg.Name) {
+				paths = append(paths, fmt.Sprintf("Spec.Global.Args.%d.Value", argIndex))
+			}
+		}
+	}
+	for moduleIndex, module := range appInstance.Spec.Modules {
+		version := module.AppVersion
+		if version == "" {
+			version = appInstance.Spec.AppRef.Version
+		}
+		appModule, ok := app.GetVersionModule(version, module.Name)
+		if !ok {
+			continue
+		}
+		for replicaIndex, replica := range module.Replicas {
+			for argIndex, arg := range replica.Args {
+				if v1.IsPasswordArg(appModule.Args, arg.Name) {
+					paths = append(paths, fmt.Sprintf("Spec.Modules.%d.Replicas.%d.Args.%d.Value", moduleIndex, replicaIndex, argIndex))
+				}
+			}
+			for argIndex, arg := range replica.AdditionalConfigs.Args {
+				if v1.IsPasswordArg(appModule.AdditionalConfigs.Args, arg.Name) {
+					paths = append(paths, fmt.Sprintf("Spec.Modules.%d.Replicas.%d.AdditionalConfigs.Args.%d.Value", moduleIndex, replicaIndex, argIndex))
+				}
+			}
+		}
+	}
+	return paths
+}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"path"
 	"text/template"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/wujie1993/waves/pkg/orm/core"
 	"github.com/wujie1993/waves/pkg/orm/registry"
 	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/setting"
 	"github.com/wujie1993/waves/pkg/util"
 )
 
@@ -198,6 +200,25 @@ func appInstanceValidate(obj core.ApiObject) error {
 		return e.Errorf("referred app version %s not found", appInstance.Spec.AppRef.Version)
 	}
 
+	// 根据应用版本中的参数定义校验参数，Spec未变更时（如只更新状态）不再重复校验
+	if appVersionExist && appInstance.Status.Phase != core.PhaseDeleting {
+		oldObj, err := NewAppInstanceRegistry().Get(context.TODO(), appInstance.Metadata.Namespace, appInstance.Metadata.Name)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		var oldAppInstance *AppInstance
+		if oldObj != nil {
+			oldAppInstance = oldObj.(*AppInstance)
+		}
+		if oldAppInstance == nil || oldAppInstance.SpecHash() != appInstance.SpecHash() {
+			if err := validateAppInstanceArgs(appInstance, oldAppInstance, app); err != nil {
+				log.Error(err)
+				return err
+			}
+		}
+	}
+
 	switch app.Spec.Category {
 	case core.AppCategoryHostPlugin:
 		for _, module := range appInstance.Spec.Modules {
@@ -281,7 +302,8 @@ func appInstanceMutate(obj core.ApiObject) error {
 					return err
 				}
 
-				if cm != nil {
+				// 配置文件内容未变化时保留原有的版本号，资源版本号会随配置文件的任意写入累加
+				if cm != nil && (replica.ConfigMapRef.Hash != cm.SpecHash() || replica.ConfigMapRef.Revision == 0) {
 					appInstance.Spec.Modules[moduleIndex].Replicas[replicaIndex].ConfigMapRef.Hash = cm.SpecHash()
 					appInstance.Spec.Modules[moduleIndex].Replicas[replicaIndex].ConfigMapRef.Revision = cm.GetMetadata().ResourceVersion
 				}
@@ -395,7 +417,6 @@ func NewAppInstanceRegistry() *AppInstanceRegistry {
 		Registry: registry.NewRegistry(newGVK(core.KindAppInstance), true),
 	}
 	r.SetDefaultFinalizers([]string{
-		core.FinalizerCleanRefEvent,
 		core.FinalizerReleaseRefGPU,
 		core.FinalizerCleanRefConfigMap,
 		core.FinalizerCleanRevision,
@@ -405,8 +426,9 @@ func NewAppInstanceRegistry() *AppInstanceRegistry {
 	r.SetMutateHook(appInstanceMutate)
 	r.SetDecorateHook(appInstanceDecorate)
 	r.SetPostCreateHook(appInstancePostCreate)
+	r.SetEncryptedFieldsFunc(appInstanceEncryptedFields)
 	r.SetPreUpdateHook(appInstancePreUpdate)
-	r.SetRevisioner(NewAppInstanceRevision())
+	r.SetRevisioner(newAppInstanceRevision(r))
 	return r
 }
 
@@ -420,10 +442,10 @@ func NewHostRegistry() *HostRegistry {
 	r := &HostRegistry{
 		Registry: registry.NewRegistry(newGVK(core.KindHost), false),
 	}
-	r.SetDefaultFinalizers([]string{
-		core.FinalizerCleanRefGPU,
-		core.FinalizerCleanRefEvent,
-	})
+	r.SetEncryptedFields("Spec.SSH.Password")
+	r.SetRevisioner(v1.NewResourceRevision(r, func() int {
+		return setting.RevisionSetting.Host
+	}))
 	return r
 }
 
@@ -469,8 +491,18 @@ func NewJobRegistry() *JobRegistry {
 		Registry: registry.NewRegistry(newGVK(core.KindJob), false),
 	}
 	r.SetDefaultFinalizers([]string{
-		core.FinalizerCleanRefConfigMap,
 		core.FinalizerCleanJobWorkDir,
 	})
+	r.SetTTLFunc(jobTTL)
+	r.SetEncryptedFields("Spec.Exec.Ansible.Plays.*.Inventory.Value", "Spec.Exec.Ansible.Plays.*.GroupVars.Value")
 	return r
 }
+
+// jobTTL 仅为已完成或失败的任务设置保留时长
+func jobTTL(obj core.ApiObject) time.Duration {
+	switch obj.GetStatusPhase() {
+	case core.PhaseCompleted, core.PhaseFailed:
+		return setting.RetentionSetting.Job
+	}
+	return 0
+}
@@ -217,6 +217,11 @@ func (obj *AppInstance) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *AppInstance) SpecReset() {
+	obj.Spec = AppInstanceSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj AppInstance) SpecHash() string {
 	for moduleIndex, module := range obj.Spec.Modules {
@@ -239,6 +244,11 @@ func (obj *Host) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *Host) SpecReset() {
+	obj.Spec = HostSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj Host) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -255,6 +265,11 @@ func (obj *Job) SpecDecode(data []byte) error {
 	return json.Unmarshal(data, &obj.Spec)
 }
 
+// SpecReset 清空Spec字段的内容
+func (obj *Job) SpecReset() {
+	obj.Spec = JobSpec{}
+}
+
 // SpecHash 计算Spec字段中的"有效"内容哈希值
 func (obj Job) SpecHash() string {
 	data, _ := json.Marshal(&obj.Spec)
@@ -0,0 +1,258 @@
+package v2
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+// argValidator 根据应用版本中声明的参数定义校验应用实例的参数，收集所有字段的校验错误
+type argValidator struct {
+	hostRegistry registry.ApiObjectRegistry
+	errs         e.InvalidFieldsError
+}
+
+func (v *argValidator) addError(field string, format string, args ...interface{}) {
+	v.errs = append(v.errs, e.InvalidFieldError{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+// validateAppInstanceArgs 校验应用实例的全局参数与模块参数，oldAppInstance为更新前的应用实例，创建时为空。
+// 只有在同版本配置应用实例时才校验不可修改的参数
+func validateAppInstanceArgs(appInstance *AppInstance, oldAppInstance *AppInstance, app *v1.App) error {
+	v := &argValidator{
+		hostRegistry: v1.NewHostRegistry(),
+	}
+
+	configure := oldAppInstance != nil && (appInstance.Spec.Action == core.AppActionConfigure || appInstance.Spec.Action == core.AppActionUpdate)
+
+	versionApp, _ := app.GetVersion(appInstance.Spec.AppRef.Version)
+	var oldGlobalValue func(name string) (interface{}, bool)
+	if configure && oldAppInstance.Spec.AppRef.Version == appInstance.Spec.AppRef.Version {
+		oldGlobalValue = func(name string) (interface{}, bool) {
+			for _, arg := range oldAppInstance.Spec.Global.Args {
+				if arg.Name == name {
+					return arg.Value, true
+				}
+			}
+			return nil, false
+		}
+	}
+	if err := v.validateArgs("Spec.Global.Args", appInstance.Spec.Global.Args, versionApp.Global.Args, oldGlobalValue); err != nil {
+		return err
+	}
+
+	for moduleIndex, module := range appInstance.Spec.Modules {
+		version := module.AppVersion
+		if version == "" {
+			version = appInstance.Spec.AppRef.Version
+		}
+		modulePath := fmt.Sprintf("Spec.Modules[%d]", moduleIndex)
+		appModule, ok := app.GetVersionModule(version, module.Name)
+		if !ok {
+			v.addError(modulePath+".Name", "模块 %s 在应用版本 %s 中不存在", module.Name, version)
+			continue
+		}
+
+		var oldModule AppInstanceModule
+		sameVersion := false
+		if configure {
+			oldModule, sameVersion = oldAppInstance.GetModule(module.Name)
+			oldVersion := oldModule.AppVersion
+			if oldVersion == "" {
+				oldVersion = oldAppInstance.Spec.AppRef.Version
+			}
+			sameVersion = sameVersion && oldVersion == version
+		}
+
+		for replicaIndex, replica := range module.Replicas {
+			replicaPath := fmt.Sprintf("%s.Replicas[%d]", modulePath, replicaIndex)
+
+			// 校验模块切片所部署的主机数量
+			if limits := appModule.HostLimits; limits.Min > 0 && len(replica.HostRefs) < limits.Min {
+				v.addError(replicaPath+".HostRefs", "主机数量不能少于 %d", limits.Min)
+			} else if limits.Max > 0 && len(replica.HostRefs) > limits.Max {
+				v.addError(replicaPath+".HostRefs", "主机数量不能多于 %d", limits.Max)
+			}
+
+			var oldReplicaValue func(name string) (interface{}, bool)
+			if sameVersion {
+				replicaIndex := replicaIndex
+				oldReplicaValue = func(name string) (interface{}, bool) {
+					return oldAppInstance.Spec.GetModuleReplicaArgValue(module.Name, replicaIndex, name)
+				}
+			}
+			if err := v.validateArgs(replicaPath+".Args", replica.Args, appModule.Args, oldReplicaValue); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
+
+// validateArgs 校验一组参数，oldValue用于获取参数更新前的值，为空时不校验参数是否允许修改。未声明的参数不做校验
+func (v *argValidator) validateArgs(path string, args []AppInstanceArgs, appArgs []v1.AppArgs, oldValue func(name string) (interface{}, bool)) error {
+	present := make(map[string]bool)
+	for argIndex, arg := range args {
+		field := fmt.Sprintf("%s[%d]", path, argIndex)
+		present[arg.Name] = true
+
+		var appArg *v1.AppArgs
+		for i := range appArgs {
+			if appArgs[i].Name == arg.Name {
+				appArg = &appArgs[i]
+				break
+			}
+		}
+		if appArg == nil {
+			continue
+		}
+
+		if isEmptyArgValue(arg.Value) {
+			if appArg.Required && appArg.Default == nil {
+				v.addError(field, "必填参数 %s 不能为空", arg.Name)
+			}
+			continue
+		}
+
+		if err := v.validateArgValue(field, *appArg, arg.Value); err != nil {
+			return err
+		}
+
+		if len(appArg.Enum) > 0 {
+			valid := false
+			for _, enum := range appArg.Enum {
+				if enum == fmt.Sprint(arg.Value) {
+					valid = true
+					break
+				}
+			}
+			if !valid && appArg.Format == core.ArgFormatPassword {
+				// 错误信息中不回显密码
+				v.addError(field, "参数 %s 的取值不在可选范围中", arg.Name)
+			} else if !valid {
+				v.addError(field, "参数 %s 的取值 %v 不在可选范围 %s 中", arg.Name, arg.Value, strings.Join(appArg.Enum, ","))
+			}
+		}
+
+		if appArg.Readonly && !argValueEqual(arg.Value, appArg.Default) {
+			v.addError(field, "只读参数 %s 只能为默认值 %v", arg.Name, appArg.Default)
+		}
+
+		if oldValue != nil && !appArg.Modifiable {
+			if old, ok := oldValue(arg.Name); ok && !argValueEqual(arg.Value, old) {
+				v.addError(field, "参数 %s 不允许修改", arg.Name)
+			}
+		}
+	}
+
+	for _, appArg := range appArgs {
+		if appArg.Required && appArg.Default == nil && !present[appArg.Name] {
+			v.addError(path, "缺少必填参数 %s", appArg.Name)
+		}
+	}
+	return nil
+}
+
+// validateArgValue 根据参数定义的类型与格式校验参数值，只有在查询主机失败时返回错误
+func (v *argValidator) validateArgValue(field string, appArg v1.AppArgs, value interface{}) error {
+	switch appArg.Type {
+	case core.ArgTypeInteger:
+		n, ok := toFloat64(value)
+		if !ok || n != math.Trunc(n) {
+			v.addError(field, "参数 %s 应为整数", appArg.Name)
+			return nil
+		}
+		switch appArg.Format {
+		case core.ArgFormatInt32:
+			if n < math.MinInt32 || n > math.MaxInt32 {
+				v.addError(field, "参数 %s 超出int32的取值范围", appArg.Name)
+			}
+		case core.ArgFormatPort:
+			if n < 1 || n > 65535 {
+				v.addError(field, "参数 %s 应为1-65535之间的端口号", appArg.Name)
+			}
+		}
+	case core.ArgTypeNumber:
+		if _, ok := toFloat64(value); !ok {
+			v.addError(field, "参数 %s 应为数字", appArg.Name)
+		}
+	case core.ArgTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			v.addError(field, "参数 %s 应为布尔值", appArg.Name)
+		}
+	case core.ArgTypeString:
+		s, ok := value.(string)
+		if !ok {
+			v.addError(field, "参数 %s 应为字符串", appArg.Name)
+			return nil
+		}
+		switch appArg.Format {
+		case core.ArgFormatPassword:
+			if appArg.Required && strings.TrimSpace(s) == "" {
+				v.addError(field, "必填的密码参数 %s 不能为空白", appArg.Name)
+			}
+		case core.ArgFormatDate:
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				v.addError(field, "参数 %s 应为RFC3339格式的日期", appArg.Name)
+			}
+		case core.ArgFormatArray:
+			for _, item := range strings.Split(s, ";") {
+				if item == "" {
+					v.addError(field, "参数 %s 应为以;分隔的非空数组", appArg.Name)
+					break
+				}
+			}
+		case core.ArgFormatGroupHost:
+			hostRefs := strings.Split(s, ";")
+			if limits := appArg.HostLimits; limits.Min > 0 && len(hostRefs) < limits.Min {
+				v.addError(field, "参数 %s 的主机数量不能少于 %d", appArg.Name, limits.Min)
+			} else if limits.Max > 0 && len(hostRefs) > limits.Max {
+				v.addError(field, "参数 %s 的主机数量不能多于 %d", appArg.Name, limits.Max)
+			}
+			for _, hostRef := range hostRefs {
+				hostObj, err := v.hostRegistry.Get(context.TODO(), "", hostRef)
+				if err != nil {
+					return err
+				}
+				if hostObj == nil {
+					v.addError(field, "参数 %s 引用的主机 %s 不存在", appArg.Name, hostRef)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isEmptyArgValue(value interface{}) bool {
+	return value == nil || value == ""
+}
+
+// argValueEqual 比较两个参数值，json反序列化后的数字统一为float64，因此按文本形式比较
+func argValueEqual(a interface{}, b interface{}) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func toFloat64(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
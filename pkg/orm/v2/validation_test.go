@@ -0,0 +1,94 @@
+package v2_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/orm/v2"
+	// 注册资源对象的实例化与转换方法
+	_ "github.com/wujie1993/waves/pkg/orm"
+)
+
+func TestAppInstanceArgsValidation(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	host := v1.NewHost()
+	host.Metadata.Name = "host-1"
+	if _, err := v1.NewHostRegistry().Create(context.TODO(), host); err != nil {
+		t.Fatal(err)
+	}
+
+	app := v1.NewApp()
+	app.Metadata.Namespace = core.DefaultNamespace
+	app.Metadata.Name = "demo"
+	app.Spec.Category = core.AppCategoryCustomize
+	app.Spec.Versions = []v1.AppVersion{{
+		Version: "1.0",
+		Modules: []v1.AppModule{{
+			Name:       "web",
+			HostLimits: v1.HostLimits{Min: 1},
+			Args: []v1.AppArgs{
+				{Name: "port", Type: core.ArgTypeInteger, Format: core.ArgFormatPort, Required: true},
+				{Name: "mode", Type: core.ArgTypeString, Enum: []string{"a", "b"}},
+				{Name: "hosts", Type: core.ArgTypeString, Format: core.ArgFormatGroupHost},
+				{Name: "password", Type: core.ArgTypeString, Format: core.ArgFormatPassword, Required: true},
+			},
+		}},
+	}}
+	if _, err := v1.NewAppRegistry().Create(context.TODO(), app); err != nil {
+		t.Fatal(err)
+	}
+
+	newAppInstance := func(args []v2.AppInstanceArgs, hostRefs []string) *v2.AppInstance {
+		appInstance := v2.NewAppInstance()
+		appInstance.Metadata.Namespace = core.DefaultNamespace
+		appInstance.Metadata.Name = "demo-1"
+		appInstance.Spec.AppRef = v2.AppRef{Name: "demo", Version: "1.0"}
+		appInstance.Spec.Modules = []v2.AppInstanceModule{{
+			Name:     "web",
+			Replicas: []v2.AppInstanceModuleReplica{{Args: args, HostRefs: hostRefs}},
+		}}
+		return appInstance
+	}
+
+	appInstanceRegistry := v2.NewAppInstanceRegistry()
+	invalid := newAppInstance([]v2.AppInstanceArgs{
+		{Name: "port", Value: float64(70000)},
+		{Name: "mode", Value: "c"},
+		{Name: "hosts", Value: "host-1;host-2"},
+		{Name: "password", Value: "  "},
+	}, nil)
+	_, err := appInstanceRegistry.Create(context.TODO(), invalid)
+	fieldErrs, ok := err.(e.InvalidFieldsError)
+	if !ok || len(fieldErrs) != 5 {
+		t.Fatalf("unexpected validation error %v", err)
+	}
+	if fieldErrs[0].Field != "Spec.Modules[0].Replicas[0].HostRefs" || fieldErrs[1].Field != "Spec.Modules[0].Replicas[0].Args[0]" || fieldErrs[4].Field != "Spec.Modules[0].Replicas[0].Args[3]" {
+		t.Fatalf("unexpected field paths %+v", fieldErrs)
+	}
+
+	if _, err := appInstanceRegistry.Create(context.TODO(), newAppInstance(nil, []string{"host-1"})); err == nil {
+		t.Fatal("expected missing required arg to be rejected")
+	}
+
+	valid := newAppInstance([]v2.AppInstanceArgs{
+		{Name: "port", Value: float64(8080)},
+		{Name: "mode", Value: "a"},
+		{Name: "hosts", Value: "host-1"},
+		{Name: "password", Value: "secret"},
+	}, []string{"host-1"})
+	if _, err := appInstanceRegistry.Create(context.TODO(), valid); err != nil {
+		t.Fatal(err)
+	}
+
+	// 同版本配置时不允许修改声明为不可修改的参数
+	valid.Spec.Action = core.AppActionConfigure
+	valid.Spec.Modules[0].Replicas[0].Args[0].Value = float64(8081)
+	if _, err := appInstanceRegistry.Update(context.TODO(), valid); err == nil {
+		t.Fatal("expected unmodifiable arg update to be rejected")
+	}
+}
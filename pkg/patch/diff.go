@@ -0,0 +1,219 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// CreateJSONPatch 生成将文档from变更为文档to的JSON补丁。
+// 字典按字段逐个比较，长度一致的数组按元素逐个比较，长度不一致的数组整体替换
+func CreateJSONPatch(from []byte, to []byte) ([]Operation, error) {
+	fromContent, err := unmarshal(from)
+	if err != nil {
+		return nil, err
+	}
+	toContent, err := unmarshal(to)
+	if err != nil {
+		return nil, err
+	}
+	ops := []Operation{}
+	if err := diffOperations("", fromContent, toContent, &ops); err != nil {
+		return nil, err
+	}
+	return ops, nil
+}
+
+func diffOperations(path string, from interface{}, to interface{}, ops *[]Operation) error {
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+
+	switch toValue := to.(type) {
+	case map[string]interface{}:
+		fromValue, ok := from.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := []string{}
+		for key := range fromValue {
+			keys = append(keys, key)
+		}
+		for key := range toValue {
+			if _, ok := fromValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			childPath := path + "/" + escapePointerToken(key)
+			fromChild, inFrom := fromValue[key]
+			toChild, inTo := toValue[key]
+			switch {
+			case !inTo:
+				*ops = append(*ops, Operation{Op: "remove", Path: childPath})
+			case !inFrom:
+				if err := appendValueOperation(ops, "add", childPath, toChild); err != nil {
+					return err
+				}
+			default:
+				if err := diffOperations(childPath, fromChild, toChild, ops); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case []interface{}:
+		fromValue, ok := from.([]interface{})
+		if !ok || len(fromValue) != len(toValue) {
+			break
+		}
+		for i := range toValue {
+			if err := diffOperations(fmt.Sprintf("%s/%d", path, i), fromValue[i], toValue[i], ops); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return appendValueOperation(ops, "replace", path, to)
+}
+
+func appendValueOperation(ops *[]Operation, op string, path string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	*ops = append(*ops, Operation{Op: op, Path: path, Value: data})
+	return nil
+}
+
+// escapePointerToken 按RFC 6901转义JSON指针中的字段名
+func escapePointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+// UnifiedDiff 按行比较文本from与to，生成统一格式(unified)的差异文本，每处差异保留上下各3行。两段文本一致时返回空
+func UnifiedDiff(fromName string, toName string, from string, to string) string {
+	fromLines := splitLines(from)
+	toLines := splitLines(to)
+
+	// 基于最长公共子序列计算逐行的编辑操作
+	lcs := make([][]int, len(fromLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(toLines)+1)
+	}
+	for i := len(fromLines) - 1; i >= 0; i-- {
+		for j := len(toLines) - 1; j >= 0; j-- {
+			if fromLines[i] == toLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	type edit struct {
+		op   byte
+		line string
+	}
+	edits := []edit{}
+	changed := false
+	i, j := 0, 0
+	for i < len(fromLines) || j < len(toLines) {
+		switch {
+		case i < len(fromLines) && j < len(toLines) && fromLines[i] == toLines[j]:
+			edits = append(edits, edit{' ', fromLines[i]})
+			i++
+			j++
+		case i < len(fromLines) && (j == len(toLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', fromLines[i]})
+			changed = true
+			i++
+		default:
+			edits = append(edits, edit{'+', toLines[j]})
+			changed = true
+			j++
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	const context = 3
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", fromName, toName)
+	// 逐个输出差异块，相邻差异之间的相同行不超过2倍上下文行数时合并为同一个差异块
+	fromLine, toLine := 0, 0
+	for start := 0; start < len(edits); {
+		if edits[start].op == ' ' {
+			fromLine++
+			toLine++
+			start++
+			continue
+		}
+		hunkStart := start - context
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		hunkEnd := start
+		for k := start; k < len(edits); k++ {
+			if edits[k].op != ' ' {
+				hunkEnd = k + 1
+			} else if k-hunkEnd >= 2*context {
+				break
+			}
+		}
+		hunkEnd += context
+		if hunkEnd > len(edits) {
+			hunkEnd = len(edits)
+		}
+
+		hunkFrom := fromLine - (start - hunkStart)
+		hunkTo := toLine - (start - hunkStart)
+		fromCount, toCount := 0, 0
+		for _, e := range edits[hunkStart:hunkEnd] {
+			if e.op != '+' {
+				fromCount++
+			}
+			if e.op != '-' {
+				toCount++
+			}
+		}
+		fmt.Fprintf(&builder, "@@ -%s +%s @@\n", hunkRange(hunkFrom, fromCount), hunkRange(hunkTo, toCount))
+		for _, e := range edits[hunkStart:hunkEnd] {
+			fmt.Fprintf(&builder, "%c%s\n", e.op, e.line)
+		}
+
+		for _, e := range edits[start:hunkEnd] {
+			if e.op != '+' {
+				fromLine++
+			}
+			if e.op != '-' {
+				toLine++
+			}
+		}
+		start = hunkEnd
+	}
+	return builder.String()
+}
+
+// hunkRange 生成差异块的行号范围，行号从1开始，空范围的行号为范围之前的一行
+func hunkRange(start int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
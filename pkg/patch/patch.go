@@ -0,0 +1,373 @@
+package patch
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+const (
+	// TypeMergePatch JSON合并补丁(RFC 7386)
+	TypeMergePatch = "application/merge-patch+json"
+	// TypeJSONPatch JSON补丁(RFC 6902)
+	TypeJSONPatch = "application/json-patch+json"
+	// TypeApplyPatch 完整的资源配置，与上次应用的配置及当前对象进行三方合并
+	TypeApplyPatch = "application/apply-patch+json"
+)
+
+// Operation JSON补丁中的单个操作
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MergePatch 将JSON合并补丁应用于文档，补丁中值为null的字段会从文档中删除
+func MergePatch(doc []byte, patch []byte) ([]byte, error) {
+	docContent, err := unmarshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	patchContent, err := unmarshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(docContent, patchContent))
+}
+
+func mergePatch(doc interface{}, patch interface{}) interface{} {
+	patchFields, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	docFields, ok := doc.(map[string]interface{})
+	if !ok {
+		docFields = make(map[string]interface{})
+	}
+	for key, value := range patchFields {
+		if value == nil {
+			delete(docFields, key)
+			continue
+		}
+		docFields[key] = mergePatch(docFields[key], value)
+	}
+	return docFields
+}
+
+// JSONPatch 将JSON补丁应用于文档，支持add、remove、replace、move、copy与test操作
+func JSONPatch(doc []byte, patch []byte) ([]byte, error) {
+	content, err := unmarshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	ops := []Operation{}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, err
+	}
+
+	for _, op := range ops {
+		path, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "add", "replace", "test":
+			var value interface{}
+			if value, err = unmarshal(op.Value); err != nil {
+				return nil, e.Errorf("invalid value of %s operation at %s", op.Op, op.Path)
+			}
+			switch op.Op {
+			case "add":
+				content, err = addValue(content, path, value)
+			case "replace":
+				if _, err = getValue(content, path); err == nil {
+					content, err = replaceValue(content, path, value)
+				}
+			case "test":
+				var current interface{}
+				if current, err = getValue(content, path); err == nil && !reflect.DeepEqual(current, value) {
+					err = e.Errorf("test operation at %s failed", op.Path)
+				}
+			}
+		case "remove":
+			content, _, err = removeValue(content, path)
+		case "move", "copy":
+			var from []string
+			if from, err = parsePointer(op.From); err != nil {
+				return nil, err
+			}
+			var value interface{}
+			if op.Op == "move" {
+				content, value, err = removeValue(content, from)
+			} else {
+				value, err = getValue(content, from)
+				if err == nil {
+					// 复制时需要深拷贝，避免后续操作修改到源字段
+					value, err = deepCopy(value)
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			content, err = addValue(content, path, value)
+		default:
+			err = e.Errorf("unsupported json patch operation %s", op.Op)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(content)
+}
+
+// CreateThreeWayMergePatch 根据上次应用的配置、本次应用的配置与当前对象生成JSON合并补丁。
+// 补丁包含本次配置中与当前对象不一致的字段，以及上次配置中存在而本次配置中已移除的字段，
+// 其他方式写入当前对象且未出现在两次配置中的字段保持不变
+func CreateThreeWayMergePatch(original []byte, modified []byte, current []byte) ([]byte, error) {
+	originalContent := interface{}(map[string]interface{}{})
+	if len(original) > 0 {
+		content, err := unmarshal(original)
+		if err != nil {
+			return nil, err
+		}
+		originalContent = content
+	}
+	modifiedContent, err := unmarshal(modified)
+	if err != nil {
+		return nil, err
+	}
+	currentContent, err := unmarshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := diff(currentContent, modifiedContent, false)
+	mergeDiff(patch, diff(originalContent, modifiedContent, true))
+	return json.Marshal(patch)
+}
+
+// diff 生成将from转换为to的合并补丁，deletions为false时只包含新增与修改的字段，为true时只包含删除的字段
+func diff(from interface{}, to interface{}, deletions bool) map[string]interface{} {
+	result := make(map[string]interface{})
+	fromFields, _ := from.(map[string]interface{})
+	toFields, _ := to.(map[string]interface{})
+
+	for key, toValue := range toFields {
+		fromValue, ok := fromFields[key]
+		_, fromIsMap := fromValue.(map[string]interface{})
+		_, toIsMap := toValue.(map[string]interface{})
+		if ok && fromIsMap && toIsMap {
+			if child := diff(fromValue, toValue, deletions); len(child) > 0 {
+				result[key] = child
+			}
+			continue
+		}
+		if !deletions && (!ok || !reflect.DeepEqual(fromValue, toValue)) {
+			result[key] = toValue
+		}
+	}
+	if deletions {
+		for key := range fromFields {
+			if _, ok := toFields[key]; !ok {
+				result[key] = nil
+			}
+		}
+	}
+	return result
+}
+
+// mergeDiff 将src中的字段合并至dst，与合并补丁不同，值为null的字段会被保留
+func mergeDiff(dst map[string]interface{}, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcFields, srcIsMap := srcValue.(map[string]interface{})
+		dstFields, dstIsMap := dst[key].(map[string]interface{})
+		if srcIsMap && dstIsMap {
+			mergeDiff(dstFields, srcFields)
+			continue
+		}
+		dst[key] = srcValue
+	}
+}
+
+// unmarshal 解析JSON文档，数字保留为json.Number，文档中只能包含一个JSON值
+func unmarshal(data []byte) (interface{}, error) {
+	var content interface{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&content); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, e.Errorf("invalid character after top-level value")
+	}
+	return content, nil
+}
+
+func deepCopy(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshal(data)
+}
+
+// parsePointer 解析JSON指针(RFC 6901)
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, e.Errorf("invalid json pointer %s", pointer)
+	}
+	path := strings.Split(pointer[1:], "/")
+	for i := range path {
+		path[i] = strings.Replace(strings.Replace(path[i], "~1", "/", -1), "~0", "~", -1)
+	}
+	return path, nil
+}
+
+// parseIndex 解析数组下标，下标只能由数字组成且不能有前导0，allowEnd为true时允许使用-或数组长度表示末尾
+func parseIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+	if token == "" || strings.TrimLeft(token, "0123456789") != "" || (len(token) > 1 && token[0] == '0') {
+		return 0, e.Errorf("invalid array index %s", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil || index > length || (!allowEnd && index == length) {
+		return 0, e.Errorf("invalid array index %s", token)
+	}
+	return index, nil
+}
+
+func getValue(content interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch value := content.(type) {
+		case map[string]interface{}:
+			child, ok := value[token]
+			if !ok {
+				return nil, e.Errorf("path /%s not found", strings.Join(path, "/"))
+			}
+			content = child
+		case []interface{}:
+			index, err := parseIndex(token, len(value), false)
+			if err != nil {
+				return nil, err
+			}
+			content = value[index]
+		default:
+			return nil, e.Errorf("path /%s not found", strings.Join(path, "/"))
+		}
+	}
+	return content, nil
+}
+
+// updateParent 获取路径的父节点并使用f修改，返回修改后的文档
+func updateParent(content interface{}, path []string, f func(parent interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, e.Errorf("can not modify the root of document")
+	}
+	if len(path) == 1 {
+		return f(content, path[0])
+	}
+
+	var err error
+	switch value := content.(type) {
+	case map[string]interface{}:
+		child, ok := value[path[0]]
+		if !ok {
+			return nil, e.Errorf("path %s not found", path[0])
+		}
+		value[path[0]], err = updateParent(child, path[1:], f)
+	case []interface{}:
+		var index int
+		if index, err = parseIndex(path[0], len(value), false); err != nil {
+			return nil, err
+		}
+		value[index], err = updateParent(value[index], path[1:], f)
+	default:
+		return nil, e.Errorf("path %s not found", path[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func addValue(content interface{}, path []string, newValue interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return newValue, nil
+	}
+	return updateParent(content, path, func(parent interface{}, token string) (interface{}, error) {
+		switch value := parent.(type) {
+		case map[string]interface{}:
+			value[token] = newValue
+			return value, nil
+		case []interface{}:
+			index, err := parseIndex(token, len(value), true)
+			if err != nil {
+				return nil, err
+			}
+			value = append(value, nil)
+			copy(value[index+1:], value[index:])
+			value[index] = newValue
+			return value, nil
+		}
+		return nil, e.Errorf("can not add value to %s", token)
+	})
+}
+
+func replaceValue(content interface{}, path []string, newValue interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return newValue, nil
+	}
+	return updateParent(content, path, func(parent interface{}, token string) (interface{}, error) {
+		switch value := parent.(type) {
+		case map[string]interface{}:
+			value[token] = newValue
+			return value, nil
+		case []interface{}:
+			index, err := parseIndex(token, len(value), false)
+			if err != nil {
+				return nil, err
+			}
+			value[index] = newValue
+			return value, nil
+		}
+		return nil, e.Errorf("can not replace value of %s", token)
+	})
+}
+
+func removeValue(content interface{}, path []string) (interface{}, interface{}, error) {
+	var removed interface{}
+	result, err := updateParent(content, path, func(parent interface{}, token string) (interface{}, error) {
+		switch value := parent.(type) {
+		case map[string]interface{}:
+			child, ok := value[token]
+			if !ok {
+				return nil, e.Errorf("path %s not found", token)
+			}
+			removed = child
+			delete(value, token)
+			return value, nil
+		case []interface{}:
+			index, err := parseIndex(token, len(value), false)
+			if err != nil {
+				return nil, err
+			}
+			removed = value[index]
+			return append(value[:index], value[index+1:]...), nil
+		}
+		return nil, e.Errorf("can not remove value of %s", token)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, removed, nil
+}
@@ -0,0 +1,174 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/patch"
+)
+
+func TestTrailingData(t *testing.T) {
+	// 文档中第一个JSON值之后的内容不能被忽略
+	for _, data := range []string{
+		`{"a":1} {"b":2}`,
+		`3af1c2e4`,
+		`{"a":1}x`,
+	} {
+		if _, err := patch.MergePatch([]byte(data), []byte(`{}`)); err == nil {
+			t.Errorf("expected error for document %s", data)
+		}
+		if _, err := patch.MergePatch([]byte(`{}`), []byte(data)); err == nil {
+			t.Errorf("expected error for patch %s", data)
+		}
+	}
+	if _, err := patch.MergePatch([]byte(" {\"a\":1}\n"), []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		// JSON指针中的~1表示/，~0表示~，先还原~1再还原~0
+		{"escaped slash", `{"a/b":1}`, `[{"op":"replace","path":"/a~1b","value":2}]`, `{"a/b":2}`},
+		{"escaped tilde", `{"m~n":1,"x":1}`, `[{"op":"remove","path":"/m~0n"}]`, `{"x":1}`},
+		{"escaped tilde before one", `{"~1":1,"/":2}`, `[{"op":"remove","path":"/~01"}]`, `{"/":2}`},
+		// 数组下标
+		{"append with dash", `{"a":[1,2]}`, `[{"op":"add","path":"/a/-","value":3}]`, `{"a":[1,2,3]}`},
+		{"append with length", `{"a":[1,2]}`, `[{"op":"add","path":"/a/2","value":3}]`, `{"a":[1,2,3]}`},
+		{"insert", `{"a":[1,2]}`, `[{"op":"add","path":"/a/0","value":0}]`, `{"a":[0,1,2]}`},
+		{"add out of range", `{"a":[1,2]}`, `[{"op":"add","path":"/a/3","value":3}]`, ""},
+		{"replace at length", `{"a":[1,2]}`, `[{"op":"replace","path":"/a/2","value":3}]`, ""},
+		{"replace with dash", `{"a":[1,2]}`, `[{"op":"replace","path":"/a/-","value":3}]`, ""},
+		{"remove with dash", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/-"}]`, ""},
+		{"negative index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/-1"}]`, ""},
+		{"leading zero index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/01"}]`, ""},
+		{"signed index", `{"a":[1,2]}`, `[{"op":"remove","path":"/a/+1"}]`, ""},
+		// 移动与复制
+		{"move array element", `{"a":[1,2,3]}`, `[{"op":"move","from":"/a/0","path":"/a/-"}]`, `{"a":[2,3,1]}`},
+		{"move into itself", `{"a":{"b":1}}`, `[{"op":"move","from":"/a","path":"/a/c"}]`, ""},
+		{"copy is independent", `{"a":{"b":1}}`, `[{"op":"copy","from":"/a","path":"/c"},{"op":"replace","path":"/c/b","value":2}]`, `{"a":{"b":1},"c":{"b":2}}`},
+		{"copy array element", `{"a":[{"b":1}]}`, `[{"op":"copy","from":"/a/0","path":"/a/-"},{"op":"replace","path":"/a/1/b","value":2}]`, `{"a":[{"b":1},{"b":2}]}`},
+		// 测试操作
+		{"test passes", `{"a":{"b":[1]}}`, `[{"op":"test","path":"/a","value":{"b":[1]}},{"op":"add","path":"/c","value":1}]`, `{"a":{"b":[1]},"c":1}`},
+		{"test fails", `{"a":1}`, `[{"op":"add","path":"/c","value":1},{"op":"test","path":"/a","value":2}]`, ""},
+		{"test missing path", `{"a":1}`, `[{"op":"test","path":"/b","value":null}]`, ""},
+		// 非对象文档
+		{"array document", `[1,2]`, `[{"op":"add","path":"/-","value":3}]`, `[1,2,3]`},
+		{"scalar document", `"text"`, `[{"op":"add","path":"/a","value":1}]`, ""},
+		{"replace root", `null`, `[{"op":"replace","path":"","value":{"a":1}}]`, `{"a":1}`},
+		{"remove root", `{"a":1}`, `[{"op":"remove","path":""}]`, ""},
+		{"unsupported operation", `{"a":1}`, `[{"op":"merge","path":"/a","value":1}]`, ""},
+	} {
+		result, err := patch.JSONPatch([]byte(c.doc), []byte(c.patch))
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("%s: expected error, got %s", c.name, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		assertJSONEqual(t, c.name, string(result), c.want)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	for _, c := range []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"merge nested", `{"a":{"b":1,"c":2}}`, `{"a":{"b":null,"d":3}}`, `{"a":{"c":2,"d":3}}`},
+		{"replace array", `{"a":[1,2,3]}`, `{"a":[4]}`, `{"a":[4]}`},
+		{"array document", `[1,2]`, `{"a":1}`, `{"a":1}`},
+		{"array patch", `{"a":1}`, `[2]`, `[2]`},
+		{"scalar field", `{"a":"text"}`, `{"a":{"b":1}}`, `{"a":{"b":1}}`},
+	} {
+		result, err := patch.MergePatch([]byte(c.doc), []byte(c.patch))
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		assertJSONEqual(t, c.name, string(result), c.want)
+	}
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{"equal", `{"a":[1,2]}`, `{"a":[1,2]}`, `[]`},
+		{"same length array", `{"a":[1,2]}`, `{"a":[1,3]}`, `[{"op":"replace","path":"/a/1","value":3}]`},
+		{"longer array", `{"a":[1,2]}`, `{"a":[1,2,3]}`, `[{"op":"replace","path":"/a","value":[1,2,3]}]`},
+		{"shorter array", `{"a":[1,2,3]}`, `{"a":[1]}`, `[{"op":"replace","path":"/a","value":[1]}]`},
+		{"escaped keys", `{"a/b":1,"m~n":1}`, `{"a/b":2}`, `[{"op":"replace","path":"/a~1b","value":2},{"op":"remove","path":"/m~0n"}]`},
+		{"add and type change", `{"a":1}`, `{"a":{"b":1},"c":[]}`, `[{"op":"replace","path":"/a","value":{"b":1}},{"op":"add","path":"/c","value":[]}]`},
+		{"root array", `[1]`, `[1,2]`, `[{"op":"replace","path":"","value":[1,2]}]`},
+	} {
+		ops, err := patch.CreateJSONPatch([]byte(c.from), []byte(c.to))
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		data, err := json.Marshal(ops)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, data)
+			continue
+		}
+		// 生成的补丁应用于from后应得到to
+		result, err := patch.JSONPatch([]byte(c.from), data)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		assertJSONEqual(t, c.name, string(result), c.to)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{"equal", "a\nb\n", "a\nb\n", ""},
+		{"append item", "items:\n- a\n- b\n", "items:\n- a\n- b\n- c\n", "--- old\n+++ new\n@@ -1,3 +1,4 @@\n items:\n - a\n - b\n+- c\n"},
+		{"remove item", "items:\n- a\n- b\n- c\n", "items:\n- a\n- c\n", "--- old\n+++ new\n@@ -1,4 +1,3 @@\n items:\n - a\n-- b\n - c\n"},
+		{"from empty", "", "a\n", "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n"},
+		{"to empty", "a\nb\n", "", "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n"},
+		{"separate hunks", "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n", "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n", "--- old\n+++ new\n@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n@@ -7,4 +8,3 @@\n 7\n 8\n 9\n-10\n"},
+	} {
+		if result := patch.UnifiedDiff("old", "new", c.from, c.to); result != c.want {
+			t.Errorf("%s: expected\n%s\ngot\n%s", c.name, c.want, result)
+		}
+	}
+}
+
+func assertJSONEqual(t *testing.T, name string, actual string, expected string) {
+	var actualContent, expectedContent interface{}
+	if err := json.Unmarshal([]byte(actual), &actualContent); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if err := json.Unmarshal([]byte(expected), &expectedContent); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	if !reflect.DeepEqual(actualContent, expectedContent) {
+		t.Errorf("%s: expected %s, got %s", name, expected, actual)
+	}
+}
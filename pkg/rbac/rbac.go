@@ -0,0 +1,172 @@
+package rbac
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+const (
+	// 运维管理接口（备份、还原与密钥轮换）对应的资源类型，仅用于授权规则
+	KindAdmin = "admin"
+)
+
+// Attributes 待授权的操作与资源
+type Attributes struct {
+	Verb string
+	Kind string
+	// 资源所属的命名空间，不属于命名空间的资源为空
+	Namespace string
+	Name      string
+}
+
+// AccessReview 权限检查的请求与结果，用于前端判断是否展示操作入口
+type AccessReview struct {
+	Verb string `binding:"required"`
+	// 资源类型，支持单数与复数别名
+	Kind      string `binding:"required"`
+	Namespace string
+	Name      string
+	Allowed   bool
+}
+
+// Authorize 判断用户或服务账号是否被授权执行操作，用户通过其本身或所属的用户组绑定的角色获得授权
+func Authorize(username string, attrs Attributes) (bool, error) {
+	subjects, err := subjectsOf(username)
+	if err != nil {
+		return false, err
+	}
+
+	roleBindingRegistry := v1.NewRoleBindingRegistry()
+	roleRegistry := v1.NewRoleRegistry()
+	checked := make(map[string]bool)
+	for _, subject := range subjects {
+		roleBindings, err := roleBindingRegistry.ListByIndex(context.TODO(), core.IndexSubject, subject)
+		if err != nil {
+			log.Error(err)
+			return false, err
+		}
+		for _, obj := range roleBindings {
+			roleBinding := obj.(*v1.RoleBinding)
+			if checked[roleBinding.Metadata.Name] {
+				continue
+			}
+			checked[roleBinding.Metadata.Name] = true
+
+			inScope, err := bindingInScope(roleBinding, attrs)
+			if err != nil {
+				return false, err
+			} else if !inScope {
+				continue
+			}
+
+			roleObj, err := roleRegistry.Get(context.TODO(), "", roleBinding.Spec.RoleRef)
+			if err != nil {
+				log.Error(err)
+				return false, err
+			} else if roleObj == nil {
+				continue
+			}
+			for _, rule := range roleObj.(*v1.Role).Spec.Rules {
+				if ruleMatches(rule, attrs) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// MatchRules 判断授权规则中是否存在匹配操作与资源的规则，用于校验令牌的授权范围
+func MatchRules(rules []v1.PolicyRule, attrs Attributes) bool {
+	for _, rule := range rules {
+		if ruleMatches(rule, attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+// subjectsOf 获取用户或服务账号对应的角色绑定主体索引值，用户或服务账号不存在时返回空
+func subjectsOf(username string) ([]string, error) {
+	if name, ok := auth.ParseServiceAccountUsername(username); ok {
+		return []string{core.IndexValue(core.SubjectKindServiceAccount, name)}, nil
+	}
+
+	userObj, err := v1.NewUserRegistry().Get(context.TODO(), "", username)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	} else if userObj == nil {
+		return nil, nil
+	}
+	subjects := []string{core.IndexValue(core.SubjectKindUser, username)}
+	for _, group := range userObj.(*v1.User).Spec.Groups {
+		subjects = append(subjects, core.IndexValue(core.SubjectKindGroup, group))
+	}
+	return subjects, nil
+}
+
+// bindingInScope 判断资源是否在角色绑定的授权范围内，绑定了项目时只授权项目本身及项目所引用的命名空间中的资源
+func bindingInScope(roleBinding *v1.RoleBinding, attrs Attributes) (bool, error) {
+	if roleBinding.Spec.ProjectRef == "" {
+		return true, nil
+	}
+	if attrs.Kind == core.KindProject && attrs.Name == roleBinding.Spec.ProjectRef {
+		return true, nil
+	}
+
+	namespace := scopeNamespace(attrs)
+	if namespace == "" {
+		return false, nil
+	}
+	projectObj, err := v1.NewProjectRegistry().Get(context.TODO(), "", roleBinding.Spec.ProjectRef)
+	if err != nil {
+		log.Error(err)
+		return false, err
+	} else if projectObj == nil {
+		return false, nil
+	}
+	return contains(projectObj.(*v1.Project).ReferNamespaces, namespace), nil
+}
+
+// ruleMatches 判断授权规则是否匹配操作与资源
+func ruleMatches(rule v1.PolicyRule, attrs Attributes) bool {
+	if !containsOrAll(rule.Verbs, attrs.Verb) || !containsOrAll(rule.Kinds, attrs.Kind) {
+		return false
+	}
+	if len(rule.Namespaces) == 0 {
+		return true
+	}
+	// 限定了命名空间的规则不匹配跨命名空间或不属于命名空间的请求
+	namespace := scopeNamespace(attrs)
+	if namespace == "" {
+		return false
+	}
+	return containsOrAll(rule.Namespaces, namespace)
+}
+
+// scopeNamespace 获取用于划分授权范围的命名空间，命名空间资源本身按其名称划分
+func scopeNamespace(attrs Attributes) string {
+	if attrs.Kind == core.KindNamespace {
+		return attrs.Name
+	}
+	return attrs.Namespace
+}
+
+func containsOrAll(values []string, value string) bool {
+	return contains(values, "*") || contains(values, value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wujie1993/waves/pkg/db/dbtest"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+	"github.com/wujie1993/waves/pkg/rbac"
+	// 注册资源对象的实例化与转换方法
+	_ "github.com/wujie1993/waves/pkg/orm"
+)
+
+func TestRBAC(t *testing.T) {
+	defer dbtest.NewBoltKV(t)()
+
+	// 项目会自动关联同名命名空间
+	project := v1.NewProject()
+	project.Metadata.Name = "proj"
+	project.ReferNamespaces = []string{"shared"}
+	if _, err := v1.NewProjectRegistry().Create(context.TODO(), project); err != nil {
+		t.Fatal(err)
+	}
+
+	user := v1.NewUser()
+	user.Metadata.Name = "alice"
+	user.Spec.Password = "secret"
+	user.Spec.Groups = []string{"dev"}
+	if _, err := v1.NewUserRegistry().Create(context.TODO(), user); err != nil {
+		t.Fatal(err)
+	}
+
+	roleRegistry := v1.NewRoleRegistry()
+	invalid := v1.NewRole()
+	invalid.Metadata.Name = "invalid"
+	invalid.Spec.Rules = []v1.PolicyRule{{Verbs: []string{"watch"}, Kinds: []string{"*"}}}
+	if _, err := roleRegistry.Create(context.TODO(), invalid); err == nil {
+		t.Fatal("expected error when creating role with unknown verb")
+	}
+	viewer := v1.NewRole()
+	viewer.Metadata.Name = "viewer"
+	viewer.Spec.Rules = []v1.PolicyRule{{Verbs: []string{core.VerbGet, core.VerbList}, Kinds: []string{"*"}}}
+	if _, err := roleRegistry.Create(context.TODO(), viewer); err != nil {
+		t.Fatal(err)
+	}
+	deployer := v1.NewRole()
+	deployer.Metadata.Name = "deployer"
+	deployer.Spec.Rules = []v1.PolicyRule{{
+		Verbs:      []string{core.VerbUpdate, core.VerbAction},
+		Kinds:      []string{core.KindAppInstance},
+		Namespaces: []string{"proj"},
+	}}
+	if _, err := roleRegistry.Create(context.TODO(), deployer); err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户通过所属用户组获得项目范围内的只读权限，通过用户本身获得部署权限
+	roleBindingRegistry := v1.NewRoleBindingRegistry()
+	viewerBinding := v1.NewRoleBinding()
+	viewerBinding.Metadata.Name = "dev-viewer"
+	viewerBinding.Spec.RoleRef = "viewer"
+	viewerBinding.Spec.ProjectRef = "proj"
+	viewerBinding.Spec.Subjects = []v1.Subject{{Kind: core.SubjectKindGroup, Name: "dev"}}
+	if _, err := roleBindingRegistry.Create(context.TODO(), viewerBinding); err != nil {
+		t.Fatal(err)
+	}
+	deployerBinding := v1.NewRoleBinding()
+	deployerBinding.Metadata.Name = "alice-deployer"
+	deployerBinding.Spec.RoleRef = "deployer"
+	deployerBinding.Spec.Subjects = []v1.Subject{{Kind: core.SubjectKindUser, Name: "alice"}}
+	if _, err := roleBindingRegistry.Create(context.TODO(), deployerBinding); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		attrs   rbac.Attributes
+		allowed bool
+	}{
+		{rbac.Attributes{Verb: core.VerbGet, Kind: core.KindAppInstance, Namespace: "proj", Name: "a"}, true},
+		{rbac.Attributes{Verb: core.VerbList, Kind: core.KindAppInstance, Namespace: "shared"}, true},
+		{rbac.Attributes{Verb: core.VerbGet, Kind: core.KindAppInstance, Namespace: "other", Name: "a"}, false},
+		{rbac.Attributes{Verb: core.VerbGet, Kind: core.KindProject, Name: "proj"}, true},
+		{rbac.Attributes{Verb: core.VerbGet, Kind: core.KindNamespace, Name: "shared"}, true},
+		{rbac.Attributes{Verb: core.VerbGet, Kind: core.KindHost, Name: "h"}, false},
+		{rbac.Attributes{Verb: core.VerbAction, Kind: core.KindAppInstance, Namespace: "proj", Name: "a"}, true},
+		{rbac.Attributes{Verb: core.VerbAction, Kind: core.KindAppInstance, Namespace: "shared", Name: "a"}, false},
+		{rbac.Attributes{Verb: core.VerbDelete, Kind: core.KindAppInstance, Namespace: "proj", Name: "a"}, false},
+		// 限定了命名空间的规则不能用于跨命名空间的请求
+		{rbac.Attributes{Verb: core.VerbUpdate, Kind: core.KindAppInstance}, false},
+		{rbac.Attributes{Verb: core.VerbAction, Kind: core.KindAppInstance, Name: "a"}, false},
+	}
+	for _, c := range cases {
+		allowed, err := rbac.Authorize("alice", c.attrs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != c.allowed {
+			t.Errorf("expected allowed=%v for %+v, got %v", c.allowed, c.attrs, allowed)
+		}
+	}
+
+	// 不存在的用户不被授权
+	if allowed, err := rbac.Authorize("bob", rbac.Attributes{Verb: core.VerbGet, Kind: core.KindHost}); err != nil {
+		t.Fatal(err)
+	} else if allowed {
+		t.Fatal("unknown user should not be authorized")
+	}
+}
@@ -48,12 +48,74 @@ type Package struct {
 
 var PackageSetting = &Package{}
 
+// Etcd etcd连接配置，证书路径为空时不启用TLS，用户名为空时不启用认证
 type Etcd struct {
 	Endpoints []string
+	// CA证书路径，用于校验etcd服务端证书
+	CAFile string
+	// 客户端证书与私钥路径，用于etcd开启客户端证书认证时
+	CertFile string
+	KeyFile  string
+	Username string
+	Password string
+	// 建立连接的超时时长，为0时使用默认值
+	DialTimeout time.Duration
+	// 单次请求的超时时长，为0时使用默认值
+	RequestTimeout time.Duration
+	// 请求超时后的最大尝试次数，为0时使用默认值
+	RetryTimes int
 }
 
 var EtcdSetting = &Etcd{}
 
+type Storage struct {
+	Driver string
+	Path   string
+}
+
+var StorageSetting = &Storage{}
+
+// Retention 各类资源的保留时长，超过保留时长后资源会被自动删除，为0时表示永久保留
+type Retention struct {
+	Event            time.Duration
+	HealthCheckEvent time.Duration
+	Audit            time.Duration
+	Job              time.Duration
+}
+
+var RetentionSetting = &Retention{}
+
+// Revision 各类资源保留的修订版本数量上限，超出时删除最早的修订版本，为0时表示不限制
+type Revision struct {
+	App         int
+	AppInstance int
+	ConfigMap   int
+	Host        int
+	K8sConfig   int
+	Project     int
+}
+
+var RevisionSetting = &Revision{}
+
+// Encryption 敏感字段的加密存储配置，密钥文件为空时不进行加密
+type Encryption struct {
+	KeyFile string
+}
+
+var EncryptionSetting = &Encryption{}
+
+// Auth 接口认证配置，启用后除登录与刷新令牌外的接口都需要携带访问令牌
+type Auth struct {
+	Enabled bool
+	// 访问令牌与刷新令牌的有效期
+	AccessTokenExpire  time.Duration
+	RefreshTokenExpire time.Duration
+	// 初始管理员admin的密码，仅在不存在任何用户时使用，为空时随机生成并输出到日志
+	AdminPassword string
+}
+
+var AuthSetting = &Auth{}
+
 type Ansible struct {
 	Bin          string
 	BaseDir      string
@@ -79,6 +141,11 @@ func Setup() {
 	mapTo("server", ServerSetting)
 	mapTo("package", PackageSetting)
 	mapTo("etcd", EtcdSetting)
+	mapTo("storage", StorageSetting)
+	mapTo("retention", RetentionSetting)
+	mapTo("revision", RevisionSetting)
+	mapTo("encryption", EncryptionSetting)
+	mapTo("auth", AuthSetting)
 	mapTo("ansible", AnsibleSetting)
 
 	ServerSetting.ReadTimeout = ServerSetting.ReadTimeout * time.Second
@@ -6,37 +6,56 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
+const (
+	// 访问令牌，用于调用接口
+	TokenTypeAccess = "access"
+	// 刷新令牌，仅用于换取新的访问令牌
+	TokenTypeRefresh = "refresh"
+
+	tokenIssuer = "waves"
+)
+
 var jwtSecret []byte
 
+// Claims 令牌中携带的声明
 type Claims struct {
 	Username string `json:"username"`
-	Password string `json:"password"`
+	// 令牌类型，可选值为access与refresh
+	Type string `json:"type"`
+	// 签发时用户的令牌代数，与用户当前的令牌代数不一致时令牌失效
+	Generation int `json:"generation"`
 	jwt.StandardClaims
 }
 
-// GenerateToken generate tokens used for auth
-func GenerateToken(username, password string) (string, error) {
+// GenerateToken 为用户签发指定类型与有效期的令牌
+func GenerateToken(username string, tokenType string, generation int, expire time.Duration) (string, time.Time, error) {
 	nowTime := time.Now()
-	expireTime := nowTime.Add(3 * time.Hour)
+	expireTime := nowTime.Add(expire)
 
 	claims := Claims{
-		EncodeMD5(username),
-		EncodeMD5(password),
-		jwt.StandardClaims{
+		Username:   username,
+		Type:       tokenType,
+		Generation: generation,
+		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expireTime.Unix(),
-			Issuer:    "gin-blog",
+			IssuedAt:  nowTime.Unix(),
+			Issuer:    tokenIssuer,
+			Subject:   username,
 		},
 	}
 
 	tokenClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	token, err := tokenClaims.SignedString(jwtSecret)
 
-	return token, err
+	return token, expireTime, err
 }
 
-// ParseToken parsing token
+// ParseToken 解析并校验令牌的签名与有效期
 func ParseToken(token string) (*Claims, error) {
 	tokenClaims, err := jwt.ParseWithClaims(token, &Claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
 		return jwtSecret, nil
 	})
 
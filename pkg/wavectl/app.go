@@ -18,7 +18,7 @@ type AppClient struct {
 	clientset.ClientSet
 }
 
-func (c AppClient) GetPrint(namespace string, name string, format string) error {
+func (c AppClient) GetPrint(namespace string, name string, format string, labelSelector string, fieldSelector string) error {
 	if name != "" {
 		app, err := c.ClientSet.V1().Apps("default").Get(context.TODO(), name)
 		if err != nil {
@@ -28,7 +28,7 @@ func (c AppClient) GetPrint(namespace string, name string, format string) error
 		return printApps([]v1.App{*app}, format)
 	}
 
-	apps, err := c.ClientSet.V1().Apps("default").List(context.TODO())
+	apps, err := c.ClientSet.V1().Apps("default").ListBySelector(context.TODO(), labelSelector, fieldSelector)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -36,22 +36,54 @@ func (c AppClient) GetPrint(namespace string, name string, format string) error
 	return printApps(apps, format)
 }
 
-func (c AppClient) Apply(obj core.ApiObject) (core.ApiObject, error) {
+func (c AppClient) Apply(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	return nil, e.Errorf("unsupported function")
 }
 
-func (c AppClient) Create(obj core.ApiObject) (core.ApiObject, error) {
+func (c AppClient) Create(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	return nil, e.Errorf("unsupported function")
 }
 
-func (c AppClient) Update(obj core.ApiObject) (core.ApiObject, error) {
+func (c AppClient) Update(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	return nil, e.Errorf("unsupported function")
 }
 
-func (c AppClient) Delete(namespace string, name string) (core.ApiObject, error) {
+func (c AppClient) Delete(namespace string, name string, opts ...core.OpOpt) (core.ApiObject, error) {
 	return nil, e.Errorf("unsupported function")
 }
 
+func (c AppClient) Get(namespace string, name string) (core.ApiObject, error) {
+	return c.ClientSet.V1().Apps("default").Get(context.TODO(), name)
+}
+
+// ListRevisions 列举应用的所有修订版本
+func (c AppClient) ListRevisions(namespace string, name string) (core.ApiObjectList, error) {
+	revisions, err := c.ClientSet.V1().Apps("default").ListRevisions(context.TODO(), name)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	result := core.ApiObjectList{}
+	for i := range revisions {
+		result = append(result, &revisions[i])
+	}
+	return result, nil
+}
+
+func (c AppClient) DiffRevisions(namespace string, name string, revision int, target int) (*core.RevisionDiff, error) {
+	return c.ClientSet.V1().Apps("default").DiffRevisions(context.TODO(), name, revision, target)
+}
+
+func (c AppClient) RevertRevision(namespace string, name string, revision int) (core.ApiObject, error) {
+	result, err := c.ClientSet.V1().Apps("default").RevertRevision(context.TODO(), name, revision)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	fmt.Printf("%s rolled back to revision %d\n", result.GetKey(), revision)
+	return result, nil
+}
+
 func printApps(apps []v1.App, format string) error {
 	switch format {
 	case OutputFormatJSON:
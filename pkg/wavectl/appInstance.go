@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
@@ -19,7 +18,7 @@ type AppInstanceClient struct {
 	clientset.ClientSet
 }
 
-func (c AppInstanceClient) GetPrint(namespace string, name string, format string) error {
+func (c AppInstanceClient) GetPrint(namespace string, name string, format string, labelSelector string, fieldSelector string) error {
 	if name != "" {
 		appInstance, err := c.ClientSet.V2().AppInstances(namespace).Get(context.TODO(), name)
 		if err != nil {
@@ -29,7 +28,7 @@ func (c AppInstanceClient) GetPrint(namespace string, name string, format string
 		return printAppInstances([]v2.AppInstance{*appInstance}, format)
 	}
 
-	appInstances, err := c.ClientSet.V2().AppInstances(namespace).List(context.TODO())
+	appInstances, err := c.ClientSet.V2().AppInstances(namespace).ListBySelector(context.TODO(), labelSelector, fieldSelector)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -38,22 +37,27 @@ func (c AppInstanceClient) GetPrint(namespace string, name string, format string
 	return printAppInstances(appInstances, format)
 }
 
-func (c AppInstanceClient) Apply(obj core.ApiObject) (core.ApiObject, error) {
-	meta := obj.GetMetadata()
-
-	getObj, err := c.ClientSet.V2().AppInstances(meta.Namespace).Get(context.TODO(), meta.Name)
-	if err != nil && !strings.Contains(err.Error(), "not found") {
+// Apply 由服务端与上次应用的配置进行三方合并，应用实例不存在时创建
+func (c AppInstanceClient) Apply(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
+	// 转换成v2版本结构
+	obj, err := orm.Convert(obj, core.GVK{Group: core.Group, ApiVersion: v2.ApiVersion, Kind: core.KindAppInstance})
+	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	if getObj == nil {
-		return c.Create(obj)
+	appInstance := obj.(*v2.AppInstance)
+
+	result, err := c.ClientSet.V2().AppInstances(appInstance.Metadata.Namespace).Apply(context.TODO(), appInstance, opts...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
+	fmt.Printf("%s configured%s\n", result.GetKey(), dryRunSuffix(opts...))
 
-	return c.Update(obj)
+	return result, nil
 }
 
-func (c AppInstanceClient) Create(obj core.ApiObject) (core.ApiObject, error) {
+func (c AppInstanceClient) Create(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	// 转换成v2版本结构
 	obj, err := orm.Convert(obj, core.GVK{Group: core.Group, ApiVersion: v2.ApiVersion, Kind: core.KindAppInstance})
 	if err != nil {
@@ -62,17 +66,17 @@ func (c AppInstanceClient) Create(obj core.ApiObject) (core.ApiObject, error) {
 	}
 	appInstance := obj.(*v2.AppInstance)
 
-	result, err := c.ClientSet.V2().AppInstances(appInstance.Metadata.Namespace).Create(context.TODO(), appInstance)
+	result, err := c.ClientSet.V2().AppInstances(appInstance.Metadata.Namespace).Create(context.TODO(), appInstance, opts...)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	fmt.Printf("%s created\n", result.GetKey())
+	fmt.Printf("%s created%s\n", result.GetKey(), dryRunSuffix(opts...))
 
 	return result, nil
 }
 
-func (c AppInstanceClient) Update(obj core.ApiObject) (core.ApiObject, error) {
+func (c AppInstanceClient) Update(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	// 转换成v2版本结构
 	obj, err := orm.Convert(obj, core.GVK{Group: core.Group, ApiVersion: v2.ApiVersion, Kind: core.KindAppInstance})
 	if err != nil {
@@ -80,18 +84,50 @@ func (c AppInstanceClient) Update(obj core.ApiObject) (core.ApiObject, error) {
 	}
 	appInstance := obj.(*v2.AppInstance)
 
-	result, err := c.ClientSet.V2().AppInstances(appInstance.Metadata.Namespace).Update(context.TODO(), appInstance)
+	result, err := c.ClientSet.V2().AppInstances(appInstance.Metadata.Namespace).Update(context.TODO(), appInstance, opts...)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	fmt.Printf("%s updated\n", result.GetKey())
+	fmt.Printf("%s updated%s\n", result.GetKey(), dryRunSuffix(opts...))
 
 	return result, nil
 }
 
-func (c AppInstanceClient) Delete(namespace string, name string) (core.ApiObject, error) {
-	return c.ClientSet.V2().AppInstances(namespace).Delete(context.TODO(), name)
+func (c AppInstanceClient) Delete(namespace string, name string, opts ...core.OpOpt) (core.ApiObject, error) {
+	return c.ClientSet.V2().AppInstances(namespace).Delete(context.TODO(), name, opts...)
+}
+
+func (c AppInstanceClient) Get(namespace string, name string) (core.ApiObject, error) {
+	return c.ClientSet.V2().AppInstances(namespace).Get(context.TODO(), name)
+}
+
+// ListRevisions 列举应用实例的所有修订版本
+func (c AppInstanceClient) ListRevisions(namespace string, name string) (core.ApiObjectList, error) {
+	revisions, err := c.ClientSet.V2().AppInstances(namespace).ListRevisions(context.TODO(), name)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	result := core.ApiObjectList{}
+	for i := range revisions {
+		result = append(result, &revisions[i])
+	}
+	return result, nil
+}
+
+func (c AppInstanceClient) DiffRevisions(namespace string, name string, revision int, target int) (*core.RevisionDiff, error) {
+	return c.ClientSet.V2().AppInstances(namespace).DiffRevisions(context.TODO(), name, revision, target)
+}
+
+func (c AppInstanceClient) RevertRevision(namespace string, name string, revision int) (core.ApiObject, error) {
+	result, err := c.ClientSet.V2().AppInstances(namespace).RevertRevision(context.TODO(), name, revision)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	fmt.Printf("%s rolled back to revision %d\n", result.GetKey(), revision)
+	return result, nil
 }
 
 func printAppInstances(appInstances []v2.AppInstance, format string) error {
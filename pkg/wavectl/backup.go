@@ -0,0 +1,62 @@
+package wavectl
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+)
+
+// BackupOptions 数据备份配置项
+type BackupOptions struct {
+	Endpoint string
+	Output   string
+}
+
+// RestoreOptions 数据还原配置项
+type RestoreOptions struct {
+	Endpoint string
+	File     string
+}
+
+// Backup 导出全量数据备份并写入本地文件
+func Backup(opts BackupOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	archive, err := clientSet.V1().Backup(context.TODO())
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+
+	if err := ioutil.WriteFile(opts.Output, archive, 0600); err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	fmt.Printf("backup saved to %s\n", opts.Output)
+}
+
+// Restore 使用本地的备份文件还原数据
+func Restore(opts RestoreOptions) {
+	defer exit()
+
+	archive, err := ioutil.ReadFile(opts.File)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+
+	initClient(opts.Endpoint)
+
+	result, err := clientSet.V1().Restore(context.TODO(), archive)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	fmt.Println(result)
+}
@@ -18,15 +18,15 @@ func exit() {
 var clientSet clientset.ClientSet
 
 type ResourceManager interface {
-	GetPrint(namespace string, name string, format string) error
-	Apply(obj core.ApiObject) (core.ApiObject, error)
-	Create(obj core.ApiObject) (core.ApiObject, error)
-	Update(obj core.ApiObject) (core.ApiObject, error)
-	Delete(namespace string, name string) (core.ApiObject, error)
+	GetPrint(namespace string, name string, format string, labelSelector string, fieldSelector string) error
+	Apply(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error)
+	Create(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error)
+	Update(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error)
+	Delete(namespace string, name string, opts ...core.OpOpt) (core.ApiObject, error)
 }
 
 func initClient(endpoint string) {
-	clientSet = clientset.NewClientSet(endpoint)
+	clientSet = clientset.NewClientSetWithToken(endpoint, resolveToken(endpoint))
 }
 
 func getClient(kind string) ResourceManager {
@@ -58,18 +58,26 @@ type GetResourceOptions struct {
 	ResourceName string
 	Namespace    string
 	Format       string
+	// 标签选择器，仅在未指定资源名称时生效
+	LabelSelector string
+	// 字段选择器，仅在未指定资源名称时生效
+	FieldSelector string
 }
 
 // CreateResourceOptions 创建资源配置项
 type CreateResourceOptions struct {
 	Endpoint string
 	File     string
+	// 试运行模式，可选值为none与server
+	DryRun string
 }
 
 // ApplyResourceOptions 资源应用配置项
 type ApplyResourceOptions struct {
 	Endpoint string
 	File     string
+	// 试运行模式，可选值为none与server
+	DryRun string
 }
 
 // DeleteResourceOptions 资源删除配置项
@@ -79,6 +87,8 @@ type DeleteResourceOptions struct {
 	Resource     string
 	ResourceName string
 	File         string
+	// 试运行模式，可选值为none与server
+	DryRun string
 }
 
 // HostPluginOptions 主机插件操作配置项
@@ -106,7 +116,7 @@ func GetResource(opts GetResourceOptions) {
 		return
 	}
 
-	if err := cli.GetPrint(opts.Namespace, opts.ResourceName, opts.Format); err != nil {
+	if err := cli.GetPrint(opts.Namespace, opts.ResourceName, opts.Format, opts.LabelSelector, opts.FieldSelector); err != nil {
 		fmt.Println(err)
 		exitCode++
 		return
@@ -117,6 +127,13 @@ func GetResource(opts GetResourceOptions) {
 func CreateResource(opts CreateResourceOptions) {
 	defer exit()
 
+	writeOpts, err := dryRunOpts(opts.DryRun)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+
 	var objs []core.ApiObject
 
 	if opts.File != "" {
@@ -142,7 +159,7 @@ func CreateResource(opts CreateResourceOptions) {
 			continue
 		}
 
-		if _, err := cli.Create(obj); err != nil {
+		if _, err := cli.Create(obj, writeOpts...); err != nil {
 			fmt.Println(err)
 			exitCode++
 			continue
@@ -154,6 +171,13 @@ func CreateResource(opts CreateResourceOptions) {
 func ApplyResource(opts ApplyResourceOptions) {
 	defer exit()
 
+	writeOpts, err := dryRunOpts(opts.DryRun)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+
 	initClient(opts.Endpoint)
 
 	/* 更新本地文件中指定的资源 */
@@ -181,7 +205,7 @@ func ApplyResource(opts ApplyResourceOptions) {
 			continue
 		}
 
-		if _, err := cli.Apply(obj); err != nil {
+		if _, err := cli.Apply(obj, writeOpts...); err != nil {
 			fmt.Println(err)
 			exitCode++
 			continue
@@ -193,6 +217,13 @@ func ApplyResource(opts ApplyResourceOptions) {
 func DeleteResource(opts DeleteResourceOptions) {
 	defer exit()
 
+	writeOpts, err := dryRunOpts(opts.DryRun)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+
 	initClient(opts.Endpoint)
 
 	/* 删除指定类型资源 */
@@ -205,10 +236,13 @@ func DeleteResource(opts DeleteResourceOptions) {
 			return
 		}
 
-		if _, err := cli.Delete(opts.Namespace, opts.ResourceName); err != nil {
+		result, err := cli.Delete(opts.Namespace, opts.ResourceName, writeOpts...)
+		if err != nil {
 			fmt.Println(err)
 			exitCode++
+			return
 		}
+		printDryRunDeleted(result, writeOpts...)
 		return
 	}
 
@@ -238,11 +272,20 @@ func DeleteResource(opts DeleteResourceOptions) {
 			continue
 		}
 
-		if _, err := cli.Delete(meta.Namespace, meta.Name); err != nil {
+		result, err := cli.Delete(meta.Namespace, meta.Name, writeOpts...)
+		if err != nil {
 			fmt.Println(err)
 			exitCode++
 			continue
 		}
+		printDryRunDeleted(result, writeOpts...)
+	}
+}
+
+// printDryRunDeleted 试运行删除时输出将被删除的资源
+func printDryRunDeleted(obj core.ApiObject, opts ...core.OpOpt) {
+	if suffix := dryRunSuffix(opts...); suffix != "" && obj != nil {
+		fmt.Printf("%s deleted%s\n", obj.GetKey(), suffix)
 	}
 }
 
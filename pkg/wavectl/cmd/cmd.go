@@ -11,7 +11,14 @@ import (
 )
 
 func Execute() {
-	getCmd := NewResourceCmd()
+	// get命令的-l用于指定标签选择器，日志级别只能通过--level指定
+	getCmd := new(cobra.Command)
+	getCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	getCmd.Flags().StringP("namespace", "n", "default", "the namespace to which the resource belongs")
+	getCmd.Flags().StringP("format", "", "table", "resource output format")
+	getCmd.Flags().IntP("level", "", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+	getCmd.Flags().StringP("selector", "l", "", "label selector to filter on, supports '=', '==', '!=', 'in', 'notin' and '!key' (e.g. -l app=nginx,tier in (web,db))")
+	getCmd.Flags().StringP("field-selector", "", "", "field selector to filter on, supports '=', '==' and '!=' (e.g. --field-selector status.phase=Running)")
 	getCmd.Use = "get [RESOURCE TYPE] [RESOURCE NAME]"
 	getCmd.Short = "Get resources"
 	getCmd.Args = cobra.MinimumNArgs(1)
@@ -40,6 +47,18 @@ func Execute() {
 			os.Exit(1)
 		}
 
+		selector, err := cmd.Flags().GetString("selector")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		fieldSelector, err := cmd.Flags().GetString("field-selector")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		log.SetLevel(log.Level(level))
 
 		var resource string
@@ -52,11 +71,13 @@ func Execute() {
 		}
 
 		wavectl.GetResource(wavectl.GetResourceOptions{
-			Endpoint:     endpoint,
-			Namespace:    namespace,
-			Resource:     resource,
-			ResourceName: resourceName,
-			Format:       format,
+			Endpoint:      endpoint,
+			Namespace:     namespace,
+			Resource:      resource,
+			ResourceName:  resourceName,
+			Format:        format,
+			LabelSelector: selector,
+			FieldSelector: fieldSelector,
 		})
 	}
 
@@ -83,11 +104,18 @@ func Execute() {
 			os.Exit(1)
 		}
 
+		dryRun, err := cmd.Flags().GetString("dry-run")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		log.SetLevel(log.Level(level))
 
 		wavectl.CreateResource(wavectl.CreateResourceOptions{
 			Endpoint: endpoint,
 			File:     file,
+			DryRun:   dryRun,
 		})
 	}
 
@@ -113,11 +141,18 @@ func Execute() {
 			os.Exit(1)
 		}
 
+		dryRun, err := cmd.Flags().GetString("dry-run")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		log.SetLevel(log.Level(level))
 
 		wavectl.ApplyResource(wavectl.ApplyResourceOptions{
 			Endpoint: endpoint,
 			File:     file,
+			DryRun:   dryRun,
 		})
 	}
 
@@ -158,6 +193,12 @@ func Execute() {
 			os.Exit(1)
 		}
 
+		dryRun, err := cmd.Flags().GetString("dry-run")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
 		log.SetLevel(log.Level(level))
 
 		wavectl.DeleteResource(wavectl.DeleteResourceOptions{
@@ -166,6 +207,7 @@ func Execute() {
 			Resource:     resource,
 			ResourceName: resourceName,
 			File:         file,
+			DryRun:       dryRun,
 		})
 	}
 
@@ -231,15 +273,348 @@ func Execute() {
 	hostPluginCmd.MarkFlagRequired("host")
 	hostPluginCmd.MarkFlagRequired("plugin-name")
 
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Backup all data of visible deploy platform",
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, err := cmd.Flags().GetString("endpoint")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			output, err := cmd.Flags().GetString("output")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			level, err := cmd.Flags().GetInt("level")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			log.SetLevel(log.Level(level))
+
+			wavectl.Backup(wavectl.BackupOptions{
+				Endpoint: endpoint,
+				Output:   output,
+			})
+		},
+	}
+	backupCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	backupCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+	backupCmd.Flags().StringP("output", "o", "", "the local path to save the backup archive")
+	backupCmd.MarkFlagRequired("output")
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore data of visible deploy platform from backup archive",
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, err := cmd.Flags().GetString("endpoint")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			file, err := cmd.Flags().GetString("file")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			level, err := cmd.Flags().GetInt("level")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			log.SetLevel(log.Level(level))
+
+			wavectl.Restore(wavectl.RestoreOptions{
+				Endpoint: endpoint,
+				File:     file,
+			})
+		},
+	}
+	restoreCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	restoreCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+	restoreCmd.Flags().StringP("file", "f", "", "the local path of the backup archive")
+	restoreCmd.MarkFlagRequired("file")
+
+	rotateKeyCmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Rotate the encryption key and re-encrypt all sensitive data",
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, err := cmd.Flags().GetString("endpoint")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			level, err := cmd.Flags().GetInt("level")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			log.SetLevel(log.Level(level))
+
+			wavectl.RotateKey(wavectl.RotateKeyOptions{
+				Endpoint: endpoint,
+			})
+		},
+	}
+	rotateKeyCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	rotateKeyCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+
+	rolloutCmd := &cobra.Command{
+		Use:   "rollout",
+		Short: "Manage the revisions of a resource",
+	}
+	rolloutActions := []struct {
+		use   string
+		short string
+		run   func(wavectl.RolloutOptions)
+	}{
+		{"history", "View the revision history of a resource", wavectl.RolloutHistory},
+		{"diff", "Show the differences between two revisions of a resource", wavectl.RolloutDiff},
+		{"undo", "Roll back a resource to a previous revision", wavectl.RolloutUndo},
+	}
+	for _, action := range rolloutActions {
+		run := action.run
+		actionCmd := &cobra.Command{
+			Use:   action.use + " [RESOURCE TYPE] [RESOURCE NAME]",
+			Short: action.short,
+			Args:  cobra.ExactArgs(2),
+			Run: func(cmd *cobra.Command, args []string) {
+				endpoint, err := cmd.Flags().GetString("endpoint")
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				namespace, err := cmd.Flags().GetString("namespace")
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				level, err := cmd.Flags().GetInt("level")
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				log.SetLevel(log.Level(level))
+
+				opts := wavectl.RolloutOptions{
+					Endpoint:     endpoint,
+					Namespace:    namespace,
+					Resource:     args[0],
+					ResourceName: args[1],
+				}
+				if cmd.Flags().Lookup("revision") != nil {
+					if opts.Revision, err = cmd.Flags().GetInt("revision"); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+				if cmd.Flags().Lookup("to-revision") != nil {
+					if opts.Revision, err = cmd.Flags().GetInt("to-revision"); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+				if cmd.Flags().Lookup("to") != nil {
+					if opts.ToRevision, err = cmd.Flags().GetInt("to"); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+
+				run(opts)
+			},
+		}
+		actionCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+		actionCmd.Flags().StringP("namespace", "n", "default", "the namespace to which the resource belongs")
+		actionCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+		switch action.use {
+		case "diff":
+			actionCmd.Flags().IntP("revision", "", 0, "the revision to compare from, defaults to the latest revision")
+			actionCmd.Flags().IntP("to", "", 0, "the revision to compare to, defaults to the current content of the resource")
+		case "undo":
+			actionCmd.Flags().IntP("to-revision", "", 0, "the revision to roll back to, defaults to the latest revision")
+		}
+		rolloutCmd.AddCommand(actionCmd)
+	}
+
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to visible deploy platform and save the token for later commands",
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, err := cmd.Flags().GetString("endpoint")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			username, err := cmd.Flags().GetString("username")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			password, err := cmd.Flags().GetString("password")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			level, err := cmd.Flags().GetInt("level")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			log.SetLevel(log.Level(level))
+
+			wavectl.Login(wavectl.LoginOptions{
+				Endpoint: endpoint,
+				Username: username,
+				Password: password,
+			})
+		},
+	}
+	loginCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	loginCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+	loginCmd.Flags().StringP("username", "u", "", "the username to log in as")
+	loginCmd.Flags().StringP("password", "p", "", "the password of the user, read from the terminal if not specified")
+	loginCmd.MarkFlagRequired("username")
+
+	logoutCmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Revoke all tokens of the current user and remove the saved token",
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, err := cmd.Flags().GetString("endpoint")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			level, err := cmd.Flags().GetInt("level")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			log.SetLevel(log.Level(level))
+
+			wavectl.Logout(wavectl.LogoutOptions{
+				Endpoint: endpoint,
+			})
+		},
+	}
+	logoutCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	logoutCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+
+	tokenCmd := &cobra.Command{
+		Use:   "token [create|revoke]",
+		Short: "Manage the api tokens of service accounts",
+	}
+	tokenCreateCmd := &cobra.Command{
+		Use:   "create [SERVICE ACCOUNT] [TOKEN NAME]",
+		Short: "Create an api token for the service account and print it, the token is only shown once",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, err := cmd.Flags().GetString("endpoint")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			level, err := cmd.Flags().GetInt("level")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			expiresIn, err := cmd.Flags().GetString("expires-in")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			log.SetLevel(log.Level(level))
+
+			wavectl.CreateToken(wavectl.CreateTokenOptions{
+				Endpoint:       endpoint,
+				ServiceAccount: args[0],
+				Name:           args[1],
+				ExpiresIn:      expiresIn,
+			})
+		},
+	}
+	tokenCreateCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	tokenCreateCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+	tokenCreateCmd.Flags().StringP("expires-in", "", "", "the lifetime of the token such as 720h, never expires if not specified")
+	tokenRevokeCmd := &cobra.Command{
+		Use:   "revoke [SERVICE ACCOUNT] [TOKEN NAME]",
+		Short: "Revoke an api token of the service account",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			endpoint, err := cmd.Flags().GetString("endpoint")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			level, err := cmd.Flags().GetInt("level")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			log.SetLevel(log.Level(level))
+
+			wavectl.RevokeToken(wavectl.RevokeTokenOptions{
+				Endpoint:       endpoint,
+				ServiceAccount: args[0],
+				Name:           args[1],
+			})
+		},
+	}
+	tokenRevokeCmd.Flags().StringP("endpoint", "e", "http://127.0.0.1:8000/deployer", "api endpoint of visible deploy platform")
+	tokenRevokeCmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
 	rootCmd := &cobra.Command{
 		Use:   "wavectl",
 		Short: "The command line tool of visible deploy platform",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			token, err := cmd.Flags().GetString("token")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			wavectl.SetToken(token)
+		},
 	}
+	rootCmd.PersistentFlags().StringP("token", "", "", "the access token or service account token, defaults to $"+wavectl.TokenEnv+" or the token saved by login")
 	rootCmd.AddCommand(getCmd)
 	rootCmd.AddCommand(createCmd)
 	rootCmd.AddCommand(applyCmd)
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(hostPluginCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(rotateKeyCmd)
+	rootCmd.AddCommand(rolloutCmd)
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(tokenCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -254,5 +629,6 @@ func NewResourceCmd() *cobra.Command {
 	cmd.Flags().StringP("file", "f", "", "the local path of the loaded resource")
 	cmd.Flags().StringP("format", "", "table", "resource output format")
 	cmd.Flags().IntP("level", "l", 0, "logs level(0.Panic|1.Fatal|2.Error|3.Warn|4.Info|5.Debug|6.Trace)")
+	cmd.Flags().StringP("dry-run", "", "none", "must be \"none\" or \"server\", if server, submit the request without persisting the resource")
 	return cmd
 }
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 
 	"github.com/ghodss/yaml"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
 )
 
 const (
@@ -11,6 +14,9 @@ const (
 	OutputFormatJSONPretty = "json-pretty"
 	OutputFormatYAML       = "yaml"
 	OutputFormatTable      = "table"
+
+	DryRunNone   = "none"
+	DryRunServer = "server"
 )
 
 func ToJSON(obj interface{}, pretty bool) ([]byte, error) {
@@ -23,3 +29,24 @@ func ToJSON(obj interface{}, pretty bool) ([]byte, error) {
 func ToYAML(obj interface{}) ([]byte, error) {
 	return yaml.Marshal(obj)
 }
+
+// dryRunOpts 将--dry-run参数转换为写入选项，目前只支持由服务端试运行
+func dryRunOpts(dryRun string) ([]core.OpOpt, error) {
+	switch dryRun {
+	case "", DryRunNone:
+		return nil, nil
+	case DryRunServer:
+		return []core.OpOpt{core.WithDryRun()}, nil
+	}
+	return nil, e.Errorf("invalid dry-run value %s, must be one of %s or %s", dryRun, DryRunNone, DryRunServer)
+}
+
+// dryRunSuffix 试运行时在输出信息后追加的提示
+func dryRunSuffix(opts ...core.OpOpt) string {
+	var option core.Option
+	option.SetupOption(opts...)
+	if option.DryRun {
+		return " (server dry run)"
+	}
+	return ""
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
@@ -18,7 +17,7 @@ type ConfigMapClient struct {
 	clientset.ClientSet
 }
 
-func (c ConfigMapClient) GetPrint(namespace string, name string, format string) error {
+func (c ConfigMapClient) GetPrint(namespace string, name string, format string, labelSelector string, fieldSelector string) error {
 	if name != "" {
 		configMap, err := c.ClientSet.V1().ConfigMaps(namespace).Get(context.TODO(), name)
 		if err != nil {
@@ -28,7 +27,7 @@ func (c ConfigMapClient) GetPrint(namespace string, name string, format string)
 		return printConfigMaps([]v1.ConfigMap{*configMap}, format)
 	}
 
-	configMaps, err := c.ClientSet.V1().ConfigMaps(namespace).List(context.TODO())
+	configMaps, err := c.ClientSet.V1().ConfigMaps(namespace).ListBySelector(context.TODO(), labelSelector, fieldSelector)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -36,49 +35,80 @@ func (c ConfigMapClient) GetPrint(namespace string, name string, format string)
 	return printConfigMaps(configMaps, format)
 }
 
-func (c ConfigMapClient) Apply(obj core.ApiObject) (core.ApiObject, error) {
-	meta := obj.GetMetadata()
+// Apply 由服务端与上次应用的配置进行三方合并，配置字典不存在时创建
+func (c ConfigMapClient) Apply(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
+	configMap := obj.(*v1.ConfigMap)
 
-	getObj, err := c.ClientSet.V1().ConfigMaps(meta.Namespace).Get(context.TODO(), meta.Name)
-	if err != nil && !strings.Contains(err.Error(), "not found") {
+	result, err := c.ClientSet.V1().ConfigMaps(configMap.Metadata.Namespace).Apply(context.TODO(), configMap, opts...)
+	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	if getObj == nil {
-		return c.Create(obj)
-	}
+	fmt.Printf("%s configured%s\n", result.GetKey(), dryRunSuffix(opts...))
 
-	return c.Update(obj)
+	return result, nil
 }
 
-func (c ConfigMapClient) Create(obj core.ApiObject) (core.ApiObject, error) {
+func (c ConfigMapClient) Create(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	configMap := obj.(*v1.ConfigMap)
 
-	result, err := c.ClientSet.V1().ConfigMaps(configMap.Metadata.Namespace).Create(context.TODO(), configMap)
+	result, err := c.ClientSet.V1().ConfigMaps(configMap.Metadata.Namespace).Create(context.TODO(), configMap, opts...)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	fmt.Printf("%s created\n", result.GetKey())
+	fmt.Printf("%s created%s\n", result.GetKey(), dryRunSuffix(opts...))
 
 	return result, nil
 }
 
-func (c ConfigMapClient) Update(obj core.ApiObject) (core.ApiObject, error) {
+func (c ConfigMapClient) Update(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	configMap := obj.(*v1.ConfigMap)
 
-	result, err := c.ClientSet.V1().ConfigMaps(configMap.Metadata.Namespace).Update(context.TODO(), configMap)
+	result, err := c.ClientSet.V1().ConfigMaps(configMap.Metadata.Namespace).Update(context.TODO(), configMap, opts...)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	fmt.Printf("%s updated\n", result.GetKey())
+	fmt.Printf("%s updated%s\n", result.GetKey(), dryRunSuffix(opts...))
 
 	return result, nil
 }
 
-func (c ConfigMapClient) Delete(namespace string, name string) (core.ApiObject, error) {
-	return c.ClientSet.V1().ConfigMaps(namespace).Delete(context.TODO(), name)
+func (c ConfigMapClient) Delete(namespace string, name string, opts ...core.OpOpt) (core.ApiObject, error) {
+	return c.ClientSet.V1().ConfigMaps(namespace).Delete(context.TODO(), name, opts...)
+}
+
+func (c ConfigMapClient) Get(namespace string, name string) (core.ApiObject, error) {
+	return c.ClientSet.V1().ConfigMaps(namespace).Get(context.TODO(), name)
+}
+
+// ListRevisions 列举配置字典的所有修订版本
+func (c ConfigMapClient) ListRevisions(namespace string, name string) (core.ApiObjectList, error) {
+	revisions, err := c.ClientSet.V1().ConfigMaps(namespace).ListRevisions(context.TODO(), name)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	result := core.ApiObjectList{}
+	for i := range revisions {
+		result = append(result, &revisions[i])
+	}
+	return result, nil
+}
+
+func (c ConfigMapClient) DiffRevisions(namespace string, name string, revision int, target int) (*core.RevisionDiff, error) {
+	return c.ClientSet.V1().ConfigMaps(namespace).DiffRevisions(context.TODO(), name, revision, target)
+}
+
+func (c ConfigMapClient) RevertRevision(namespace string, name string, revision int) (core.ApiObject, error) {
+	result, err := c.ClientSet.V1().ConfigMaps(namespace).RevertRevision(context.TODO(), name, revision)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	fmt.Printf("%s rolled back to revision %d\n", result.GetKey(), revision)
+	return result, nil
 }
 
 func printConfigMaps(configMaps []v1.ConfigMap, format string) error {
@@ -0,0 +1,26 @@
+package wavectl
+
+import (
+	"context"
+	"fmt"
+)
+
+// RotateKeyOptions 加密密钥轮换配置项
+type RotateKeyOptions struct {
+	Endpoint string
+}
+
+// RotateKey 轮换服务端的加密密钥并重新加密所有数据
+func RotateKey(opts RotateKeyOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	keyID, err := clientSet.V1().RotateEncryptionKey(context.TODO())
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	fmt.Printf("rotated encryption key to %s\n", keyID)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	log "github.com/sirupsen/logrus"
@@ -19,7 +18,7 @@ type HostClient struct {
 	clientset.ClientSet
 }
 
-func (c HostClient) GetPrint(namespace string, name string, format string) error {
+func (c HostClient) GetPrint(namespace string, name string, format string, labelSelector string, fieldSelector string) error {
 	if name != "" {
 		host, err := c.ClientSet.V2().Hosts().Get(context.TODO(), name)
 		if err != nil {
@@ -29,7 +28,7 @@ func (c HostClient) GetPrint(namespace string, name string, format string) error
 		return printHosts([]v2.Host{*host}, format)
 	}
 
-	hosts, err := c.ClientSet.V2().Hosts().List(context.TODO())
+	hosts, err := c.ClientSet.V2().Hosts().ListBySelector(context.TODO(), labelSelector, fieldSelector)
 	if err != nil {
 		log.Error(err)
 		return err
@@ -37,22 +36,27 @@ func (c HostClient) GetPrint(namespace string, name string, format string) error
 	return printHosts(hosts, format)
 }
 
-func (c HostClient) Apply(obj core.ApiObject) (core.ApiObject, error) {
-	getObj, err := c.ClientSet.V2().Hosts().Get(context.TODO(), obj.GetMetadata().Name)
-	if err != nil && !strings.Contains(err.Error(), "not found") {
+// Apply 由服务端与上次应用的配置进行三方合并，主机不存在时创建
+func (c HostClient) Apply(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
+	// 转换成最新v2版本结构
+	obj, err := orm.Convert(obj, core.GVK{Group: core.Group, ApiVersion: v2.ApiVersion, Kind: core.KindHost})
+	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	if getObj == nil {
-		// 创建主机
-		return c.Create(obj)
+	host := obj.(*v2.Host)
+
+	result, err := c.ClientSet.V2().Hosts().Apply(context.TODO(), host, opts...)
+	if err != nil {
+		log.Error(err)
+		return nil, err
 	}
+	fmt.Printf("%s configured%s\n", result.GetKey(), dryRunSuffix(opts...))
 
-	// 更新主机
-	return c.Update(obj)
+	return result, nil
 }
 
-func (c HostClient) Create(obj core.ApiObject) (core.ApiObject, error) {
+func (c HostClient) Create(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	// 转换成最新v2版本结构
 	obj, err := orm.Convert(obj, core.GVK{Group: core.Group, ApiVersion: v2.ApiVersion, Kind: core.KindHost})
 	if err != nil {
@@ -61,17 +65,17 @@ func (c HostClient) Create(obj core.ApiObject) (core.ApiObject, error) {
 	}
 	host := obj.(*v2.Host)
 
-	result, err := c.ClientSet.V2().Hosts().Create(context.TODO(), host)
+	result, err := c.ClientSet.V2().Hosts().Create(context.TODO(), host, opts...)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	fmt.Printf("%s created\n", result.GetKey())
+	fmt.Printf("%s created%s\n", result.GetKey(), dryRunSuffix(opts...))
 
 	return result, nil
 }
 
-func (c HostClient) Update(obj core.ApiObject) (core.ApiObject, error) {
+func (c HostClient) Update(obj core.ApiObject, opts ...core.OpOpt) (core.ApiObject, error) {
 	// 转换成最新v2版本结构
 	obj, err := orm.Convert(obj, core.GVK{Group: core.Group, ApiVersion: v2.ApiVersion, Kind: core.KindHost})
 	if err != nil {
@@ -80,18 +84,50 @@ func (c HostClient) Update(obj core.ApiObject) (core.ApiObject, error) {
 	}
 	host := obj.(*v2.Host)
 
-	result, err := c.ClientSet.V2().Hosts().Update(context.TODO(), host)
+	result, err := c.ClientSet.V2().Hosts().Update(context.TODO(), host, opts...)
 	if err != nil {
 		log.Error(err)
 		return nil, err
 	}
-	fmt.Printf("%s updated\n", result.GetKey())
+	fmt.Printf("%s updated%s\n", result.GetKey(), dryRunSuffix(opts...))
+
+	return result, nil
+}
+
+func (c HostClient) Delete(namespace string, name string, opts ...core.OpOpt) (core.ApiObject, error) {
+	return c.ClientSet.V2().Hosts().Delete(context.TODO(), name, opts...)
+}
 
+func (c HostClient) Get(namespace string, name string) (core.ApiObject, error) {
+	return c.ClientSet.V2().Hosts().Get(context.TODO(), name)
+}
+
+// ListRevisions 列举主机的所有修订版本
+func (c HostClient) ListRevisions(namespace string, name string) (core.ApiObjectList, error) {
+	revisions, err := c.ClientSet.V2().Hosts().ListRevisions(context.TODO(), name)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	result := core.ApiObjectList{}
+	for i := range revisions {
+		result = append(result, &revisions[i])
+	}
 	return result, nil
 }
 
-func (c HostClient) Delete(namespace string, name string) (core.ApiObject, error) {
-	return c.ClientSet.V2().Hosts().Delete(context.TODO(), name)
+func (c HostClient) DiffRevisions(namespace string, name string, revision int, target int) (*core.RevisionDiff, error) {
+	return c.ClientSet.V2().Hosts().DiffRevisions(context.TODO(), name, revision, target)
+}
+
+func (c HostClient) RevertRevision(namespace string, name string, revision int) (core.ApiObject, error) {
+	result, err := c.ClientSet.V2().Hosts().RevertRevision(context.TODO(), name, revision)
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+	fmt.Printf("%s rolled back to revision %d\n", result.GetKey(), revision)
+	return result, nil
 }
 
 func printHosts(hosts []v2.Host, format string) error {
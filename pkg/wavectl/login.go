@@ -0,0 +1,200 @@
+package wavectl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	clientset "github.com/wujie1993/waves/pkg/client"
+)
+
+const (
+	// 指定访问令牌的环境变量
+	TokenEnv = "WAVES_TOKEN"
+
+	// 保存登录令牌的文件，位于用户主目录下，按接口地址分别保存
+	credentialsFile = ".wavectl/credentials"
+)
+
+// token 通过--token参数指定的访问令牌，优先级高于环境变量与登录保存的令牌
+var token string
+
+// SetToken 设置访问令牌
+func SetToken(t string) {
+	token = t
+}
+
+// LoginOptions 登录配置项
+type LoginOptions struct {
+	Endpoint string
+	Username string
+	// 登录密码，为空时从终端读取
+	Password string
+}
+
+// LogoutOptions 登出配置项
+type LogoutOptions struct {
+	Endpoint string
+}
+
+// Login 登录并保存令牌，之后的命令会自动携带该令牌，令牌过期时使用刷新令牌自动续期
+func Login(opts LoginOptions) {
+	defer exit()
+
+	password := opts.Password
+	if password == "" {
+		var err error
+		if password, err = readPassword(); err != nil {
+			fmt.Println(err)
+			exitCode++
+			return
+		}
+	}
+
+	tokens, err := clientset.NewClientSet(opts.Endpoint).V1().Login(context.TODO(), opts.Username, password)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	if err := saveCredential(opts.Endpoint, tokens); err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	fmt.Printf("logged in as %s\n", opts.Username)
+}
+
+// Logout 使服务端已签发的令牌失效并删除本地保存的令牌
+func Logout(opts LogoutOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	if err := clientSet.V1().Logout(context.TODO()); err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	if err := saveCredential(opts.Endpoint, nil); err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	fmt.Println("logged out")
+}
+
+// resolveToken 按--token参数、环境变量、登录保存的令牌的顺序获取访问令牌
+func resolveToken(endpoint string) string {
+	if token != "" {
+		return token
+	}
+	if envToken := os.Getenv(TokenEnv); envToken != "" {
+		return envToken
+	}
+
+	credentials, err := loadCredentials()
+	if err != nil {
+		log.Debug(err)
+		return ""
+	}
+	tokens, ok := credentials[endpoint]
+	if !ok {
+		return ""
+	}
+	// 访问令牌即将过期时使用刷新令牌续期
+	if time.Now().Add(time.Minute).Before(tokens.AccessTokenExpiresAt) {
+		return tokens.AccessToken
+	}
+	if time.Now().After(tokens.RefreshTokenExpiresAt) {
+		return ""
+	}
+	refreshed, err := clientset.NewClientSet(endpoint).V1().Refresh(context.TODO(), tokens.RefreshToken)
+	if err != nil {
+		log.Debug(err)
+		return ""
+	}
+	if err := saveCredential(endpoint, refreshed); err != nil {
+		log.Debug(err)
+	}
+	return refreshed.AccessToken
+}
+
+func credentialsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, credentialsFile), nil
+}
+
+func loadCredentials() (map[string]auth.TokenPair, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	credentials := make(map[string]auth.TokenPair)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return credentials, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, err
+	}
+	return credentials, nil
+}
+
+// saveCredential 保存指定接口地址的令牌，tokens为空时删除
+func saveCredential(endpoint string, tokens *auth.TokenPair) error {
+	credentials, err := loadCredentials()
+	if err != nil {
+		return err
+	}
+	if tokens == nil {
+		delete(credentials, endpoint)
+	} else {
+		credentials[endpoint] = *tokens
+	}
+
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(credentials, "", "\t")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// readPassword 从终端读取密码，标准输入不是终端时读取一行
+func readPassword() (string, error) {
+	fd := int(syscall.Stdin)
+	if terminal.IsTerminal(fd) {
+		fmt.Print("Password: ")
+		password, err := terminal.ReadPassword(fd)
+		fmt.Println()
+		return string(password), err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
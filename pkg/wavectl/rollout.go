@@ -0,0 +1,168 @@
+package wavectl
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+// RevisionManager 资源修订版本管理接口
+type RevisionManager interface {
+	Get(namespace string, name string) (core.ApiObject, error)
+	ListRevisions(namespace string, name string) (core.ApiObjectList, error)
+	DiffRevisions(namespace string, name string, revision int, target int) (*core.RevisionDiff, error)
+	RevertRevision(namespace string, name string, revision int) (core.ApiObject, error)
+}
+
+func getRevisionClient(kind string) RevisionManager {
+	if cli, ok := getClient(kind).(RevisionManager); ok {
+		return cli
+	}
+	return nil
+}
+
+// RolloutOptions 资源修订版本操作配置项
+type RolloutOptions struct {
+	Endpoint     string
+	Namespace    string
+	Resource     string
+	ResourceName string
+	// 比较或回滚的修订版本号，为0时使用最新的修订版本
+	Revision int
+	// 比较的目标修订版本号，为0时与资源的当前内容比较
+	ToRevision int
+}
+
+// RolloutHistory 列举资源的修订版本
+func RolloutHistory(opts RolloutOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	kind := core.SearchKind(opts.Resource)
+	cli := getRevisionClient(kind)
+	if cli == nil {
+		fmt.Printf("does not support rollout %s\n", kind)
+		exitCode++
+		return
+	}
+
+	revisions, err := cli.ListRevisions(opts.Namespace, opts.ResourceName)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetBorder(false)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"revision", "spec hash", "last updates"})
+	for _, revision := range revisions {
+		metadata := revision.GetMetadata()
+		table.Append([]string{
+			fmt.Sprint(metadata.ResourceVersion),
+			revision.SpecHash(),
+			metadata.UpdateTime.Format("2006/1/2 15:04:05"),
+		})
+	}
+	table.Render()
+}
+
+// RolloutDiff 比较资源的两个修订版本，默认比较最新的修订版本与资源的当前内容
+func RolloutDiff(opts RolloutOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	kind := core.SearchKind(opts.Resource)
+	cli := getRevisionClient(kind)
+	if cli == nil {
+		fmt.Printf("does not support rollout %s\n", kind)
+		exitCode++
+		return
+	}
+
+	revision := opts.Revision
+	if revision == 0 {
+		last, err := lastRevision(cli, opts.Namespace, opts.ResourceName)
+		if err != nil {
+			fmt.Println(err)
+			exitCode++
+			return
+		}
+		revision = last
+	}
+
+	target := opts.ToRevision
+	if target == 0 {
+		obj, err := cli.Get(opts.Namespace, opts.ResourceName)
+		if err != nil {
+			fmt.Println(err)
+			exitCode++
+			return
+		}
+		target = obj.GetMetadata().ResourceVersion
+	}
+
+	diff, err := cli.DiffRevisions(opts.Namespace, opts.ResourceName, revision, target)
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	if diff.Diff == "" {
+		fmt.Printf("no differences between revision %d and %d\n", diff.From, diff.To)
+		return
+	}
+	fmt.Print(diff.Diff)
+}
+
+// RolloutUndo 将资源回滚到指定的修订版本，默认回滚到最新的修订版本
+func RolloutUndo(opts RolloutOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	kind := core.SearchKind(opts.Resource)
+	cli := getRevisionClient(kind)
+	if cli == nil {
+		fmt.Printf("does not support rollout %s\n", kind)
+		exitCode++
+		return
+	}
+
+	revision := opts.Revision
+	if revision == 0 {
+		last, err := lastRevision(cli, opts.Namespace, opts.ResourceName)
+		if err != nil {
+			fmt.Println(err)
+			exitCode++
+			return
+		}
+		revision = last
+	}
+
+	if _, err := cli.RevertRevision(opts.Namespace, opts.ResourceName, revision); err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+}
+
+// lastRevision 获取资源最新的修订版本号
+func lastRevision(cli RevisionManager, namespace string, name string) (int, error) {
+	revisions, err := cli.ListRevisions(namespace, name)
+	if err != nil {
+		return 0, err
+	}
+	if len(revisions) == 0 {
+		return 0, e.Errorf("no revision found for %s", name)
+	}
+	// 修订版本按版本号从新到旧排序
+	return revisions[0].GetMetadata().ResourceVersion, nil
+}
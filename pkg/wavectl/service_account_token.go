@@ -0,0 +1,56 @@
+package wavectl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wujie1993/waves/pkg/auth"
+)
+
+// CreateTokenOptions 创建服务账号令牌配置项
+type CreateTokenOptions struct {
+	Endpoint       string
+	ServiceAccount string
+	Name           string
+	// 有效时长，如720h，为空时永不过期
+	ExpiresIn string
+}
+
+// RevokeTokenOptions 吊销服务账号令牌配置项
+type RevokeTokenOptions struct {
+	Endpoint       string
+	ServiceAccount string
+	Name           string
+}
+
+// CreateToken 为服务账号创建令牌并输出令牌明文，令牌明文只在创建时返回一次
+func CreateToken(opts CreateTokenOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	token, err := clientSet.V1().CreateServiceAccountToken(context.TODO(), opts.ServiceAccount, auth.ServiceAccountTokenRequest{
+		Name:      opts.Name,
+		ExpiresIn: opts.ExpiresIn,
+	})
+	if err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	fmt.Println(token.Token)
+}
+
+// RevokeToken 吊销服务账号的令牌
+func RevokeToken(opts RevokeTokenOptions) {
+	defer exit()
+
+	initClient(opts.Endpoint)
+
+	if err := clientSet.V1().RevokeServiceAccountToken(context.TODO(), opts.ServiceAccount, opts.Name); err != nil {
+		fmt.Println(err)
+		exitCode++
+		return
+	}
+	fmt.Printf("token %s of service account %s revoked\n", opts.Name, opts.ServiceAccount)
+}
@@ -0,0 +1,52 @@
+package extv1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/rbac"
+)
+
+type AccessReviewController struct {
+	controller.BaseController
+}
+
+// @summary 检查当前用户的操作权限
+// @description 批量检查当前用户是否被授权执行操作，用于前端隐藏无权限的操作入口。未启用认证时总是允许
+// @tags Auth
+// @produce json
+// @accept json
+// @param body body []rbac.AccessReview true "待检查的操作，Verb可选值为get、list、create、update、delete与action"
+// @success 200 {object} controller.Response{Data=[]rbac.AccessReview}
+// @failure 400 {object} controller.Response
+// @failure 500 {object} controller.Response
+// @router /api/v1/accessreviews [post]
+func (c *AccessReviewController) PostAccessReviews(ctx *gin.Context) {
+	var reviews []rbac.AccessReview
+	if err := ctx.ShouldBindJSON(&reviews); err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+
+	for i, review := range reviews {
+		kind := review.Kind
+		if searched := core.SearchKind(kind); searched != "" {
+			kind = searched
+		}
+		allowed, err := c.CheckAccess(ctx, review.Verb, kind, review.Namespace, review.Name)
+		if err != nil {
+			c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+			return
+		}
+		reviews[i].Allowed = allowed
+	}
+	c.Response(ctx, 200, e.SUCCESS, "", reviews)
+}
+
+func NewAccessReviewController() AccessReviewController {
+	return AccessReviewController{
+		BaseController: controller.NewController(nil),
+	}
+}
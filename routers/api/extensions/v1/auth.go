@@ -0,0 +1,92 @@
+package extv1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/e"
+)
+
+type AuthController struct {
+	controller.BaseController
+}
+
+// @summary 登录
+// @description 校验用户名与密码，签发访问令牌与刷新令牌，访问令牌通过请求头 Authorization: Bearer <访问令牌> 携带
+// @tags Auth
+// @produce json
+// @accept json
+// @param body body auth.LoginRequest true "用户名与密码"
+// @success 200 {object} controller.Response{Data=auth.TokenPair}
+// @failure 400 {object} controller.Response
+// @failure 401 {object} controller.Response
+// @router /api/v1/login [post]
+func (c *AuthController) PostLogin(ctx *gin.Context) {
+	var req auth.LoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	tokens, err := auth.Login(req.Username, req.Password)
+	if err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	c.Response(ctx, 200, e.SUCCESS, "", tokens)
+}
+
+// @summary 刷新令牌
+// @description 使用刷新令牌换取新的访问令牌与刷新令牌
+// @tags Auth
+// @produce json
+// @accept json
+// @param body body auth.RefreshRequest true "刷新令牌"
+// @success 200 {object} controller.Response{Data=auth.TokenPair}
+// @failure 400 {object} controller.Response
+// @failure 401 {object} controller.Response
+// @router /api/v1/refresh [post]
+func (c *AuthController) PostRefresh(ctx *gin.Context) {
+	var req auth.RefreshRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	tokens, err := auth.Refresh(req.RefreshToken)
+	if err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	c.Response(ctx, 200, e.SUCCESS, "", tokens)
+}
+
+// @summary 登出
+// @description 使当前用户已签发的所有访问令牌与刷新令牌失效
+// @tags Auth
+// @produce json
+// @success 200 {object} controller.Response
+// @failure 400 {object} controller.Response
+// @failure 401 {object} controller.Response
+// @router /api/v1/logout [post]
+func (c *AuthController) PostLogout(ctx *gin.Context) {
+	username := ctx.GetString(auth.ContextKeyUsername)
+	if username == "" {
+		c.Response(ctx, 400, e.INVALID_PARAMS, "auth is not enabled", nil)
+		return
+	}
+	if _, ok := auth.ParseServiceAccountUsername(username); ok {
+		c.Response(ctx, 400, e.INVALID_PARAMS, "service account tokens should be revoked instead of logging out", nil)
+		return
+	}
+	if err := auth.Logout(username); err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	c.Response(ctx, 200, e.SUCCESS, "", nil)
+}
+
+func NewAuthController() AuthController {
+	return AuthController{
+		BaseController: controller.NewController(nil),
+	}
+}
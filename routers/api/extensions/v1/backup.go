@@ -0,0 +1,70 @@
+package extv1
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/orm/registry"
+	"github.com/wujie1993/waves/pkg/rbac"
+)
+
+type BackupController struct {
+	controller.BaseController
+}
+
+// @summary 导出全量数据备份
+// @tags Admin
+// @produce application/octet-stream
+// @success 200 {file} file "gzip压缩的备份归档"
+// @failure 500 {object} controller.Response
+// @router /api/v1/admin/backup [get]
+func (c *BackupController) GetBackup(ctx *gin.Context) {
+	if !c.Authorize(ctx, core.VerbGet, rbac.KindAdmin, "", "backup") {
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := registry.Backup(buf); err != nil {
+		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		return
+	}
+
+	filename := fmt.Sprintf("waves-backup-%s.json.gz", time.Now().Format("20060102150405"))
+	ctx.Header("Content-Disposition", "attachment; filename="+filename)
+	ctx.Data(200, "application/octet-stream", buf.Bytes())
+}
+
+// @summary 从备份中还原数据
+// @tags Admin
+// @produce json
+// @accept application/octet-stream
+// @param body body string true "gzip压缩的备份归档"
+// @success 200 {object} controller.Response
+// @failure 400 {object} controller.Response
+// @failure 500 {object} controller.Response
+// @router /api/v1/admin/restore [post]
+func (c *BackupController) PostRestore(ctx *gin.Context) {
+	if !c.Authorize(ctx, core.VerbUpdate, rbac.KindAdmin, "", "restore") {
+		return
+	}
+
+	// 归档无效时返回400，写入存储失败时返回500，错误信息中说明只还原了部分数据
+	archive, err := registry.Restore(ctx.Request.Body)
+	if err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	c.Response(ctx, 200, e.SUCCESS, "", fmt.Sprintf("restored %d items", len(archive.Items)))
+}
+
+func NewBackupController() BackupController {
+	return BackupController{
+		BaseController: controller.NewController(nil),
+	}
+}
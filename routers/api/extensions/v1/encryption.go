@@ -0,0 +1,41 @@
+package extv1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/encryption"
+	"github.com/wujie1993/waves/pkg/orm/core"
+	"github.com/wujie1993/waves/pkg/rbac"
+)
+
+type EncryptionController struct {
+	controller.BaseController
+}
+
+// @summary 轮换加密密钥
+// @description 生成新的主密钥并使用新密钥重新加密所有资源对象中的敏感字段，旧密钥保留在密钥文件中用于解密
+// @tags Admin
+// @produce json
+// @success 200 {object} controller.Response{Data=string}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admin/encryption/rotate [post]
+func (c *EncryptionController) PostRotate(ctx *gin.Context) {
+	if !c.Authorize(ctx, core.VerbUpdate, rbac.KindAdmin, "", "encryption") {
+		return
+	}
+
+	keyID, err := encryption.RotateKey()
+	if err != nil {
+		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
+		return
+	}
+	c.Response(ctx, 200, e.SUCCESS, "", keyID)
+}
+
+func NewEncryptionController() EncryptionController {
+	return EncryptionController{
+		BaseController: controller.NewController(nil),
+	}
+}
@@ -0,0 +1,73 @@
+package extv1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/auth"
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/e"
+	"github.com/wujie1993/waves/pkg/orm/core"
+)
+
+type ServiceAccountTokenController struct {
+	controller.BaseController
+}
+
+// @summary 创建服务账号令牌
+// @description 为服务账号创建长期有效的API令牌，令牌明文只在创建时返回一次，服务端仅存储其哈希值。令牌通过请求头Authorization: Bearer <令牌>使用
+// @tags ServiceAccount
+// @produce json
+// @accept json
+// @param name path string true "服务账号名称"
+// @param body body auth.ServiceAccountTokenRequest true "令牌内容"
+// @success 201 {object} controller.Response{Data=auth.ServiceAccountTokenResponse}
+// @failure 400 {object} controller.Response
+// @failure 404 {object} controller.Response
+// @failure 500 {object} controller.Response
+// @router /api/v1/serviceaccounts/{name}/tokens [post]
+func (c *ServiceAccountTokenController) PostServiceAccountToken(ctx *gin.Context) {
+	name := ctx.Param("name")
+	if !c.Authorize(ctx, core.VerbUpdate, core.KindServiceAccount, "", name) {
+		return
+	}
+
+	var req auth.ServiceAccountTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		c.Response(ctx, 400, e.INVALID_PARAMS, err.Error(), nil)
+		return
+	}
+	token, err := auth.CreateServiceAccountToken(name, req)
+	if err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	c.Response(ctx, 201, e.SUCCESS, "", token)
+}
+
+// @summary 吊销服务账号令牌
+// @tags ServiceAccount
+// @produce json
+// @param name path string true "服务账号名称"
+// @param token path string true "令牌名称"
+// @success 200 {object} controller.Response
+// @failure 404 {object} controller.Response
+// @failure 500 {object} controller.Response
+// @router /api/v1/serviceaccounts/{name}/tokens/{token} [delete]
+func (c *ServiceAccountTokenController) DeleteServiceAccountToken(ctx *gin.Context) {
+	name := ctx.Param("name")
+	if !c.Authorize(ctx, core.VerbUpdate, core.KindServiceAccount, "", name) {
+		return
+	}
+
+	if err := auth.RevokeServiceAccountToken(name, ctx.Param("token")); err != nil {
+		c.ResponseError(ctx, err)
+		return
+	}
+	c.Response(ctx, 200, e.SUCCESS, "", nil)
+}
+
+func NewServiceAccountTokenController() ServiceAccountTokenController {
+	return ServiceAccountTokenController{
+		BaseController: controller.NewController(nil),
+	}
+}
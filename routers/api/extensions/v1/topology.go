@@ -45,6 +45,13 @@ func (c *TopologyController) GetTopology(ctx *gin.Context) {
 	download := ctx.Query("download")
 
 	log.Debug(resourceKind, resourceNamespace, resourceName, format, download)
+	namespace := resourceNamespace
+	if resourceKind != core.KindAppInstance {
+		namespace = ""
+	}
+	if !c.Authorize(ctx, core.VerbGet, resourceKind, namespace, resourceName) {
+		return
+	}
 	result, err := getTopology(resourceKind, resourceNamespace, resourceName, format)
 	if err != nil {
 		c.Response(ctx, 500, e.ERROR, err.Error(), nil)
@@ -0,0 +1,198 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/wujie1993/waves/pkg/controller"
+	"github.com/wujie1993/waves/pkg/orm/v1"
+)
+
+type AdmissionConfigController struct {
+	controller.BaseController
+}
+
+// @summary 获取所有准入控制配置
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param limit query integer false "每页的最大记录数，为0时不分页"
+// @param continue query string false "上一页返回的分页令牌"
+// @param labelSelector query string false "标签选择器 eg. app=nginx,tier in (web,db),!canary"
+// @param fieldSelector query string false "字段选择器 eg. status.phase=Running,spec.appRef.name=nginx"
+// @param sortBy query string false "排序字段 eg. metadata.createTime"
+// @param order query string false "排序方向 asc或desc，默认为asc"
+// @param q query string false "按名称或ShortName注解模糊搜索"
+// @param fields query string false "只返回指定的字段 eg. metadata.createTime,spec.replicas"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
+// @success 200 {object} controller.Response{Data=[]v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs [get]
+func (c *AdmissionConfigController) GetAdmissionConfigs(ctx *gin.Context) {
+	c.List(ctx)
+}
+
+// @summary 创建单个准入控制配置
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param body body v1.AdmissionConfig true "准入控制配置信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs [post]
+func (c *AdmissionConfigController) PostAdmissionConfig(ctx *gin.Context) {
+	c.Create(ctx)
+}
+
+// @summary 获取单个准入控制配置
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name} [get]
+func (c *AdmissionConfigController) GetAdmissionConfig(ctx *gin.Context) {
+	c.Get(ctx)
+}
+
+// @summary 更新单个准入控制配置
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param body body v1.AdmissionConfig true "准入控制配置信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name} [put]
+func (c *AdmissionConfigController) PutAdmissionConfig(ctx *gin.Context) {
+	c.Update(ctx)
+}
+
+// @summary 使用补丁修改单个准入控制配置
+// @tags AdmissionConfig
+// @produce json
+// @accept application/merge-patch+json,application/json-patch+json,application/apply-patch+json
+// @param name path string true "准入控制配置名称"
+// @param body body object true "补丁内容，类型由Content-Type指定，apply-patch为完整的资源配置"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name} [patch]
+func (c *AdmissionConfigController) PatchAdmissionConfig(ctx *gin.Context) {
+	c.Patch(ctx)
+}
+
+// @summary 删除单个准入控制配置
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param propagationPolicy query string false "依赖对象的级联删除策略，可选值为Foreground、Background与Orphan，默认为Background"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name} [delete]
+func (c *AdmissionConfigController) DeleteAdmissionConfig(ctx *gin.Context) {
+	c.Delete(ctx)
+}
+
+// @summary 获取单个准入控制配置的所有修订版本
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @success 200 {object} controller.Response{Data=[]v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name}/revisions [get]
+func (c *AdmissionConfigController) GetAdmissionConfigRevisions(ctx *gin.Context) {
+	c.ListRevisions(ctx)
+}
+
+// @summary 获取单个准入控制配置的指定修订版本
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name}/revisions/{revision} [get]
+func (c *AdmissionConfigController) GetAdmissionConfigRevision(ctx *gin.Context) {
+	c.GetRevision(ctx)
+}
+
+// @summary 更新单个准入控制配置到指定的修订版本
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name}/revisions/{revision} [put]
+func (c *AdmissionConfigController) PutAdmissionConfigRevision(ctx *gin.Context) {
+	c.PutRevision(ctx)
+}
+
+// @summary 删除单个准入控制配置的指定修订版本
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name}/revisions/{revision} [delete]
+func (c *AdmissionConfigController) DeleteAdmissionConfigRevision(ctx *gin.Context) {
+	c.DeleteRevision(ctx)
+}
+
+// @summary 比较单个准入控制配置的两个修订版本
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param revision path integer true "起始修订版本"
+// @param target path integer true "目标修订版本，为当前版本号时与当前内容比较"
+// @success 200 {object} controller.Response{Data=core.RevisionDiff}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name}/revisions/{revision}/diff/{target} [get]
+func (c *AdmissionConfigController) DiffAdmissionConfigRevision(ctx *gin.Context) {
+	c.DiffRevision(ctx)
+}
+
+// @summary 获取单个准入控制配置的状态
+// @tags AdmissionConfig
+// @produce json
+// @param name path string true "准入控制配置名称"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name}/status [get]
+func (c *AdmissionConfigController) GetAdmissionConfigStatus(ctx *gin.Context) {
+	c.GetStatus(ctx)
+}
+
+// @summary 更新单个准入控制配置的状态
+// @tags AdmissionConfig
+// @produce json
+// @accept json
+// @param name path string true "准入控制配置名称"
+// @param body body v1.AdmissionConfig true "准入控制配置信息，只有Status生效，指定了资源版本号时要求与当前版本号一致"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.AdmissionConfig}
+// @failure 500 {object} controller.Response
+// @router /api/v1/admissionconfigs/{name}/status [put]
+func (c *AdmissionConfigController) PutAdmissionConfigStatus(ctx *gin.Context) {
+	c.PutStatus(ctx)
+}
+
+func NewAdmissionConfigController() AdmissionConfigController {
+	return AdmissionConfigController{
+		BaseController: controller.NewController(v1.NewAdmissionConfigRegistry()),
+	}
+}
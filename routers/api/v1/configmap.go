@@ -16,6 +16,16 @@ type ConfigMapController struct {
 // @produce json
 // @accept json
 // @param namespace path string true "命名空间" default(default)
+// @param limit query integer false "每页的最大记录数，为0时不分页"
+// @param continue query string false "上一页返回的分页令牌"
+// @param labelSelector query string false "标签选择器 eg. app=nginx,tier in (web,db),!canary"
+// @param fieldSelector query string false "字段选择器 eg. status.phase=Running,spec.appRef.name=nginx"
+// @param sortBy query string false "排序字段 eg. metadata.createTime"
+// @param order query string false "排序方向 asc或desc，默认为asc"
+// @param q query string false "按名称或ShortName注解模糊搜索"
+// @param fields query string false "只返回指定的字段 eg. metadata.createTime,spec.replicas"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=[]v1.ConfigMap}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces/{namespace}/configmaps [get]
@@ -29,6 +39,8 @@ func (c *ConfigMapController) GetConfigMaps(ctx *gin.Context) {
 // @accept json
 // @param namespace path string true "命名空间" default(default)
 // @param name path string true "配置字典名称"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=v1.ConfigMap}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces/{namespace}/configmaps/{name} [get]
@@ -42,6 +54,7 @@ func (c *ConfigMapController) GetConfigMap(ctx *gin.Context) {
 // @accept json
 // @param namespace path string true "命名空间" default(default)
 // @param body body v1.ConfigMap true "配置字典信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.ConfigMap}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces/{namespace}/configmaps [post]
@@ -56,6 +69,7 @@ func (c *ConfigMapController) PostConfigMap(ctx *gin.Context) {
 // @param namespace path string true "命名空间" default(default)
 // @param name path string true "配置字典名称"
 // @param body body v1.ConfigMap true "配置字典信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.ConfigMap}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces/{namespace}/configmaps/{name} [put]
@@ -63,12 +77,29 @@ func (c *ConfigMapController) PutConfigMap(ctx *gin.Context) {
 	c.Update(ctx)
 }
 
+// @summary 使用补丁修改单个配置字典
+// @tags ConfigMap
+// @produce json
+// @accept application/merge-patch+json,application/json-patch+json,application/apply-patch+json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @param body body object true "补丁内容，类型由Content-Type指定，apply-patch为完整的资源配置"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.ConfigMap}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name} [patch]
+func (c *ConfigMapController) PatchConfigMap(ctx *gin.Context) {
+	c.Patch(ctx)
+}
+
 // @summary 删除单个配置字典
 // @tags ConfigMap
 // @produce json
 // @accept json
 // @param namespace path string true "命名空间" default(default)
 // @param name path string true "配置字典名称"
+// @param propagationPolicy query string false "依赖对象的级联删除策略，可选值为Foreground、Background与Orphan，默认为Background"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.ConfigMap}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces/{namespace}/configmaps/{name} [delete]
@@ -76,6 +107,103 @@ func (c *ConfigMapController) DeleteConfigMap(ctx *gin.Context) {
 	c.Delete(ctx)
 }
 
+// @summary 获取单个配置字典的所有修订版本
+// @tags ConfigMap
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @success 200 {object} controller.Response{Data=[]v1.ConfigMap}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name}/revisions [get]
+func (c *ConfigMapController) GetConfigMapRevisions(ctx *gin.Context) {
+	c.ListRevisions(ctx)
+}
+
+// @summary 获取单个配置字典的指定修订版本
+// @tags ConfigMap
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.ConfigMap}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name}/revisions/{revision} [get]
+func (c *ConfigMapController) GetConfigMapRevision(ctx *gin.Context) {
+	c.GetRevision(ctx)
+}
+
+// @summary 更新单个配置字典到指定的修订版本
+// @tags ConfigMap
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.ConfigMap}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name}/revisions/{revision} [put]
+func (c *ConfigMapController) PutConfigMapRevision(ctx *gin.Context) {
+	c.PutRevision(ctx)
+}
+
+// @summary 删除单个配置字典的指定修订版本
+// @tags ConfigMap
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.ConfigMap}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name}/revisions/{revision} [delete]
+func (c *ConfigMapController) DeleteConfigMapRevision(ctx *gin.Context) {
+	c.DeleteRevision(ctx)
+}
+
+// @summary 比较单个配置字典的两个修订版本
+// @tags ConfigMap
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @param revision path integer true "起始修订版本"
+// @param target path integer true "目标修订版本，为当前版本号时与当前内容比较"
+// @success 200 {object} controller.Response{Data=core.RevisionDiff}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name}/revisions/{revision}/diff/{target} [get]
+func (c *ConfigMapController) DiffConfigMapRevision(ctx *gin.Context) {
+	c.DiffRevision(ctx)
+}
+
+// @summary 获取单个配置字典的状态
+// @tags ConfigMap
+// @produce json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @success 200 {object} controller.Response{Data=v1.ConfigMap}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name}/status [get]
+func (c *ConfigMapController) GetConfigMapStatus(ctx *gin.Context) {
+	c.GetStatus(ctx)
+}
+
+// @summary 更新单个配置字典的状态
+// @tags ConfigMap
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "配置字典名称"
+// @param body body v1.ConfigMap true "配置字典信息，只有Status生效，指定了资源版本号时要求与当前版本号一致"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.ConfigMap}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{namespace}/configmaps/{name}/status [put]
+func (c *ConfigMapController) PutConfigMapStatus(ctx *gin.Context) {
+	c.PutStatus(ctx)
+}
+
 func NewConfigMapController() ConfigMapController {
 	return ConfigMapController{
 		BaseController: controller.NewController(v1.NewConfigMapRegistry()),
@@ -16,6 +16,16 @@ type GPUController struct {
 // @produce json
 // @accept json
 // @param category query string false "显卡分类" Enums(thirdParty,customize,hostPlugin,algorithmPlugin,algorithmInstance)
+// @param limit query integer false "每页的最大记录数，为0时不分页"
+// @param continue query string false "上一页返回的分页令牌"
+// @param labelSelector query string false "标签选择器 eg. app=nginx,tier in (web,db),!canary"
+// @param fieldSelector query string false "字段选择器 eg. status.phase=Running,spec.appRef.name=nginx"
+// @param sortBy query string false "排序字段 eg. metadata.createTime"
+// @param order query string false "排序方向 asc或desc，默认为asc"
+// @param q query string false "按名称或ShortName注解模糊搜索"
+// @param fields query string false "只返回指定的字段 eg. metadata.createTime,spec.replicas"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=[]v1.GPU}
 // @failure 500 {object} controller.Response
 // @router /api/v1/gpus [get]
@@ -28,6 +38,8 @@ func (c *GPUController) GetGPUs(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param name path string true "显卡名称"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=v1.GPU}
 // @failure 500 {object} controller.Response
 // @router /api/v1/gpus/{name} [get]
@@ -40,6 +52,7 @@ func (c *GPUController) GetGPU(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param body body v1.GPU true "显卡信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.GPU}
 // @failure 500 {object} controller.Response
 // @router /api/v1/gpus [post]
@@ -53,6 +66,7 @@ func (c *GPUController) PostGPU(ctx *gin.Context) {
 // @accept json
 // @param name path string true "显卡名称"
 // @param body body v1.GPU true "显卡信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.GPU}
 // @failure 500 {object} controller.Response
 // @router /api/v1/gpus/{name} [put]
@@ -60,11 +74,27 @@ func (c *GPUController) PutGPU(ctx *gin.Context) {
 	c.Update(ctx)
 }
 
+// @summary 使用补丁修改单个显卡
+// @tags GPU
+// @produce json
+// @accept application/merge-patch+json,application/json-patch+json,application/apply-patch+json
+// @param name path string true "显卡名称"
+// @param body body object true "补丁内容，类型由Content-Type指定，apply-patch为完整的资源配置"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.GPU}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name} [patch]
+func (c *GPUController) PatchGPU(ctx *gin.Context) {
+	c.Patch(ctx)
+}
+
 // @summary 删除单个显卡
 // @tags GPU
 // @produce json
 // @accept json
 // @param name path string true "显卡名称"
+// @param propagationPolicy query string false "依赖对象的级联删除策略，可选值为Foreground、Background与Orphan，默认为Background"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.GPU}
 // @failure 500 {object} controller.Response
 // @router /api/v1/gpus/{name} [delete]
@@ -72,6 +102,96 @@ func (c *GPUController) DeleteGPU(ctx *gin.Context) {
 	c.Delete(ctx)
 }
 
+// @summary 获取单个显卡的所有修订版本
+// @tags GPU
+// @produce json
+// @accept json
+// @param name path string true "显卡名称"
+// @success 200 {object} controller.Response{Data=[]v1.GPU}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name}/revisions [get]
+func (c *GPUController) GetGPURevisions(ctx *gin.Context) {
+	c.ListRevisions(ctx)
+}
+
+// @summary 获取单个显卡的指定修订版本
+// @tags GPU
+// @produce json
+// @accept json
+// @param name path string true "显卡名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.GPU}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name}/revisions/{revision} [get]
+func (c *GPUController) GetGPURevision(ctx *gin.Context) {
+	c.GetRevision(ctx)
+}
+
+// @summary 更新单个显卡到指定的修订版本
+// @tags GPU
+// @produce json
+// @accept json
+// @param name path string true "显卡名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.GPU}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name}/revisions/{revision} [put]
+func (c *GPUController) PutGPURevision(ctx *gin.Context) {
+	c.PutRevision(ctx)
+}
+
+// @summary 删除单个显卡的指定修订版本
+// @tags GPU
+// @produce json
+// @accept json
+// @param name path string true "显卡名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.GPU}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name}/revisions/{revision} [delete]
+func (c *GPUController) DeleteGPURevision(ctx *gin.Context) {
+	c.DeleteRevision(ctx)
+}
+
+// @summary 比较单个显卡的两个修订版本
+// @tags GPU
+// @produce json
+// @accept json
+// @param name path string true "显卡名称"
+// @param revision path integer true "起始修订版本"
+// @param target path integer true "目标修订版本，为当前版本号时与当前内容比较"
+// @success 200 {object} controller.Response{Data=core.RevisionDiff}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name}/revisions/{revision}/diff/{target} [get]
+func (c *GPUController) DiffGPURevision(ctx *gin.Context) {
+	c.DiffRevision(ctx)
+}
+
+// @summary 获取单个显卡的状态
+// @tags GPU
+// @produce json
+// @param name path string true "显卡名称"
+// @success 200 {object} controller.Response{Data=v1.GPU}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name}/status [get]
+func (c *GPUController) GetGPUStatus(ctx *gin.Context) {
+	c.GetStatus(ctx)
+}
+
+// @summary 更新单个显卡的状态
+// @tags GPU
+// @produce json
+// @accept json
+// @param name path string true "显卡名称"
+// @param body body v1.GPU true "显卡信息，只有Status生效，指定了资源版本号时要求与当前版本号一致"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.GPU}
+// @failure 500 {object} controller.Response
+// @router /api/v1/gpus/{name}/status [put]
+func (c *GPUController) PutGPUStatus(ctx *gin.Context) {
+	c.PutStatus(ctx)
+}
+
 func NewGPUController() GPUController {
 	return GPUController{
 		BaseController: controller.NewController(v1.NewGPURegistry()),
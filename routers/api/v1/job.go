@@ -11,6 +11,7 @@ import (
 	"github.com/wujie1993/waves/pkg/controller"
 	"github.com/wujie1993/waves/pkg/e"
 	"github.com/wujie1993/waves/pkg/orm"
+	"github.com/wujie1993/waves/pkg/orm/core"
 	"github.com/wujie1993/waves/pkg/orm/v1"
 	"github.com/wujie1993/waves/pkg/setting"
 )
@@ -23,6 +24,16 @@ type JobController struct {
 // @tags Job
 // @produce json
 // @accept json
+// @param limit query integer false "每页的最大记录数，为0时不分页"
+// @param continue query string false "上一页返回的分页令牌"
+// @param labelSelector query string false "标签选择器 eg. app=nginx,tier in (web,db),!canary"
+// @param fieldSelector query string false "字段选择器 eg. status.phase=Running,spec.appRef.name=nginx"
+// @param sortBy query string false "排序字段 eg. metadata.createTime"
+// @param order query string false "排序方向 asc或desc，默认为asc"
+// @param q query string false "按名称或ShortName注解模糊搜索"
+// @param fields query string false "只返回指定的字段 eg. metadata.createTime,spec.replicas"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=[]v1.Job}
 // @failure 500 {object} controller.Response
 // @router /api/v1/jobs [get]
@@ -35,6 +46,8 @@ func (c *JobController) GetJobs(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param name path string true "任务名称"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=v1.Job}
 // @failure 500 {object} controller.Response
 // @router /api/v1/jobs/{name} [get]
@@ -58,6 +71,10 @@ func (c *JobController) GetJobLog(ctx *gin.Context) {
 	download := ctx.Query("download")
 	jobDirs := path.Join(setting.AppSetting.DataDir, setting.JobsDir)
 
+	if !c.Authorize(ctx, core.VerbGet, core.KindJob, "", name) {
+		return
+	}
+
 	helper := orm.GetHelper()
 
 	if download == "true" {
@@ -123,6 +140,7 @@ func (c *JobController) GetJobLog(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param body body v1.Job true "任务信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.Job}
 // @failure 500 {object} controller.Response
 // @router /api/v1/jobs [post]
@@ -136,6 +154,7 @@ func (c *JobController) PostJob(ctx *gin.Context) {
 // @accept json
 // @param name path string true "任务名称"
 // @param body body v1.Job true "任务信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.Job}
 // @failure 500 {object} controller.Response
 // @router /api/v1/jobs/{name} [put]
@@ -143,11 +162,27 @@ func (c *JobController) PutJob(ctx *gin.Context) {
 	c.Update(ctx)
 }
 
+// @summary 使用补丁修改单个任务
+// @tags Job
+// @produce json
+// @accept application/merge-patch+json,application/json-patch+json,application/apply-patch+json
+// @param name path string true "任务名称"
+// @param body body object true "补丁内容，类型由Content-Type指定，apply-patch为完整的资源配置"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.Job}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name} [patch]
+func (c *JobController) PatchJob(ctx *gin.Context) {
+	c.Patch(ctx)
+}
+
 // @summary 删除单个任务
 // @tags Job
 // @produce json
 // @accept json
 // @param name path string true "任务名称"
+// @param propagationPolicy query string false "依赖对象的级联删除策略，可选值为Foreground、Background与Orphan，默认为Background"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.Job}
 // @failure 500 {object} controller.Response
 // @router /api/v1/jobs/{name} [delete]
@@ -155,6 +190,96 @@ func (c *JobController) DeleteJob(ctx *gin.Context) {
 	c.Delete(ctx)
 }
 
+// @summary 获取单个任务的所有修订版本
+// @tags Job
+// @produce json
+// @accept json
+// @param name path string true "任务名称"
+// @success 200 {object} controller.Response{Data=[]v1.Job}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name}/revisions [get]
+func (c *JobController) GetJobRevisions(ctx *gin.Context) {
+	c.ListRevisions(ctx)
+}
+
+// @summary 获取单个任务的指定修订版本
+// @tags Job
+// @produce json
+// @accept json
+// @param name path string true "任务名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.Job}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name}/revisions/{revision} [get]
+func (c *JobController) GetJobRevision(ctx *gin.Context) {
+	c.GetRevision(ctx)
+}
+
+// @summary 更新单个任务到指定的修订版本
+// @tags Job
+// @produce json
+// @accept json
+// @param name path string true "任务名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.Job}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name}/revisions/{revision} [put]
+func (c *JobController) PutJobRevision(ctx *gin.Context) {
+	c.PutRevision(ctx)
+}
+
+// @summary 删除单个任务的指定修订版本
+// @tags Job
+// @produce json
+// @accept json
+// @param name path string true "任务名称"
+// @param revision path integer true "修订版本"
+// @success 200 {object} controller.Response{Data=v1.Job}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name}/revisions/{revision} [delete]
+func (c *JobController) DeleteJobRevision(ctx *gin.Context) {
+	c.DeleteRevision(ctx)
+}
+
+// @summary 比较单个任务的两个修订版本
+// @tags Job
+// @produce json
+// @accept json
+// @param name path string true "任务名称"
+// @param revision path integer true "起始修订版本"
+// @param target path integer true "目标修订版本，为当前版本号时与当前内容比较"
+// @success 200 {object} controller.Response{Data=core.RevisionDiff}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name}/revisions/{revision}/diff/{target} [get]
+func (c *JobController) DiffJobRevision(ctx *gin.Context) {
+	c.DiffRevision(ctx)
+}
+
+// @summary 获取单个任务的状态
+// @tags Job
+// @produce json
+// @param name path string true "任务名称"
+// @success 200 {object} controller.Response{Data=v1.Job}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name}/status [get]
+func (c *JobController) GetJobStatus(ctx *gin.Context) {
+	c.GetStatus(ctx)
+}
+
+// @summary 更新单个任务的状态
+// @tags Job
+// @produce json
+// @accept json
+// @param name path string true "任务名称"
+// @param body body v1.Job true "任务信息，只有Status生效，指定了资源版本号时要求与当前版本号一致"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.Job}
+// @failure 500 {object} controller.Response
+// @router /api/v1/jobs/{name}/status [put]
+func (c *JobController) PutJobStatus(ctx *gin.Context) {
+	c.PutStatus(ctx)
+}
+
 func NewJobController() JobController {
 	return JobController{
 		BaseController: controller.NewController(v1.NewJobRegistry()),
@@ -15,6 +15,16 @@ type NamespaceController struct {
 // @tags Namespace
 // @produce json
 // @accept json
+// @param limit query integer false "每页的最大记录数，为0时不分页"
+// @param continue query string false "上一页返回的分页令牌"
+// @param labelSelector query string false "标签选择器 eg. app=nginx,tier in (web,db),!canary"
+// @param fieldSelector query string false "字段选择器 eg. status.phase=Running,spec.appRef.name=nginx"
+// @param sortBy query string false "排序字段 eg. metadata.createTime"
+// @param order query string false "排序方向 asc或desc，默认为asc"
+// @param q query string false "按名称或ShortName注解模糊搜索"
+// @param fields query string false "只返回指定的字段 eg. metadata.createTime,spec.replicas"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=[]v1.Namespace}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces [get]
@@ -27,6 +37,7 @@ func (c *NamespaceController) GetNamespaces(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param body body v1.Namespace true "命名空间信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.Namespace}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces [post]
@@ -39,6 +50,8 @@ func (c *NamespaceController) PostNamespace(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param name path string true "命名空间名称"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=v1.Namespace}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces/{name} [get]
@@ -61,11 +74,26 @@ func (c *NamespaceController) PutNamespace(ctx *gin.Context) {
 	c.Update(ctx)
 }
 
+// @summary 使用补丁修改单个命名空间
+// @tags Namespace
+// @produce json
+// @accept application/merge-patch+json,application/json-patch+json,application/apply-patch+json
+// @param name path string true "命名空间名称"
+// @param body body object true "补丁内容，类型由Content-Type指定，apply-patch为完整的资源配置"
+// @success 200 {object} controller.Response{Data=v1.Namespace}
+// @failure 500 {object} controller.Response
+// @router /api/v1/namespaces/{name} [patch]
+func (c *NamespaceController) PatchNamespace(ctx *gin.Context) {
+	ctx.Set("name", ctx.Param("namespace"))
+	c.Patch(ctx)
+}
+
 // @summary 删除单个命名空间
 // @tags Namespace
 // @produce json
 // @accept json
 // @param name path string true "命名空间名称"
+// @param propagationPolicy query string false "依赖对象的级联删除策略，可选值为Foreground、Background与Orphan，默认为Background"
 // @success 200 {object} controller.Response{Data=v1.Namespace}
 // @failure 500 {object} controller.Response
 // @router /api/v1/namespaces/{name} [delete]
@@ -18,6 +18,16 @@ type RevisionController struct {
 // @tags Revision
 // @produce json
 // @accept json
+// @param limit query integer false "每页的最大记录数，为0时不分页"
+// @param continue query string false "上一页返回的分页令牌"
+// @param labelSelector query string false "标签选择器 eg. app=nginx,tier in (web,db),!canary"
+// @param fieldSelector query string false "字段选择器 eg. status.phase=Running,spec.appRef.name=nginx"
+// @param sortBy query string false "排序字段 eg. metadata.createTime"
+// @param order query string false "排序方向 asc或desc，默认为asc"
+// @param q query string false "按名称或ShortName注解模糊搜索"
+// @param fields query string false "只返回指定的字段 eg. metadata.createTime,spec.replicas"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=[]v1.Revision}
 // @failure 500 {object} controller.Response
 // @router /api/v1/revisions [get]
@@ -30,6 +40,8 @@ func (c *RevisionController) GetRevisions(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param name path string true "修订历史名称"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=v1.Revision}
 // @failure 500 {object} controller.Response
 // @router /api/v1/revisions/{name} [get]
@@ -42,6 +54,7 @@ func (c *RevisionController) GetRevision(ctx *gin.Context) {
 // @produce json
 // @accept json
 // @param body body v1.Revision true "修订历史信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.Revision}
 // @failure 500 {object} controller.Response
 // @router /api/v1/revisions [post]
@@ -55,6 +68,7 @@ func (c *RevisionController) PostRevision(ctx *gin.Context) {
 // @accept json
 // @param name path string true "修订历史名称"
 // @param body body v1.Revision true "修订历史信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.Revision}
 // @failure 500 {object} controller.Response
 // @router /api/v1/revisions/{name} [put]
@@ -62,11 +76,27 @@ func (c *RevisionController) PutRevision(ctx *gin.Context) {
 	c.Update(ctx)
 }
 
+// @summary 使用补丁修改单个修订历史
+// @tags Revision
+// @produce json
+// @accept application/merge-patch+json,application/json-patch+json,application/apply-patch+json
+// @param name path string true "修订历史名称"
+// @param body body object true "补丁内容，类型由Content-Type指定，apply-patch为完整的资源配置"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v1.Revision}
+// @failure 500 {object} controller.Response
+// @router /api/v1/revisions/{name} [patch]
+func (c *RevisionController) PatchRevision(ctx *gin.Context) {
+	c.Patch(ctx)
+}
+
 // @summary 删除单个修订历史
 // @tags Revision
 // @produce json
 // @accept json
 // @param name path string true "修订历史名称"
+// @param propagationPolicy query string false "依赖对象的级联删除策略，可选值为Foreground、Background与Orphan，默认为Background"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v1.Revision}
 // @failure 500 {object} controller.Response
 // @router /api/v1/revisions/{name} [delete]
@@ -18,6 +18,16 @@ type AppInstanceController struct {
 // @accept json
 // @param namespace path string true "命名空间" default(default)
 // @param category query string false "应用分类" Enums(thirdParty,customize,hostPlugin,algorithmPlugin)
+// @param limit query integer false "每页的最大记录数，为0时不分页"
+// @param continue query string false "上一页返回的分页令牌"
+// @param labelSelector query string false "标签选择器 eg. app=nginx,tier in (web,db),!canary"
+// @param fieldSelector query string false "字段选择器 eg. status.phase=Running,spec.appRef.name=nginx"
+// @param sortBy query string false "排序字段 eg. metadata.createTime"
+// @param order query string false "排序方向 asc或desc，默认为asc"
+// @param q query string false "按名称或ShortName注解模糊搜索"
+// @param fields query string false "只返回指定的字段 eg. metadata.createTime,spec.replicas"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=[]v2.AppInstance}
 // @failure 500 {object} controller.Response
 // @router /api/v2/namespaces/{namespace}/appinstances [get]
@@ -31,6 +41,8 @@ func (c *AppInstanceController) GetAppInstances(ctx *gin.Context) {
 // @accept json
 // @param namespace path string true "命名空间" default(default)
 // @param name path string true "应用实例名称"
+// @param watch query boolean false "为true时持续推送资源对象的变更，请求头Accept为text/event-stream时以Server-Sent Events格式推送，否则每行推送一个JSON"
+// @param revision query integer false "侦听时从该存储修订版本之后开始推送变更，取值为列举响应或变更中的Revision，与资源对象的Metadata.ResourceVersion无关"
 // @success 200 {object} controller.Response{Data=v2.AppInstance}
 // @failure 500 {object} controller.Response
 // @router /api/v2/namespaces/{namespace}/appinstances/{name} [get]
@@ -44,6 +56,7 @@ func (c *AppInstanceController) GetAppInstance(ctx *gin.Context) {
 // @accept json
 // @param namespace path string true "命名空间" default(default)
 // @param body body v2.AppInstance true "应用实例信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v2.AppInstance}
 // @failure 500 {object} controller.Response
 // @router /api/v2/namespaces/{namespace}/appinstances [post]
@@ -58,6 +71,7 @@ func (c *AppInstanceController) PostAppInstance(ctx *gin.Context) {
 // @param namespace path string true "命名空间" default(default)
 // @param name path string true "应用实例名称"
 // @param body body v2.AppInstance true "应用实例信息"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v2.AppInstance}
 // @failure 500 {object} controller.Response
 // @router /api/v2/namespaces/{namespace}/appinstances/{name} [put]
@@ -65,12 +79,29 @@ func (c *AppInstanceController) PutAppInstance(ctx *gin.Context) {
 	c.Update(ctx)
 }
 
+// @summary 使用补丁修改单个应用实例
+// @tags AppInstance
+// @produce json
+// @accept application/merge-patch+json,application/json-patch+json,application/apply-patch+json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "应用实例名称"
+// @param body body object true "补丁内容，类型由Content-Type指定，apply-patch为完整的资源配置"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v2.AppInstance}
+// @failure 500 {object} controller.Response
+// @router /api/v2/namespaces/{namespace}/appinstances/{name} [patch]
+func (c *AppInstanceController) PatchAppInstance(ctx *gin.Context) {
+	c.Patch(ctx)
+}
+
 // @summary 删除单个应用实例
 // @tags AppInstance
 // @produce json
 // @accept json
 // @param namespace path string true "命名空间" default(default)
 // @param name path string true "应用实例名称"
+// @param propagationPolicy query string false "依赖对象的级联删除策略，可选值为Foreground、Background与Orphan，默认为Background"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
 // @success 200 {object} controller.Response{Data=v2.AppInstance}
 // @failure 500 {object} controller.Response
 // @router /api/v2/namespaces/{namespace}/appinstances/{name} [delete]
@@ -133,6 +164,48 @@ func (c *AppInstanceController) DeleteAppInstanceRevision(ctx *gin.Context) {
 	c.DeleteRevision(ctx)
 }
 
+// @summary 比较单个应用实例的两个修订版本
+// @tags AppInstance
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "应用实例名称"
+// @param revision path integer true "起始修订版本"
+// @param target path integer true "目标修订版本，为当前版本号时与当前内容比较"
+// @success 200 {object} controller.Response{Data=core.RevisionDiff}
+// @failure 500 {object} controller.Response
+// @router /api/v2/namespaces/{namespace}/appinstances/{name}/revisions/{revision}/diff/{target} [get]
+func (c *AppInstanceController) DiffAppInstanceRevision(ctx *gin.Context) {
+	c.DiffRevision(ctx)
+}
+
+// @summary 获取单个应用实例的状态
+// @tags AppInstance
+// @produce json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "应用实例名称"
+// @success 200 {object} controller.Response{Data=v2.AppInstance}
+// @failure 500 {object} controller.Response
+// @router /api/v2/namespaces/{namespace}/appinstances/{name}/status [get]
+func (c *AppInstanceController) GetAppInstanceStatus(ctx *gin.Context) {
+	c.GetStatus(ctx)
+}
+
+// @summary 更新单个应用实例的状态
+// @tags AppInstance
+// @produce json
+// @accept json
+// @param namespace path string true "命名空间" default(default)
+// @param name path string true "应用实例名称"
+// @param body body v2.AppInstance true "应用实例信息，只有Status生效，指定了资源版本号时要求与当前版本号一致"
+// @param dryRun query boolean false "为true时只返回将要写入的对象，不实际写入"
+// @success 200 {object} controller.Response{Data=v2.AppInstance}
+// @failure 500 {object} controller.Response
+// @router /api/v2/namespaces/{namespace}/appinstances/{name}/status [put]
+func (c *AppInstanceController) PutAppInstanceStatus(ctx *gin.Context) {
+	c.PutStatus(ctx)
+}
+
 // 实现了ListFilter的过滤方法
 func (c *AppInstanceController) listFilt(ctx *gin.Context, objs []core.ApiObject) []core.ApiObject {
 	result := []core.ApiObject{}
@@ -151,9 +224,7 @@ func (c *AppInstanceController) listFilt(ctx *gin.Context, objs []core.ApiObject
 }
 
 func NewAppInstanceController() AppInstanceController {
-	c := AppInstanceController{
+	return AppInstanceController{
 		BaseController: controller.NewController(v2.NewAppInstanceRegistry()),
 	}
-	c.SetRevisioner(v2.NewAppInstanceRevision())
-	return c
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 
 	_ "github.com/wujie1993/waves/docs"
+	"github.com/wujie1993/waves/pkg/middleware/jwt"
 	"github.com/wujie1993/waves/pkg/setting"
 	"github.com/wujie1993/waves/pkg/version"
 	extV1 "github.com/wujie1993/waves/routers/api/extensions/v1"
@@ -17,6 +18,8 @@ import (
 // InitRouter initialize routing information
 func InitRouter() *gin.Engine {
 	r := gin.New()
+	// 访问日志中不记录查询参数中的访问令牌
+	r.Use(jwt.StripQueryToken())
 	r.Use(gin.Logger())
 	r.Use(gin.Recovery())
 
@@ -27,8 +30,14 @@ func InitRouter() *gin.Engine {
 	apiRouter := baseRouter.Group("/api")
 	// 实体对象接口组
 	apiV1 := apiRouter.Group("/v1")
-	// apiV1.Use(jwt.JWT())
+	// 登录与刷新令牌接口无需认证，需在启用认证中间件前注册
+	authCtl := extV1.NewAuthController()
+	apiV1.POST("/login", authCtl.PostLogin)
+	apiV1.POST("/refresh", authCtl.PostRefresh)
+	apiV1.Use(jwt.JWT())
 	{
+		apiV1.POST("/logout", authCtl.PostLogout)
+
 		nsCtl := v1.NewNamespaceController()
 		apiV1.GET("/namespaces", nsCtl.GetNamespaces)
 		apiV1.POST("/namespaces", nsCtl.PostNamespace)
@@ -37,6 +46,7 @@ func InitRouter() *gin.Engine {
 		{
 			ns.GET("", nsCtl.GetNamespace)
 			ns.PUT("", nsCtl.PutNamespace)
+			ns.PATCH("", nsCtl.PatchNamespace)
 			ns.DELETE("", nsCtl.DeleteNamespace)
 
 			app := ns.Group("/apps")
@@ -46,7 +56,15 @@ func InitRouter() *gin.Engine {
 				app.POST("", c.PostApp)
 				app.GET(":name", c.GetApp)
 				app.PUT(":name", c.PutApp)
+				app.PATCH(":name", c.PatchApp)
 				app.DELETE(":name", c.DeleteApp)
+				app.GET(":name/revisions", c.GetAppRevisions)
+				app.GET(":name/revisions/:revision", c.GetAppRevision)
+				app.PUT(":name/revisions/:revision", c.PutAppRevision)
+				app.DELETE(":name/revisions/:revision", c.DeleteAppRevision)
+				app.GET(":name/revisions/:revision/diff/:target", c.DiffAppRevision)
+				app.GET(":name/status", c.GetAppStatus)
+				app.PUT(":name/status", c.PutAppStatus)
 			}
 
 			appInstance := ns.Group("/appinstances")
@@ -56,7 +74,15 @@ func InitRouter() *gin.Engine {
 				appInstance.POST("", c.PostAppInstance)
 				appInstance.GET(":name", c.GetAppInstance)
 				appInstance.PUT(":name", c.PutAppInstance)
+				appInstance.PATCH(":name", c.PatchAppInstance)
 				appInstance.DELETE(":name", c.DeleteAppInstance)
+				appInstance.GET(":name/revisions", c.GetAppInstanceRevisions)
+				appInstance.GET(":name/revisions/:revision", c.GetAppInstanceRevision)
+				appInstance.PUT(":name/revisions/:revision", c.PutAppInstanceRevision)
+				appInstance.DELETE(":name/revisions/:revision", c.DeleteAppInstanceRevision)
+				appInstance.GET(":name/revisions/:revision/diff/:target", c.DiffAppInstanceRevision)
+				appInstance.GET(":name/status", c.GetAppInstanceStatus)
+				appInstance.PUT(":name/status", c.PutAppInstanceStatus)
 			}
 
 			configMap := ns.Group("/configmaps")
@@ -66,7 +92,15 @@ func InitRouter() *gin.Engine {
 				configMap.POST("", c.PostConfigMap)
 				configMap.GET(":name", c.GetConfigMap)
 				configMap.PUT(":name", c.PutConfigMap)
+				configMap.PATCH(":name", c.PatchConfigMap)
 				configMap.DELETE(":name", c.DeleteConfigMap)
+				configMap.GET(":name/revisions", c.GetConfigMapRevisions)
+				configMap.GET(":name/revisions/:revision", c.GetConfigMapRevision)
+				configMap.PUT(":name/revisions/:revision", c.PutConfigMapRevision)
+				configMap.DELETE(":name/revisions/:revision", c.DeleteConfigMapRevision)
+				configMap.GET(":name/revisions/:revision/diff/:target", c.DiffConfigMapRevision)
+				configMap.GET(":name/status", c.GetConfigMapStatus)
+				configMap.PUT(":name/status", c.PutConfigMapStatus)
 			}
 
 			k8sconfig := ns.Group("/k8sconfig")
@@ -76,7 +110,15 @@ func InitRouter() *gin.Engine {
 				k8sconfig.POST("", c.PostK8ClusterConfig)
 				k8sconfig.GET(":name", c.GetK8ClusterConfig)
 				k8sconfig.PUT(":name", c.PutK8ClusterConfig)
+				k8sconfig.PATCH(":name", c.PatchK8ClusterConfig)
 				k8sconfig.DELETE(":name", c.DeleteK8sClusterConfig)
+				k8sconfig.GET(":name/revisions", c.GetK8sClusterConfigRevisions)
+				k8sconfig.GET(":name/revisions/:revision", c.GetK8sClusterConfigRevision)
+				k8sconfig.PUT(":name/revisions/:revision", c.PutK8sClusterConfigRevision)
+				k8sconfig.DELETE(":name/revisions/:revision", c.DeleteK8sClusterConfigRevision)
+				k8sconfig.GET(":name/revisions/:revision/diff/:target", c.DiffK8sClusterConfigRevision)
+				k8sconfig.GET(":name/status", c.GetK8sClusterConfigStatus)
+				k8sconfig.PUT(":name/status", c.PutK8sClusterConfigStatus)
 			}
 		}
 
@@ -87,7 +129,15 @@ func InitRouter() *gin.Engine {
 			job.POST("", c.PostJob)
 			job.GET(":name", c.GetJob)
 			job.PUT(":name", c.PutJob)
+			job.PATCH(":name", c.PatchJob)
 			job.DELETE(":name", c.DeleteJob)
+			job.GET(":name/revisions", c.GetJobRevisions)
+			job.GET(":name/revisions/:revision", c.GetJobRevision)
+			job.PUT(":name/revisions/:revision", c.PutJobRevision)
+			job.DELETE(":name/revisions/:revision", c.DeleteJobRevision)
+			job.GET(":name/revisions/:revision/diff/:target", c.DiffJobRevision)
+			job.GET(":name/status", c.GetJobStatus)
+			job.PUT(":name/status", c.PutJobStatus)
 			job.GET(":name/log", c.GetJobLog)
 		}
 
@@ -98,7 +148,15 @@ func InitRouter() *gin.Engine {
 			gpu.POST("", c.PostGPU)
 			gpu.GET(":name", c.GetGPU)
 			gpu.PUT(":name", c.PutGPU)
+			gpu.PATCH(":name", c.PatchGPU)
 			gpu.DELETE(":name", c.DeleteGPU)
+			gpu.GET(":name/revisions", c.GetGPURevisions)
+			gpu.GET(":name/revisions/:revision", c.GetGPURevision)
+			gpu.PUT(":name/revisions/:revision", c.PutGPURevision)
+			gpu.DELETE(":name/revisions/:revision", c.DeleteGPURevision)
+			gpu.GET(":name/revisions/:revision/diff/:target", c.DiffGPURevision)
+			gpu.GET(":name/status", c.GetGPUStatus)
+			gpu.PUT(":name/status", c.PutGPUStatus)
 		}
 
 		pkg := apiV1.Group("/pkgs")
@@ -108,7 +166,15 @@ func InitRouter() *gin.Engine {
 			pkg.POST("", c.PostPkg)
 			pkg.GET(":name", c.GetPkg)
 			pkg.PUT(":name", c.PutPkg)
+			pkg.PATCH(":name", c.PatchPkg)
 			pkg.DELETE(":name", c.DeletePkg)
+			pkg.GET(":name/revisions", c.GetPkgRevisions)
+			pkg.GET(":name/revisions/:revision", c.GetPkgRevision)
+			pkg.PUT(":name/revisions/:revision", c.PutPkgRevision)
+			pkg.DELETE(":name/revisions/:revision", c.DeletePkgRevision)
+			pkg.GET(":name/revisions/:revision/diff/:target", c.DiffPkgRevision)
+			pkg.GET(":name/status", c.GetPkgStatus)
+			pkg.PUT(":name/status", c.PutPkgStatus)
 		}
 
 		audit := apiV1.Group("/audits")
@@ -118,7 +184,15 @@ func InitRouter() *gin.Engine {
 			audit.POST("", c.PostAudit)
 			audit.GET(":name", c.GetAudit)
 			audit.PUT(":name", c.PutAudit)
+			audit.PATCH(":name", c.PatchAudit)
 			audit.DELETE(":name", c.DeleteAudit)
+			audit.GET(":name/revisions", c.GetAuditRevisions)
+			audit.GET(":name/revisions/:revision", c.GetAuditRevision)
+			audit.PUT(":name/revisions/:revision", c.PutAuditRevision)
+			audit.DELETE(":name/revisions/:revision", c.DeleteAuditRevision)
+			audit.GET(":name/revisions/:revision/diff/:target", c.DiffAuditRevision)
+			audit.GET(":name/status", c.GetAuditStatus)
+			audit.PUT(":name/status", c.PutAuditStatus)
 		}
 
 		event := apiV1.Group("/events")
@@ -128,7 +202,15 @@ func InitRouter() *gin.Engine {
 			event.POST("", c.PostEvent)
 			event.GET(":name", c.GetEvent)
 			event.PUT(":name", c.PutEvent)
+			event.PATCH(":name", c.PatchEvent)
 			event.DELETE(":name", c.DeleteEvent)
+			event.GET(":name/revisions", c.GetEventRevisions)
+			event.GET(":name/revisions/:revision", c.GetEventRevision)
+			event.PUT(":name/revisions/:revision", c.PutEventRevision)
+			event.DELETE(":name/revisions/:revision", c.DeleteEventRevision)
+			event.GET(":name/revisions/:revision/diff/:target", c.DiffEventRevision)
+			event.GET(":name/status", c.GetEventStatus)
+			event.PUT(":name/status", c.PutEventStatus)
 		}
 
 		host := apiV1.Group("/hosts")
@@ -138,9 +220,114 @@ func InitRouter() *gin.Engine {
 			host.POST("", c.PostHost)
 			host.GET(":name", c.GetHost)
 			host.PUT(":name", c.PutHost)
+			host.PATCH(":name", c.PatchHost)
 			host.DELETE(":name", c.DeleteHost)
+			host.GET(":name/revisions", c.GetHostRevisions)
+			host.GET(":name/revisions/:revision", c.GetHostRevision)
+			host.PUT(":name/revisions/:revision", c.PutHostRevision)
+			host.DELETE(":name/revisions/:revision", c.DeleteHostRevision)
+			host.GET(":name/revisions/:revision/diff/:target", c.DiffHostRevision)
+			host.GET(":name/status", c.GetHostStatus)
+			host.PUT(":name/status", c.PutHostStatus)
+		}
+
+		admissionConfig := apiV1.Group("/admissionconfigs")
+		{
+			c := v1.NewAdmissionConfigController()
+			admissionConfig.GET("", c.GetAdmissionConfigs)
+			admissionConfig.POST("", c.PostAdmissionConfig)
+			admissionConfig.GET(":name", c.GetAdmissionConfig)
+			admissionConfig.PUT(":name", c.PutAdmissionConfig)
+			admissionConfig.PATCH(":name", c.PatchAdmissionConfig)
+			admissionConfig.DELETE(":name", c.DeleteAdmissionConfig)
+			admissionConfig.GET(":name/revisions", c.GetAdmissionConfigRevisions)
+			admissionConfig.GET(":name/revisions/:revision", c.GetAdmissionConfigRevision)
+			admissionConfig.PUT(":name/revisions/:revision", c.PutAdmissionConfigRevision)
+			admissionConfig.DELETE(":name/revisions/:revision", c.DeleteAdmissionConfigRevision)
+			admissionConfig.GET(":name/revisions/:revision/diff/:target", c.DiffAdmissionConfigRevision)
+			admissionConfig.GET(":name/status", c.GetAdmissionConfigStatus)
+			admissionConfig.PUT(":name/status", c.PutAdmissionConfigStatus)
+		}
+
+		user := apiV1.Group("/users")
+		{
+			c := v1.NewUserController()
+			user.GET("", c.GetUsers)
+			user.POST("", c.PostUser)
+			user.GET(":name", c.GetUser)
+			user.PUT(":name", c.PutUser)
+			user.PATCH(":name", c.PatchUser)
+			user.DELETE(":name", c.DeleteUser)
+			user.GET(":name/revisions", c.GetUserRevisions)
+			user.GET(":name/revisions/:revision", c.GetUserRevision)
+			user.PUT(":name/revisions/:revision", c.PutUserRevision)
+			user.DELETE(":name/revisions/:revision", c.DeleteUserRevision)
+			user.GET(":name/revisions/:revision/diff/:target", c.DiffUserRevision)
+			user.GET(":name/status", c.GetUserStatus)
+			user.PUT(":name/status", c.PutUserStatus)
+		}
+
+		role := apiV1.Group("/roles")
+		{
+			c := v1.NewRoleController()
+			role.GET("", c.GetRoles)
+			role.POST("", c.PostRole)
+			role.GET(":name", c.GetRole)
+			role.PUT(":name", c.PutRole)
+			role.PATCH(":name", c.PatchRole)
+			role.DELETE(":name", c.DeleteRole)
+			role.GET(":name/revisions", c.GetRoleRevisions)
+			role.GET(":name/revisions/:revision", c.GetRoleRevision)
+			role.PUT(":name/revisions/:revision", c.PutRoleRevision)
+			role.DELETE(":name/revisions/:revision", c.DeleteRoleRevision)
+			role.GET(":name/revisions/:revision/diff/:target", c.DiffRoleRevision)
+			role.GET(":name/status", c.GetRoleStatus)
+			role.PUT(":name/status", c.PutRoleStatus)
+		}
+
+		roleBinding := apiV1.Group("/rolebindings")
+		{
+			c := v1.NewRoleBindingController()
+			roleBinding.GET("", c.GetRoleBindings)
+			roleBinding.POST("", c.PostRoleBinding)
+			roleBinding.GET(":name", c.GetRoleBinding)
+			roleBinding.PUT(":name", c.PutRoleBinding)
+			roleBinding.PATCH(":name", c.PatchRoleBinding)
+			roleBinding.DELETE(":name", c.DeleteRoleBinding)
+			roleBinding.GET(":name/revisions", c.GetRoleBindingRevisions)
+			roleBinding.GET(":name/revisions/:revision", c.GetRoleBindingRevision)
+			roleBinding.PUT(":name/revisions/:revision", c.PutRoleBindingRevision)
+			roleBinding.DELETE(":name/revisions/:revision", c.DeleteRoleBindingRevision)
+			roleBinding.GET(":name/revisions/:revision/diff/:target", c.DiffRoleBindingRevision)
+			roleBinding.GET(":name/status", c.GetRoleBindingStatus)
+			roleBinding.PUT(":name/status", c.PutRoleBindingStatus)
 		}
 
+		serviceAccount := apiV1.Group("/serviceaccounts")
+		{
+			c := v1.NewServiceAccountController()
+			serviceAccount.GET("", c.GetServiceAccounts)
+			serviceAccount.POST("", c.PostServiceAccount)
+			serviceAccount.GET(":name", c.GetServiceAccount)
+			serviceAccount.PUT(":name", c.PutServiceAccount)
+			serviceAccount.PATCH(":name", c.PatchServiceAccount)
+			serviceAccount.DELETE(":name", c.DeleteServiceAccount)
+			serviceAccount.GET(":name/revisions", c.GetServiceAccountRevisions)
+			serviceAccount.GET(":name/revisions/:revision", c.GetServiceAccountRevision)
+			serviceAccount.PUT(":name/revisions/:revision", c.PutServiceAccountRevision)
+			serviceAccount.DELETE(":name/revisions/:revision", c.DeleteServiceAccountRevision)
+			serviceAccount.GET(":name/revisions/:revision/diff/:target", c.DiffServiceAccountRevision)
+			serviceAccount.GET(":name/status", c.GetServiceAccountStatus)
+			serviceAccount.PUT(":name/status", c.PutServiceAccountStatus)
+
+			tokenCtl := extV1.NewServiceAccountTokenController()
+			serviceAccount.POST(":name/tokens", tokenCtl.PostServiceAccountToken)
+			serviceAccount.DELETE(":name/tokens/:token", tokenCtl.DeleteServiceAccountToken)
+		}
+
+		accessReviewCtl := extV1.NewAccessReviewController()
+		apiV1.POST("/accessreviews", accessReviewCtl.PostAccessReviews)
+
 		project := apiV1.Group("/project")
 		{
 			c := v1.NewProjectController()
@@ -148,7 +335,15 @@ func InitRouter() *gin.Engine {
 			project.GET(":name", c.GetProject)
 			project.POST("", c.PostProject)
 			project.PUT(":name", c.PutProject)
+			project.PATCH(":name", c.PatchProject)
 			project.DELETE(":name", c.DeleteProject)
+			project.GET(":name/revisions", c.GetProjectRevisions)
+			project.GET(":name/revisions/:revision", c.GetProjectRevision)
+			project.PUT(":name/revisions/:revision", c.PutProjectRevision)
+			project.DELETE(":name/revisions/:revision", c.DeleteProjectRevision)
+			project.GET(":name/revisions/:revision/diff/:target", c.DiffProjectRevision)
+			project.GET(":name/status", c.GetProjectStatus)
+			project.PUT(":name/status", c.PutProjectStatus)
 		}
 
 		topology := apiV1.Group("/topology")
@@ -157,6 +352,16 @@ func InitRouter() *gin.Engine {
 			topology.GET("", c.GetTopology)
 		}
 
+		admin := apiV1.Group("/admin")
+		{
+			c := extV1.NewBackupController()
+			admin.GET("/backup", c.GetBackup)
+			admin.POST("/restore", c.PostRestore)
+
+			encryptionCtl := extV1.NewEncryptionController()
+			admin.POST("/encryption/rotate", encryptionCtl.PostRotate)
+		}
+
 		revision := apiV1.Group("/revisions")
 		{
 			c := v1.NewRevisionController()
@@ -164,10 +369,12 @@ func InitRouter() *gin.Engine {
 			revision.POST("", c.PostRevision)
 			revision.GET(":name", c.GetRevision)
 			revision.PUT(":name", c.PutRevision)
+			revision.PATCH(":name", c.PatchRevision)
 			revision.DELETE(":name", c.DeleteRevision)
 		}
 	}
 	apiV2 := apiRouter.Group("/v2")
+	apiV2.Use(jwt.JWT())
 	{
 		ns := apiV2.Group("/namespaces/:namespace")
 		{
@@ -178,11 +385,15 @@ func InitRouter() *gin.Engine {
 				appInstance.POST("", c.PostAppInstance)
 				appInstance.GET(":name", c.GetAppInstance)
 				appInstance.PUT(":name", c.PutAppInstance)
+				appInstance.PATCH(":name", c.PatchAppInstance)
 				appInstance.DELETE(":name", c.DeleteAppInstance)
 				appInstance.GET(":name/revisions", c.GetAppInstanceRevisions)
 				appInstance.GET(":name/revisions/:revision", c.GetAppInstanceRevision)
 				appInstance.PUT(":name/revisions/:revision", c.PutAppInstanceRevision)
 				appInstance.DELETE(":name/revisions/:revision", c.DeleteAppInstanceRevision)
+				appInstance.GET(":name/revisions/:revision/diff/:target", c.DiffAppInstanceRevision)
+				appInstance.GET(":name/status", c.GetAppInstanceStatus)
+				appInstance.PUT(":name/status", c.PutAppInstanceStatus)
 			}
 		}
 
@@ -193,7 +404,15 @@ func InitRouter() *gin.Engine {
 			job.POST("", c.PostJob)
 			job.GET(":name", c.GetJob)
 			job.PUT(":name", c.PutJob)
+			job.PATCH(":name", c.PatchJob)
 			job.DELETE(":name", c.DeleteJob)
+			job.GET(":name/revisions", c.GetJobRevisions)
+			job.GET(":name/revisions/:revision", c.GetJobRevision)
+			job.PUT(":name/revisions/:revision", c.PutJobRevision)
+			job.DELETE(":name/revisions/:revision", c.DeleteJobRevision)
+			job.GET(":name/revisions/:revision/diff/:target", c.DiffJobRevision)
+			job.GET(":name/status", c.GetJobStatus)
+			job.PUT(":name/status", c.PutJobStatus)
 			job.GET(":name/log", c.GetJobLog)
 		}
 
@@ -204,7 +423,15 @@ func InitRouter() *gin.Engine {
 			host.POST("", c.PostHost)
 			host.GET(":name", c.GetHost)
 			host.PUT(":name", c.PutHost)
+			host.PATCH(":name", c.PatchHost)
 			host.DELETE(":name", c.DeleteHost)
+			host.GET(":name/revisions", c.GetHostRevisions)
+			host.GET(":name/revisions/:revision", c.GetHostRevision)
+			host.PUT(":name/revisions/:revision", c.PutHostRevision)
+			host.DELETE(":name/revisions/:revision", c.DeleteHostRevision)
+			host.GET(":name/revisions/:revision/diff/:target", c.DiffHostRevision)
+			host.GET(":name/status", c.GetHostStatus)
+			host.PUT(":name/status", c.PutHostStatus)
 		}
 	}
 